@@ -1,28 +1,85 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/utils"
 	"github.com/joho/godotenv"
 )
 
 type AppConfigurationMap struct {
-	Port                 int     // Port is the port number that the server will listen to.
-	IsProduction         bool    // IsProduction is a flag that indicates whether the application is running in production mode.
-	DbURI                string  // Database connection.
-	AccessTokenLifeTime  uint    // AccessTokenLifeTime is the lifetime of the access token in seconds.
-	RefreshTokenLifeTime uint    // RefreshTokenLifeTime is the lifetime of the refresh token in seconds.
-	PrivateKeyPath       string  // Path to the private key file.
-	PublicKeyPath        string  // Path to the public key file.
-	BaseURL              string  // BaseURL is the base URL of the application, used for generating absolute URLs.
-	RateLimitRPS         float64 // Global request-per-second limit (if <=0 disabled)
-	RateLimitBurst       int     // Burst size for rate limiter token bucket
+	Port                      int             // Port is the port number that the server will listen to.
+	IsProduction              bool            // IsProduction is a flag that indicates whether the application is running in production mode.
+	DbURI                     string          // Database connection.
+	DBRequestTimeout          time.Duration   // DBRequestTimeout bounds the database work of one request (0 disables).
+	AccessTokenLifeTime       uint            // AccessTokenLifeTime is the lifetime of the access token in seconds.
+	RefreshTokenLifeTime      uint            // RefreshTokenLifeTime is the lifetime of the refresh token in seconds.
+	PrivateKeyPath            string          // Path to the private key file.
+	PublicKeyPath             string          // Path to the public key file.
+	BaseURL                   string          // BaseURL is the base URL of the application, used for generating absolute URLs.
+	TrustedProxies            []string        // TrustedProxies are the proxy IPs/CIDRs whose X-Forwarded-For header is believed. Empty trusts none.
+	RateLimitDefault          RateLimitPolicy // RateLimitDefault applies to every route without a dedicated policy.
+	RateLimitAuth             RateLimitPolicy // RateLimitAuth applies to login, registration and password reset.
+	RateLimitBooking          RateLimitPolicy // RateLimitBooking applies to creating bookings.
+	RateLimitStudioRead       RateLimitPolicy // RateLimitStudioRead applies to public studio reads.
+	RateLimitStore            string          // RateLimitStore is where rate limit state is kept: "memory" (per instance) or "redis" (shared).
+	RedisURL                  string          // RedisURL is the redis:// or rediss:// URL of the Redis-compatible server.
+	NotifierDriver            string          // NotifierDriver selects the notification drivers: "live" (real providers) or "fake" (log only).
+	WhatsAppAPIURL            string          // WhatsAppAPIURL is the base URL of the WhatsApp Business Cloud API.
+	WhatsAppPhoneNumberID     string          // WhatsAppPhoneNumberID is the ID of the business phone number messages are sent from.
+	WhatsAppAccessToken       string          // WhatsAppAccessToken authenticates with the Cloud API. WhatsApp is disabled without it.
+	SMSGatewayURL             string          // SMSGatewayURL is the endpoint of the HTTP SMS gateway. SMS is disabled without it.
+	SMSGatewayToken           string          // SMSGatewayToken is sent as a bearer token to the SMS gateway.
+	SMSSender                 string          // SMSSender is the sender ID or number passed to the SMS gateway.
+	PasswordResetLifeTime     uint            // PasswordResetLifeTime is the lifetime of a password reset link in seconds.
+	EmailVerifyLifeTime       uint            // EmailVerifyLifeTime is the lifetime of an email verification link in seconds.
+	RequireVerifiedEmail      bool            // RequireVerifiedEmail blocks bookings from users who have not verified their email.
+	BookingPaymentHold        time.Duration   // BookingPaymentHold is how long a pending booking holds its slot while waiting for payment.
+	BookingExpiryInterval     time.Duration   // BookingExpiryInterval is how often overdue pending bookings are expired.
+	BookingCompletionInterval time.Duration   // BookingCompletionInterval is how often confirmed bookings whose session has ended are completed.
+	BookingMinDuration        time.Duration   // BookingMinDuration is the shortest session that can be booked.
+	AvailabilitySlotInterval  time.Duration   // AvailabilitySlotInterval is the step between the bookable start times offered by availability checks.
+	OutboxDispatchInterval    time.Duration   // OutboxDispatchInterval is how often queued emails are delivered.
+	OutboxMaxAttempts         int             // OutboxMaxAttempts is the number of delivery attempts before an email is marked dead.
+	JobPollInterval           time.Duration   // JobPollInterval is how often the job scheduler checks for due jobs.
+	ShutdownTimeout           time.Duration   // ShutdownTimeout is how long a shutdown waits for in-flight requests and jobs.
+	LoginMaxAttempts          int             // LoginMaxAttempts is the number of failed logins for one email before it is locked.
+	LoginMaxAttemptsPerIP     int             // LoginMaxAttemptsPerIP is the number of failed logins from one IP before it is locked.
+	LoginAttemptWindow        uint            // LoginAttemptWindow is how long, in seconds, failed attempts are remembered.
+	LoginLockoutDuration      uint            // LoginLockoutDuration is how long, in seconds, a lockout lasts.
+	LoginDelayBase            uint            // LoginDelayBase is the delay, in seconds, after the first failure; it doubles on every further failure.
+	LoginDelayMax             uint            // LoginDelayMax caps the progressive delay, in seconds.
+	TwoFactorLifeTime         uint            // TwoFactorLifeTime is the lifetime of a login 2FA challenge token in seconds.
+	RequireAdminTwoFactor     bool            // RequireAdminTwoFactor denies admin permissions until the account has 2FA enabled.
+	TOTPEncryptionKey         []byte          // TOTPEncryptionKey encrypts stored 2FA secrets with AES-GCM. Empty stores them in plaintext.
 }
 
+// RateLimitPolicy is a named request budget for one client (user or IP).
+// Routes sharing a policy name share the budget.
+type RateLimitPolicy struct {
+	Name   string        // Name identifies the budget.
+	Limit  int           // Limit is the number of requests allowed per Period, also the burst size. <= 0 disables the policy.
+	Period time.Duration // Period is the window over which Limit requests are allowed.
+}
+
+// Supported values of RateLimitStore.
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreRedis  = "redis"
+)
+
+// Supported values of NotifierDriver.
+const (
+	NotifierDriverLive = "live"
+	NotifierDriverFake = "fake"
+)
+
 // config is a global variable that stores the loaded application configuration.
 var config *AppConfigurationMap
 
@@ -62,6 +119,119 @@ func Load() {
 		RefreshTokenLifeTime = 86400 // Default value of 24 hours
 	}
 
+	PasswordResetLifeTime, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_LIFE_TIME"))
+	if err != nil || PasswordResetLifeTime <= 0 {
+		PasswordResetLifeTime = 3600 // Default value of 1 hour
+	}
+
+	EmailVerifyLifeTime, err := strconv.Atoi(os.Getenv("EMAIL_VERIFY_LIFE_TIME"))
+	if err != nil || EmailVerifyLifeTime <= 0 {
+		EmailVerifyLifeTime = 86400 // Default value of 24 hours
+	}
+
+	// Seconds a single request may spend on database work before its queries are cancelled
+	DBRequestTimeout, err := strconv.Atoi(os.Getenv("DB_REQUEST_TIMEOUT"))
+	if err != nil || DBRequestTimeout < 0 {
+		DBRequestTimeout = 10
+	}
+
+	requireVerifiedEmail := utils.SafeCompareString(os.Getenv("REQUIRE_VERIFIED_EMAIL"), "true")
+
+	BookingPaymentHold, err := strconv.Atoi(os.Getenv("BOOKING_PAYMENT_HOLD"))
+	if err != nil || BookingPaymentHold <= 0 {
+		BookingPaymentHold = 86400 // Default value of 24 hours
+	}
+
+	BookingExpiryInterval, err := strconv.Atoi(os.Getenv("BOOKING_EXPIRY_INTERVAL"))
+	if err != nil || BookingExpiryInterval <= 0 {
+		BookingExpiryInterval = 60 // Default value of 1 minute
+	}
+
+	BookingCompletionInterval, err := strconv.Atoi(os.Getenv("BOOKING_COMPLETION_INTERVAL"))
+	if err != nil || BookingCompletionInterval <= 0 {
+		BookingCompletionInterval = 300 // Default value of 5 minutes
+	}
+
+	BookingMinDuration, err := strconv.Atoi(os.Getenv("BOOKING_MIN_DURATION"))
+	if err != nil || BookingMinDuration <= 0 {
+		BookingMinDuration = 3600 // Default value of 1 hour
+	}
+
+	// Start times offered to customers are multiples of this from midnight, at least a minute apart
+	AvailabilitySlotInterval, err := strconv.Atoi(os.Getenv("AVAILABILITY_SLOT_INTERVAL"))
+	if err != nil || AvailabilitySlotInterval < 60 {
+		AvailabilitySlotInterval = 1800 // Default value of 30 minutes
+	}
+
+	OutboxDispatchInterval, err := strconv.Atoi(os.Getenv("OUTBOX_DISPATCH_INTERVAL"))
+	if err != nil || OutboxDispatchInterval <= 0 {
+		OutboxDispatchInterval = 15 // Default value of 15 seconds
+	}
+
+	OutboxMaxAttempts, err := strconv.Atoi(os.Getenv("OUTBOX_MAX_ATTEMPTS"))
+	if err != nil || OutboxMaxAttempts <= 0 {
+		OutboxMaxAttempts = 8 // Default value of 8 attempts, about an hour of retries
+	}
+
+	JobPollInterval, err := strconv.Atoi(os.Getenv("JOB_POLL_INTERVAL"))
+	if err != nil || JobPollInterval <= 0 {
+		JobPollInterval = 5 // Default value of 5 seconds
+	}
+
+	ShutdownTimeout, err := strconv.Atoi(os.Getenv("SHUTDOWN_TIMEOUT"))
+	if err != nil || ShutdownTimeout <= 0 {
+		ShutdownTimeout = 30 // Default value of 30 seconds
+	}
+
+	TwoFactorLifeTime, err := strconv.Atoi(os.Getenv("TWO_FACTOR_LIFE_TIME"))
+	if err != nil || TwoFactorLifeTime <= 0 {
+		TwoFactorLifeTime = 300 // Default value of 5 minutes
+	}
+
+	requireAdminTwoFactor := utils.SafeCompareString(os.Getenv("REQUIRE_ADMIN_2FA"), "true")
+
+	// Base64 encoded AES key (16, 24 or 32 bytes) for 2FA secrets at rest
+	var totpEncryptionKey []byte
+	if value := os.Getenv("TOTP_ENCRYPTION_KEY"); value != "" {
+		totpEncryptionKey, err = base64.StdEncoding.DecodeString(value)
+		if err != nil || (len(totpEncryptionKey) != 16 && len(totpEncryptionKey) != 24 && len(totpEncryptionKey) != 32) {
+			log.Fatalf("TOTP_ENCRYPTION_KEY must be a base64 encoded 16, 24 or 32 byte key")
+		}
+	} else if isProduction {
+		log.Printf("TOTP_ENCRYPTION_KEY is not set, 2FA secrets are stored unencrypted")
+	}
+
+	// Login brute-force protection
+	LoginMaxAttempts, err := strconv.Atoi(os.Getenv("LOGIN_MAX_ATTEMPTS"))
+	if err != nil || LoginMaxAttempts <= 0 {
+		LoginMaxAttempts = 5
+	}
+
+	LoginMaxAttemptsPerIP, err := strconv.Atoi(os.Getenv("LOGIN_MAX_ATTEMPTS_PER_IP"))
+	if err != nil || LoginMaxAttemptsPerIP <= 0 {
+		LoginMaxAttemptsPerIP = 20
+	}
+
+	LoginAttemptWindow, err := strconv.Atoi(os.Getenv("LOGIN_ATTEMPT_WINDOW"))
+	if err != nil || LoginAttemptWindow <= 0 {
+		LoginAttemptWindow = 900 // Default value of 15 minutes
+	}
+
+	LoginLockoutDuration, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_DURATION"))
+	if err != nil || LoginLockoutDuration <= 0 {
+		LoginLockoutDuration = 900 // Default value of 15 minutes
+	}
+
+	LoginDelayBase, err := strconv.Atoi(os.Getenv("LOGIN_DELAY_BASE"))
+	if err != nil || LoginDelayBase < 0 {
+		LoginDelayBase = 1
+	}
+
+	LoginDelayMax, err := strconv.Atoi(os.Getenv("LOGIN_DELAY_MAX"))
+	if err != nil || LoginDelayMax < 0 {
+		LoginDelayMax = 30
+	}
+
 	PrivateKeyPath := os.Getenv("PRIVATE_KEY")
 	if PrivateKeyPath == "" {
 		log.Fatalf("PRIVATE_KEY_PATH environment variable is not set, check your .env file")
@@ -77,30 +247,89 @@ func Load() {
 		BaseURL = fmt.Sprintf("http://localhost:%d", port)
 	}
 
-	// Global rate limiter configuration
-	rpsEnv := os.Getenv("RATE_LIMIT_RPS")
-	burstEnv := os.Getenv("RATE_LIMIT_BURST")
-	rps := 10.0 // sensible default
-	burst := 20 // sensible default
-	if v, err := strconv.ParseFloat(rpsEnv, 64); err == nil && v > 0 {
-		rps = v
+	// Client IPs feed login throttling and rate limits, so X-Forwarded-For is
+	// only read from these proxies; by default the connecting address is used
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
 	}
-	if v, err := strconv.Atoi(burstEnv); err == nil && v > 0 {
-		burst = v
+
+	// Per-client rate limit policies, written as "<requests>/<period>", e.g. "10/1m"
+	rateLimitDefault := loadRateLimitPolicy("default", "RATE_LIMIT_DEFAULT", "120/1m")
+	rateLimitAuth := loadRateLimitPolicy("auth", "RATE_LIMIT_AUTH", "10/1m")
+	rateLimitBooking := loadRateLimitPolicy("booking", "RATE_LIMIT_BOOKING", "5/1m")
+	rateLimitStudioRead := loadRateLimitPolicy("studio_read", "RATE_LIMIT_STUDIO_READ", "300/1m")
+
+	// Keep rate limit state in Redis when running more than one instance
+	rateLimitStore := strings.ToLower(os.Getenv("RATE_LIMIT_STORE"))
+	if rateLimitStore == "" {
+		rateLimitStore = RateLimitStoreMemory
+	}
+	if rateLimitStore != RateLimitStoreMemory && rateLimitStore != RateLimitStoreRedis {
+		log.Fatalf("RATE_LIMIT_STORE must be %q or %q", RateLimitStoreMemory, RateLimitStoreRedis)
+	}
+
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		redisURL = "redis://localhost:6379/0"
+	}
+
+	notifierDriver := strings.ToLower(os.Getenv("NOTIFIER_DRIVER"))
+	if notifierDriver == "" {
+		notifierDriver = NotifierDriverLive
+	}
+	if notifierDriver != NotifierDriverLive && notifierDriver != NotifierDriverFake {
+		log.Fatalf("NOTIFIER_DRIVER must be %q or %q", NotifierDriverLive, NotifierDriverFake)
 	}
 
 	// Set global variable config
 	config = &AppConfigurationMap{
-		Port:                 port,
-		IsProduction:         isProduction,
-		DbURI:                loadDatabaseConfig(),
-		AccessTokenLifeTime:  uint(AccessTokenLifeTime),
-		RefreshTokenLifeTime: uint(RefreshTokenLifeTime),
-		PrivateKeyPath:       PrivateKeyPath,
-		PublicKeyPath:        PublicKeyPath,
-		BaseURL:              BaseURL,
-		RateLimitRPS:         rps,
-		RateLimitBurst:       burst,
+		Port:                      port,
+		IsProduction:              isProduction,
+		DbURI:                     loadDatabaseConfig(),
+		DBRequestTimeout:          time.Duration(DBRequestTimeout) * time.Second,
+		AccessTokenLifeTime:       uint(AccessTokenLifeTime),
+		RefreshTokenLifeTime:      uint(RefreshTokenLifeTime),
+		PrivateKeyPath:            PrivateKeyPath,
+		PublicKeyPath:             PublicKeyPath,
+		BaseURL:                   BaseURL,
+		TrustedProxies:            trustedProxies,
+		RateLimitDefault:          rateLimitDefault,
+		RateLimitAuth:             rateLimitAuth,
+		RateLimitBooking:          rateLimitBooking,
+		RateLimitStudioRead:       rateLimitStudioRead,
+		RateLimitStore:            rateLimitStore,
+		RedisURL:                  redisURL,
+		NotifierDriver:            notifierDriver,
+		WhatsAppAPIURL:            os.Getenv("WHATSAPP_API_URL"),
+		WhatsAppPhoneNumberID:     os.Getenv("WHATSAPP_PHONE_NUMBER_ID"),
+		WhatsAppAccessToken:       os.Getenv("WHATSAPP_ACCESS_TOKEN"),
+		SMSGatewayURL:             os.Getenv("SMS_GATEWAY_URL"),
+		SMSGatewayToken:           os.Getenv("SMS_GATEWAY_TOKEN"),
+		SMSSender:                 os.Getenv("SMS_SENDER"),
+		PasswordResetLifeTime:     uint(PasswordResetLifeTime),
+		EmailVerifyLifeTime:       uint(EmailVerifyLifeTime),
+		RequireVerifiedEmail:      requireVerifiedEmail,
+		BookingPaymentHold:        time.Duration(BookingPaymentHold) * time.Second,
+		BookingExpiryInterval:     time.Duration(BookingExpiryInterval) * time.Second,
+		BookingCompletionInterval: time.Duration(BookingCompletionInterval) * time.Second,
+		BookingMinDuration:        time.Duration(BookingMinDuration) * time.Second,
+		AvailabilitySlotInterval:  time.Duration(AvailabilitySlotInterval) * time.Second,
+		OutboxDispatchInterval:    time.Duration(OutboxDispatchInterval) * time.Second,
+		OutboxMaxAttempts:         OutboxMaxAttempts,
+		JobPollInterval:           time.Duration(JobPollInterval) * time.Second,
+		ShutdownTimeout:           time.Duration(ShutdownTimeout) * time.Second,
+		LoginMaxAttempts:          LoginMaxAttempts,
+		LoginMaxAttemptsPerIP:     LoginMaxAttemptsPerIP,
+		LoginAttemptWindow:        uint(LoginAttemptWindow),
+		LoginLockoutDuration:      uint(LoginLockoutDuration),
+		LoginDelayBase:            uint(LoginDelayBase),
+		LoginDelayMax:             uint(LoginDelayMax),
+		TwoFactorLifeTime:         uint(TwoFactorLifeTime),
+		RequireAdminTwoFactor:     requireAdminTwoFactor,
+		TOTPEncryptionKey:         totpEncryptionKey,
 	}
 }
 
@@ -116,6 +345,53 @@ func loadDatabaseConfig() string {
 	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s TimeZone=%s", host, user, pass, name, port, timeZone)
 }
 
+// loadRateLimitPolicy reads a "<requests>/<period>" policy such as "10/1m" or "100/s" from the environment.
+// "0" disables the policy; an invalid value falls back to the default.
+func loadRateLimitPolicy(name, key, defaultValue string) RateLimitPolicy {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+
+	policy, err := parseRateLimitPolicy(name, value)
+	if err != nil {
+		log.Printf("Invalid %s %q (%v), using %q", key, value, err, defaultValue)
+		policy, _ = parseRateLimitPolicy(name, defaultValue)
+	}
+
+	return policy
+}
+
+// parseRateLimitPolicy parses a "<requests>/<period>" policy string.
+func parseRateLimitPolicy(name, value string) (RateLimitPolicy, error) {
+	if value == "0" {
+		return RateLimitPolicy{Name: name}, nil
+	}
+
+	limitPart, periodPart, found := strings.Cut(value, "/")
+	if !found {
+		return RateLimitPolicy{}, fmt.Errorf("expected <requests>/<period>")
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(limitPart))
+	if err != nil || limit < 0 {
+		return RateLimitPolicy{}, fmt.Errorf("invalid request count")
+	}
+
+	// Allow a bare unit such as "100/s"
+	periodPart = strings.TrimSpace(periodPart)
+	if periodPart != "" && (periodPart[0] < '0' || periodPart[0] > '9') {
+		periodPart = "1" + periodPart
+	}
+
+	period, err := time.ParseDuration(periodPart)
+	if err != nil || period <= 0 {
+		return RateLimitPolicy{}, fmt.Errorf("invalid period")
+	}
+
+	return RateLimitPolicy{Name: name, Limit: limit, Period: period}, nil
+}
+
 // getFromEnv retrieves an environment variable by key and exits the program if it's not set.
 func getFromEnv(key string) string {
 	value := os.Getenv(key)
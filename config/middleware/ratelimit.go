@@ -1,50 +1,83 @@
 package middleware
 
 import (
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/ratelimit"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/token"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
 )
 
-// GlobalRateLimiter returns a middleware that limits the total incoming request rate.
-// If rps <= 0 the limiter is disabled (no throttling).
-func GlobalRateLimiter(rps float64, burst int, skip map[string]struct{}) gin.HandlerFunc {
-	if rps <= 0 { // disabled
-		return func(c *gin.Context) { c.Next() }
-	}
-	if burst <= 0 { // ensure sane burst
-		burst = int(rps)
-		if burst < 1 {
-			burst = 1
-		}
-	}
-	lim := rate.NewLimiter(rate.Limit(rps), burst)
-
+// RateLimiter returns a middleware that limits requests per client.
+// Requests carrying a valid access token are counted per user ID, all others per client IP.
+// The policy is looked up by "METHOD /route/path" (e.g. "POST /auth/login"), falling back to def.
+// Policies with Limit <= 0 are not enforced. If the store fails the request is let through.
+func RateLimiter(store ratelimit.Store, def config.RateLimitPolicy, routes map[string]config.RateLimitPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip OPTIONS preflight quickly
 		if c.Request.Method == http.MethodOptions {
 			c.Next()
 			return
 		}
-		// Skip specific full paths if configured
-		if skip != nil {
-			if _, ok := skip[c.FullPath()]; ok {
-				c.Next()
-				return
-			}
+
+		policy, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			policy = def
+		}
+		if policy.Limit <= 0 || policy.Period <= 0 {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), policy.Name+":"+rateLimitClientKey(c), policy.Limit, policy.Period)
+		if err != nil {
+			log.Printf("rate limit store error: %v", err)
+			c.Next()
+			return
 		}
-		if !lim.Allow() {
-			// Optional small retry-after hint (approximate: next token)
-			retryAfter := time.Until(time.Now().Add(lim.Reserve().Delay())) / time.Millisecond
-			c.Header("Retry-After", "1") // seconds (coarse)
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		c.Header("RateLimit-Policy", strconv.Itoa(policy.Limit)+";w="+strconv.Itoa(ceilSeconds(policy.Period)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error":          "rate limit exceeded",
-				"retry_after_ms": retryAfter,
+				"success": false,
+				"error":   "rate limit exceeded, try again later",
+				"code":    "RATE_LIMITED",
 			})
 			return
 		}
+
 		c.Next()
 	}
 }
+
+// rateLimitClientKey identifies the caller: the user ID from a valid access token, otherwise the client IP.
+// The token signature is checked so a forged user ID can't be used to dodge the per-IP limit.
+// ClientIP only reads X-Forwarded-For from the router's trusted proxies (TRUSTED_PROXIES),
+// so a forged header can't be rotated for a fresh budget either.
+func rateLimitClientKey(c *gin.Context) string {
+	if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		if user, err := token.ValidateAccessToken(strings.TrimPrefix(authHeader, "Bearer ")); err == nil {
+			return "user:" + strconv.Itoa(user.ID)
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds rounds a duration up to whole seconds, as used by the RateLimit and Retry-After headers.
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+func newRateLimitedRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	store := ratelimit.NewMemoryStore(time.Minute)
+	t.Cleanup(func() { store.Close() })
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatalf("SetTrustedProxies: %v", err)
+	}
+	r.Use(RateLimiter(store, config.RateLimitPolicy{}, map[string]config.RateLimitPolicy{
+		"POST /auth/login": {Name: "auth", Limit: 2, Period: time.Minute},
+	}))
+	r.POST("/auth/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func login(r *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimiterIgnoresForgedForwardedFor(t *testing.T) {
+	r := newRateLimitedRouter(t, nil)
+
+	// Rotating the header must not buy a fresh budget when no proxy is trusted
+	for i, forwardedFor := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		got := login(r, "198.51.100.7:40000", forwardedFor)
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if got != want {
+			t.Fatalf("request %d with X-Forwarded-For %s: status %d, want %d", i+1, forwardedFor, got, want)
+		}
+	}
+}
+
+func TestRateLimiterUsesForwardedForFromTrustedProxy(t *testing.T) {
+	r := newRateLimitedRouter(t, []string{"10.0.0.0/8"})
+
+	// Behind the proxy every client has its own budget
+	for _, forwardedFor := range []string{"203.0.113.1", "203.0.113.1", "203.0.113.2", "203.0.113.2"} {
+		if got := login(r, "10.0.0.5:40000", forwardedFor); got != http.StatusOK {
+			t.Fatalf("X-Forwarded-For %s: status %d, want %d", forwardedFor, got, http.StatusOK)
+		}
+	}
+	if got := login(r, "10.0.0.5:40000", "203.0.113.1"); got != http.StatusTooManyRequests {
+		t.Fatalf("third request of 203.0.113.1: status %d, want %d", got, http.StatusTooManyRequests)
+	}
+
+	// A direct client can't claim to be someone else
+	if got := login(r, "198.51.100.7:40000", "203.0.113.9"); got != http.StatusOK {
+		t.Fatalf("direct client: status %d, want %d", got, http.StatusOK)
+	}
+	if got := login(r, "198.51.100.7:40000", "203.0.113.10"); got != http.StatusOK {
+		t.Fatalf("direct client: status %d, want %d", got, http.StatusOK)
+	}
+	if got := login(r, "198.51.100.7:40000", "203.0.113.11"); got != http.StatusTooManyRequests {
+		t.Fatalf("direct client with a new X-Forwarded-For: status %d, want %d", got, http.StatusTooManyRequests)
+	}
+}
@@ -1,14 +1,30 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/RaFYWStud/BackendBookingStudio/config"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/token"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
+// SessionValidator re-checks a parsed access token against server-side state
+// (revocation, token version, current role) and returns the up-to-date user data.
+type SessionValidator func(ctx context.Context, claims *token.UserAuthToken) (*token.UserAuthToken, error)
+
+// sessionValidator is set once at startup by SetSessionValidator and used by Auth().
+var sessionValidator SessionValidator
+
+// SetSessionValidator registers the validator Auth() uses to reject revoked sessions.
+func SetSessionValidator(validator SessionValidator) {
+    sessionValidator = validator
+}
+
 // Auth middleware validates JWT token
 func Auth() gin.HandlerFunc {
     return func(ctx *gin.Context) {
@@ -37,14 +53,9 @@ func Auth() gin.HandlerFunc {
 
         tokenString := parts[1]
 
-        // Parse and validate token
-        token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-            // TODO: Use RSA public key from config
-            // For now, use the same secret key used in service
-            return []byte("temporary-secret-key-replace-with-rsa"), nil
-        })
-
-        if err != nil || !token.Valid {
+        // Parse and validate token with the RSA public key
+        user, err := token.ValidateAccessToken(tokenString)
+        if err != nil {
             ctx.JSON(http.StatusUnauthorized, gin.H{
                 "success": false,
                 "error":   "Invalid or expired token",
@@ -54,19 +65,46 @@ func Auth() gin.HandlerFunc {
             return
         }
 
-        // Extract claims and store in context
-        if claims, ok := token.Claims.(jwt.MapClaims); ok {
-            ctx.Set("user_id", int(claims["user_id"].(float64)))
-            ctx.Set("user_email", claims["email"].(string))
-            ctx.Set("user_role", claims["role"].(string))
+        // Reject revoked tokens and pick up the current role from the database
+        if sessionValidator != nil {
+            user, err = sessionValidator(ctx.Request.Context(), user)
+            if err != nil {
+                var messageErr errs.MessageError
+                if errors.As(err, &messageErr) {
+                    switch messageErr.Status() {
+                    case http.StatusUnauthorized:
+                        ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+                            "success": false,
+                            "error":   messageErr.Message(),
+                            "code":    "TOKEN_REVOKED",
+                        })
+                        return
+                    case http.StatusForbidden:
+                        ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+                            "success": false,
+                            "error":   messageErr.Message(),
+                            "code":    "ACCOUNT_SUSPENDED",
+                        })
+                        return
+                    }
+                }
+                ctx.AbortWithStatusJSON(http.StatusInternalServerError, errs.InternalServerError("failed to verify session"))
+                return
+            }
         }
 
+        // Store claims in context
+        ctx.Set("user_id", user.ID)
+        ctx.Set("user_email", user.Email)
+        ctx.Set("user_role", user.Role)
+        ctx.Set("auth_token", user)
+
         ctx.Next()
     }
 }
 
-// AdminOnly middleware checks if user has admin role
-func AdminOnly() gin.HandlerFunc {
+// RequirePermission middleware checks if the user's role grants every given permission
+func RequirePermission(perms ...permission.Permission) gin.HandlerFunc {
     return func(ctx *gin.Context) {
         role, exists := ctx.Get("user_role")
         if !exists {
@@ -74,11 +112,24 @@ func AdminOnly() gin.HandlerFunc {
             return
         }
 
-        if role != "admin" {
-            ctx.AbortWithStatusJSON(http.StatusForbidden, errs.Forbidden("admin access required"))
+        if !permission.HasAll(role.(string), perms...) {
+            ctx.AbortWithStatusJSON(http.StatusForbidden, errs.Forbidden("you don't have permission to perform this action"))
             return
         }
 
+        // Admins must enroll 2FA before using their permissions when it is mandatory
+        if config.Get().RequireAdminTwoFactor && role == string(permission.RoleAdmin) {
+            session, _ := ctx.Get("auth_token")
+            if user, ok := session.(*token.UserAuthToken); !ok || !user.TwoFactor {
+                ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+                    "success": false,
+                    "error":   "two-factor authentication must be enabled for admin accounts",
+                    "code":    "TWO_FACTOR_REQUIRED",
+                })
+                return
+            }
+        }
+
         ctx.Next()
     }
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package errs
 
 import (
 	"net/http"
+	"time"
 )
 
 // Response is a struct that represents the response structure for the API.
@@ -35,6 +36,19 @@ func (e *ErrorData) Message() string {
 	return e.ErrMessage
 }
 
+// CodedError is a MessageError that also carries a machine-readable error code
+// and, optionally, how long the client should wait before retrying.
+type CodedError struct {
+	ErrorData
+	ErrCode    string
+	RetryAfter time.Duration
+}
+
+// Code returns the machine-readable error code.
+func (e *CodedError) Code() string {
+	return e.ErrCode
+}
+
 // Client Error Responses (400s)
 // BadRequest returns a MessageError representing a 400 Bad Request error with a custom message.
 func BadRequest(message string) MessageError {
@@ -72,6 +86,29 @@ func NotFound(message string) MessageError {
 	}
 }
 
+// Conflict returns a MessageError representing a 409 Conflict error with a custom message.
+func Conflict(message string) MessageError {
+	return &ErrorData{
+		ErrMessage: message,
+		ErrStatus:  http.StatusConflict,
+		ErrError:   "Conflict",
+	}
+}
+
+// TooManyRequests returns a CodedError representing a 429 Too Many Requests error.
+// The code tells clients why they were throttled and retryAfter how long to wait.
+func TooManyRequests(message, code string, retryAfter time.Duration) *CodedError {
+	return &CodedError{
+		ErrorData: ErrorData{
+			ErrMessage: message,
+			ErrStatus:  http.StatusTooManyRequests,
+			ErrError:   "Too Many Requests",
+		},
+		ErrCode:    code,
+		RetryAfter: retryAfter,
+	}
+}
+
 // Client Error Responses (500s)
 // InternalServerError returns a MessageError representing a 500 Internal Server Error with a custom message.
 func InternalServerError(message string) MessageError {
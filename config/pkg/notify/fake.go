@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// fakeLimit is the number of messages a Fake keeps; older ones are dropped.
+const fakeLimit = 100
+
+// Fake is a Notifier that delivers nothing. It logs every message and keeps the most recent
+// ones in memory, for tests and for running the server without real providers.
+type Fake struct {
+	channel Channel
+
+	mu   sync.Mutex
+	sent []Message
+	err  error
+}
+
+// NewFake returns a Fake for channel.
+func NewFake(channel Channel) *Fake {
+	return &Fake{channel: channel}
+}
+
+// Send implements Notifier. It returns the error set by FailWith, if any.
+func (f *Fake) Send(_ context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+
+	f.sent = append(f.sent, msg)
+	if len(f.sent) > fakeLimit {
+		f.sent = f.sent[len(f.sent)-fakeLimit:]
+	}
+
+	log.Printf("📨 [Fake %s] To %s: %s", f.channel, msg.To, firstNonEmpty(msg.Subject, msg.Body))
+	return nil
+}
+
+// Sent returns a copy of the messages sent so far, oldest first.
+func (f *Fake) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Message(nil), f.sent...)
+}
+
+// FailWith makes every following Send return err, or succeed again when err is nil.
+func (f *Fake) FailWith(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
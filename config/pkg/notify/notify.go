@@ -0,0 +1,66 @@
+// Package notify delivers messages to users over pluggable channels (email, WhatsApp, SMS).
+//
+// Every channel has a Notifier. Drivers talk to a real provider (SMTP, the WhatsApp Business
+// Cloud API, an SMS HTTP gateway); the Fake driver keeps messages in memory for tests and
+// local development.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Channel names a delivery channel. It is stored with queued messages and as the user's preference.
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelWhatsApp Channel = "whatsapp"
+	ChannelSMS      Channel = "sms"
+)
+
+// IsValidChannel reports whether channel is one of the known channels.
+func IsValidChannel(channel string) bool {
+	switch Channel(channel) {
+	case ChannelEmail, ChannelWhatsApp, ChannelSMS:
+		return true
+	}
+	return false
+}
+
+// Message is one notification for one recipient.
+type Message struct {
+	To      string // To is an email address for email, an E.164 phone number ("+628123456789") otherwise.
+	Subject string // Subject is only used by email.
+	Body    string // Body is HTML for email and plain text for the other channels.
+}
+
+// Notifier sends messages over one channel.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// httpTimeout bounds one request to an HTTP provider when ctx has no earlier deadline.
+const httpTimeout = 15 * time.Second
+
+// doJSON sends an HTTP request and turns a non-2xx response into an error that includes the start of the body.
+func doJSON(ctx context.Context, client *http.Client, req *http.Request) error {
+	ctx, cancel := context.WithTimeout(ctx, httpTimeout)
+	defer cancel()
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s responded %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
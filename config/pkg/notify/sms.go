@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// SMSNotifier sends text messages through a generic HTTP SMS gateway.
+//
+// It POSTs {"to": "+628123456789", "from": sender, "message": "..."} as JSON to the gateway URL,
+// with "Authorization: Bearer <token>" when a token is set. Any 2xx response counts as accepted.
+type SMSNotifier struct {
+	client *http.Client
+	url    string
+	token  string
+	sender string
+}
+
+// NewSMSNotifier returns an SMSNotifier posting to the gateway at url.
+func NewSMSNotifier(url, token, sender string) *SMSNotifier {
+	return &SMSNotifier{
+		client: &http.Client{},
+		url:    url,
+		token:  token,
+		sender: sender,
+	}
+}
+
+// Send implements Notifier.
+func (n *SMSNotifier) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(map[string]string{
+		"to":      msg.To,
+		"from":    n.sender,
+		"message": msg.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	return doJSON(ctx, n.client, req)
+}
@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"sort"
+	"time"
+)
+
+// smtpTimeout bounds one SMTP session when ctx has no earlier deadline.
+const smtpTimeout = 30 * time.Second
+
+// ErrNotConfigured is returned by SMTPNotifier.Send when the host, port, sender or password is missing.
+var ErrNotConfigured = errors.New("notify: SMTP is not configured")
+
+// SMTPNotifier sends HTML email through an SMTP server with PLAIN auth.
+type SMTPNotifier struct {
+	host     string
+	port     string
+	from     string
+	password string
+}
+
+// NewSMTPNotifier returns an SMTPNotifier that authenticates as from.
+func NewSMTPNotifier(host, port, from, password string) *SMTPNotifier {
+	return &SMTPNotifier{host: host, port: port, from: from, password: password}
+}
+
+// Configured reports whether the notifier has everything it needs to send.
+func (n *SMTPNotifier) Configured() bool {
+	return n.host != "" && n.port != "" && n.from != "" && n.password != ""
+}
+
+// Send implements Notifier. It returns ErrNotConfigured without a complete SMTP configuration,
+// so the outbox keeps the message instead of dropping it. The SMTP session is cancelled with ctx.
+func (n *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	if !n.Configured() {
+		return fmt.Errorf("failed to send email to %s: %w", msg.To, ErrNotConfigured)
+	}
+
+	headers := map[string]string{
+		"From":         n.from,
+		"To":           msg.To,
+		"Subject":      msg.Subject,
+		"MIME-Version": "1.0",
+		"Content-Type": "text/html; charset=UTF-8",
+	}
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	message := ""
+	for _, k := range keys {
+		message += fmt.Sprintf("%s: %s\r\n", k, headers[k])
+	}
+	message += "\r\n" + msg.Body
+
+	if err := n.sendMail(ctx, msg.To, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", msg.To, err)
+	}
+
+	log.Printf("✅ Email sent to %s: %s", msg.To, msg.Subject)
+	return nil
+}
+
+// sendMail is smtp.SendMail over a connection that is closed when ctx is done or the session
+// takes longer than smtpTimeout.
+func (n *SMTPNotifier) sendMail(ctx context.Context, to string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(n.host, n.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// The deadline covers a timeout, closing the connection covers a cancelled ctx
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return contextError(ctx, err)
+		}
+	}
+	if err := client.Auth(smtp.PlainAuth("", n.from, n.password, n.host)); err != nil {
+		return contextError(ctx, err)
+	}
+	if err := client.Mail(n.from); err != nil {
+		return contextError(ctx, err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return contextError(ctx, err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return contextError(ctx, err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return contextError(ctx, err)
+	}
+	if err := w.Close(); err != nil {
+		return contextError(ctx, err)
+	}
+	return contextError(ctx, client.Quit())
+}
+
+// contextError reports ctx's error instead of the I/O error caused by closing the connection.
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w: %v", ctx.Err(), err)
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpServer is a minimal SMTP server accepting one session without STARTTLS.
+// It returns the listener's port and a channel receiving the DATA of the session.
+func smtpServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	data := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.Fields(line + " ")[0])
+			switch cmd {
+			case "EHLO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case "AUTH":
+				reply("235 Authentication succeeded")
+			case "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var body strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					body.WriteString(line)
+				}
+				data <- body.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	return port, data
+}
+
+func TestSMTPNotifierNotConfigured(t *testing.T) {
+	tests := []struct {
+		name                       string
+		host, port, from, password string
+	}{
+		{"no host", "", "587", "noreply@example.com", "secret"},
+		{"no port", "smtp.example.com", "", "noreply@example.com", "secret"},
+		{"no sender", "smtp.example.com", "587", "", "secret"},
+		{"no password", "smtp.example.com", "587", "noreply@example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewSMTPNotifier(tt.host, tt.port, tt.from, tt.password)
+			if n.Configured() {
+				t.Errorf("Configured() = true")
+			}
+			if err := n.Send(context.Background(), Message{To: "customer@example.com"}); !errors.Is(err, ErrNotConfigured) {
+				t.Errorf("Send = %v, want ErrNotConfigured", err)
+			}
+		})
+	}
+}
+
+func TestSMTPNotifierSend(t *testing.T) {
+	port, data := smtpServer(t)
+	n := NewSMTPNotifier("127.0.0.1", port, "noreply@example.com", "secret")
+
+	err := n.Send(context.Background(), Message{To: "customer@example.com", Subject: "Booking Created", Body: "<p>Hello</p>"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	select {
+	case got := <-data:
+		for _, want := range []string{"To: customer@example.com\r\n", "Subject: Booking Created\r\n", "\r\n\r\n<p>Hello</p>"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("message %q does not contain %q", got, want)
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server received no message")
+	}
+}
+
+func TestSMTPNotifierHonorsContext(t *testing.T) {
+	// A server that accepts connections but never greets
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	n := NewSMTPNotifier("127.0.0.1", port, "noreply@example.com", "secret")
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := n.Send(ctx, Message{To: "customer@example.com"})
+		if err == nil {
+			t.Fatal("Send succeeded against a silent server")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Send returned after %v, want it to stop at the ctx deadline", elapsed)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		err := n.Send(ctx, Message{To: "customer@example.com"})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Send = %v, want context.Canceled", err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// DefaultWhatsAppAPIURL is the Graph API base URL used when none is configured.
+const DefaultWhatsAppAPIURL = "https://graph.facebook.com/v20.0"
+
+// WhatsAppNotifier sends text messages through the WhatsApp Business Cloud API.
+//
+// Meta only delivers free-form text inside the 24 hour customer service window, i.e. after
+// the customer has messaged the business number; outside it the API rejects the message
+// and the outbox retries it as a failed delivery.
+type WhatsAppNotifier struct {
+	client        *http.Client
+	apiURL        string
+	phoneNumberID string
+	accessToken   string
+}
+
+// NewWhatsAppNotifier returns a WhatsAppNotifier sending from the business phone number phoneNumberID.
+func NewWhatsAppNotifier(apiURL, phoneNumberID, accessToken string) *WhatsAppNotifier {
+	if apiURL == "" {
+		apiURL = DefaultWhatsAppAPIURL
+	}
+	return &WhatsAppNotifier{
+		client:        &http.Client{},
+		apiURL:        strings.TrimRight(apiURL, "/"),
+		phoneNumberID: phoneNumberID,
+		accessToken:   accessToken,
+	}
+}
+
+// Send implements Notifier.
+func (n *WhatsAppNotifier) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                strings.TrimPrefix(msg.To, "+"),
+		"type":              "text",
+		"text": map[string]interface{}{
+			"preview_url": true,
+			"body":        msg.Body,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.apiURL+"/"+n.phoneNumberID+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+n.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	return doJSON(ctx, n.client, req)
+}
@@ -0,0 +1,68 @@
+package permission
+
+// Role is the value stored in users.role.
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleStaff    Role = "staff"
+	RoleAdmin    Role = "admin"
+)
+
+// Permission names a single action a role may perform, in "resource:action" form.
+type Permission string
+
+const (
+	StudioWrite         Permission = "studio:write"          // create and update studios, including prices
+	StudioDelete        Permission = "studio:delete"         // delete studios
+	BookingReadAll      Permission = "booking:read_all"      // list and view bookings of every customer
+	BookingUpdateStatus Permission = "booking:update_status" // confirm payments, check in, cancel
+	UserManage          Permission = "user:manage"           // list users, change roles, suspend accounts
+	OutboxManage        Permission = "outbox:manage"         // inspect and resend queued notification emails
+	ClosureManage       Permission = "closure:manage"        // block studios for maintenance, holidays and events
+)
+
+// rolePermissions maps every role to the permissions it is granted.
+// Customers act only on their own resources and need no permission.
+var rolePermissions = map[Role][]Permission{
+	RoleCustomer: {},
+	RoleStaff: {
+		BookingReadAll,
+		BookingUpdateStatus,
+	},
+	RoleAdmin: {
+		StudioWrite,
+		StudioDelete,
+		BookingReadAll,
+		BookingUpdateStatus,
+		UserManage,
+		OutboxManage,
+		ClosureManage,
+	},
+}
+
+// IsValidRole reports whether role is one of the known roles.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[Role(role)]
+	return ok
+}
+
+// Has reports whether role is granted the given permission.
+func Has(role string, perm Permission) bool {
+	for _, granted := range rolePermissions[Role(role)] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll reports whether role is granted every one of the given permissions.
+func HasAll(role string, perms ...Permission) bool {
+	for _, perm := range perms {
+		if !Has(role, perm) {
+			return false
+		}
+	}
+	return true
+}
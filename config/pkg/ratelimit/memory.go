@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps rate limit state in process memory. Every server instance has its own budget.
+type MemoryStore struct {
+	mu   sync.Mutex
+	tats map[string]time.Time
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewMemoryStore returns a MemoryStore that evicts idle keys every sweepInterval.
+func NewMemoryStore(sweepInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		tats: make(map[string]time.Time),
+		stop: make(chan struct{}),
+	}
+	go s.sweep(sweepInterval)
+	return s
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(_ context.Context, key string, limit int, period time.Duration) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, newTAT := gcra(s.tats[key], time.Now(), limit, period)
+	if result.Allowed {
+		s.tats[key] = newTAT
+	}
+	return result, nil
+}
+
+// Close stops the sweeper.
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+// sweep periodically drops keys whose budget is full again; they behave exactly like unseen keys.
+func (s *MemoryStore) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, tat := range s.tats {
+				if !tat.After(now) {
+					delete(s.tats, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package ratelimit implements GCRA (generic cell rate algorithm) request limiting
+// on top of a pluggable Store, so several server instances can share one budget.
+//
+// Each key only stores its theoretical arrival time (TAT): the moment at which its
+// budget will be completely refilled. Limit requests may arrive at once; after that
+// one request is allowed every Period/Limit.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store counts requests per key.
+type Store interface {
+	// Take counts one request for key against a budget of limit requests per period
+	// and reports whether it is allowed. Denied requests do not use up the budget.
+	Take(ctx context.Context, key string, limit int, period time.Duration) (Result, error)
+
+	// Close releases the resources held by the store.
+	Close() error
+}
+
+// Result is the outcome of counting one request.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // ResetAfter is the time until the budget is completely refilled.
+	RetryAfter time.Duration // RetryAfter is the time until the next request would be allowed, zero when allowed.
+}
+
+// gcra applies one request to the stored TAT (zero for an unseen key) and returns
+// the result together with the TAT to store if the request is allowed.
+func gcra(tat, now time.Time, limit int, period time.Duration) (Result, time.Time) {
+	interval := max(period/time.Duration(limit), 1)
+
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(interval)
+	allowAt := newTAT.Add(-period)
+
+	if now.Before(allowAt) {
+		return Result{
+			Allowed:    false,
+			Limit:      limit,
+			Remaining:  0,
+			ResetAfter: tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}, tat
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      limit,
+		Remaining:  int((period - newTAT.Sub(now)) / interval),
+		ResetAfter: newTAT.Sub(now),
+	}, newTAT
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGCRA(t *testing.T) {
+	start := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	limit, period := 3, time.Minute // one request every 20s, bursts of 3
+
+	// Each step takes a request at start+at; the TAT carries over between steps
+	steps := []struct {
+		at        time.Duration
+		allowed   bool
+		remaining int
+		reset     time.Duration
+		retry     time.Duration
+	}{
+		{0, true, 2, 20 * time.Second, 0},
+		{0, true, 1, 40 * time.Second, 0},
+		{0, true, 0, 60 * time.Second, 0},
+		{0, false, 0, 60 * time.Second, 20 * time.Second},               // burst used up
+		{5 * time.Second, false, 0, 55 * time.Second, 15 * time.Second}, // denied requests don't move the TAT
+		{20 * time.Second, true, 0, 60 * time.Second, 0},                // one interval later one request fits again
+		{30 * time.Second, false, 0, 50 * time.Second, 10 * time.Second},
+		{3 * time.Minute, true, 2, 20 * time.Second, 0}, // idle key behaves like a new one
+	}
+
+	var tat time.Time
+	for i, step := range steps {
+		result, newTAT := gcra(tat, start.Add(step.at), limit, period)
+		if result.Allowed != step.allowed || result.Remaining != step.remaining ||
+			result.ResetAfter != step.reset || result.RetryAfter != step.retry || result.Limit != limit {
+			t.Fatalf("step %d at +%v: got %+v, want allowed=%v remaining=%d reset=%v retry=%v",
+				i, step.at, result, step.allowed, step.remaining, step.reset, step.retry)
+		}
+		if !result.Allowed && !newTAT.Equal(tat) {
+			t.Fatalf("step %d: denied request changed the TAT from %v to %v", i, tat, newTAT)
+		}
+		tat = newTAT
+	}
+}
+
+func TestGCRAIntervalBelowResolution(t *testing.T) {
+	now := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+
+	// More requests than nanoseconds in the period must not divide by zero
+	result, tat := gcra(time.Time{}, now, 10, time.Nanosecond)
+	if !result.Allowed || !tat.Equal(now.Add(time.Nanosecond)) {
+		t.Fatalf("got %+v with TAT %v", result, tat)
+	}
+}
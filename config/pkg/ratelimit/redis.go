@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gcraScript is gcra as a Lua script, so the read-modify-write is atomic across instances.
+// Times are in microseconds and taken from the server clock, so instances don't need synced clocks.
+// KEYS[1] is the key, ARGV[1] the period in microseconds and ARGV[2] the limit.
+// It returns {allowed, remaining, reset after, retry after}.
+const gcraScript = `
+if redis.replicate_commands then redis.replicate_commands() end
+
+local period = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local interval = math.max(math.floor(period / limit), 1)
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if tat == nil or tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - period
+
+if now < allow_at then
+	return {0, 0, tat - now, allow_at - now}
+end
+
+redis.call('SET', KEYS[1], string.format('%d', new_tat), 'PX', math.max(math.ceil((new_tat - now) / 1000), 1))
+return {1, math.floor((period - (new_tat - now)) / interval), new_tat - now, 0}
+`
+
+// RedisStore keeps rate limit state in Redis (or a Redis-compatible server), shared by every instance.
+// Keys expire on their own once the budget is full again.
+type RedisStore struct {
+	client    *redisClient
+	prefix    string
+	scriptSHA string
+}
+
+// NewRedisStore connects to the server at rawURL ("redis://[[user]:password@]host[:port][/db]",
+// or rediss:// for TLS) and prefixes every key with prefix.
+func NewRedisStore(ctx context.Context, rawURL, prefix string) (*RedisStore, error) {
+	opts, err := parseRedisURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+
+	sum := sha1.Sum([]byte(gcraScript))
+	s := &RedisStore{
+		client:    newRedisClient(opts),
+		prefix:    prefix,
+		scriptSHA: hex.EncodeToString(sum[:]),
+	}
+
+	// Fail fast on a wrong address or password
+	if _, err := s.client.do(ctx, "PING"); err != nil {
+		s.client.Close()
+		return nil, fmt.Errorf("redis ping: %w", err)
+	}
+
+	return s, nil
+}
+
+// Take implements Store.
+func (s *RedisStore) Take(ctx context.Context, key string, limit int, period time.Duration) (Result, error) {
+	args := []string{
+		s.scriptSHA,
+		"1",
+		s.prefix + key,
+		strconv.FormatInt(period.Microseconds(), 10),
+		strconv.Itoa(limit),
+	}
+
+	reply, err := s.client.do(ctx, append([]string{"EVALSHA"}, args...)...)
+	if rerr, ok := err.(redisError); ok && strings.HasPrefix(string(rerr), "NOSCRIPT") {
+		// First call on this server, or its script cache was flushed
+		args[0] = gcraScript
+		reply, err = s.client.do(ctx, append([]string{"EVAL"}, args...)...)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return Result{}, fmt.Errorf("redis: unexpected script reply %v", reply)
+	}
+	nums := make([]int64, len(values))
+	for i, v := range values {
+		if nums[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("redis: unexpected script reply %v", reply)
+		}
+	}
+
+	return Result{
+		Allowed:    nums[0] == 1,
+		Limit:      limit,
+		Remaining:  int(nums[1]),
+		ResetAfter: time.Duration(nums[2]) * time.Microsecond,
+		RetryAfter: time.Duration(nums[3]) * time.Microsecond,
+	}, nil
+}
+
+// Close closes the connections to the server.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,186 @@
+package ratelimit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisStore(t *testing.T, rawURL string) *RedisStore {
+	t.Helper()
+
+	store, err := NewRedisStore(context.Background(), rawURL, "ratelimit:")
+	if err != nil {
+		t.Fatalf("NewRedisStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestRedisStoreTake(t *testing.T) {
+	server := miniredis.RunT(t)
+	start := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	server.SetTime(start)
+	store := newTestRedisStore(t, "redis://"+server.Addr())
+	ctx := context.Background()
+
+	for i, remaining := range []int{1, 0} {
+		result, err := store.Take(ctx, "auth:ip:198.51.100.7", 2, time.Minute)
+		if err != nil {
+			t.Fatalf("Take %d: %v", i+1, err)
+		}
+		if !result.Allowed || result.Remaining != remaining || result.Limit != 2 || result.RetryAfter != 0 {
+			t.Fatalf("Take %d: got %+v, want allowed with %d remaining", i+1, result, remaining)
+		}
+	}
+
+	result, err := store.Take(ctx, "auth:ip:198.51.100.7", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("Take 3: %v", err)
+	}
+	want := Result{Allowed: false, Limit: 2, Remaining: 0, ResetAfter: time.Minute, RetryAfter: 30 * time.Second}
+	if result != want {
+		t.Fatalf("Take 3: got %+v, want %+v", result, want)
+	}
+
+	// Other keys have their own budget
+	if result, err := store.Take(ctx, "auth:ip:198.51.100.8", 2, time.Minute); err != nil || !result.Allowed {
+		t.Fatalf("other key: got %+v, %v", result, err)
+	}
+
+	// The key only lives until the budget is full again
+	if ttl := server.TTL("ratelimit:auth:ip:198.51.100.7"); ttl != time.Minute {
+		t.Fatalf("TTL = %v, want %v", ttl, time.Minute)
+	}
+
+	// One interval later one request fits again
+	server.SetTime(start.Add(30 * time.Second))
+	result, err = store.Take(ctx, "auth:ip:198.51.100.7", 2, time.Minute)
+	if err != nil {
+		t.Fatalf("Take 4: %v", err)
+	}
+	if !result.Allowed || result.Remaining != 0 || result.ResetAfter != time.Minute {
+		t.Fatalf("Take 4: got %+v", result)
+	}
+}
+
+func TestRedisStoreMatchesGCRA(t *testing.T) {
+	server := miniredis.RunT(t)
+	start := time.Date(2025, 11, 20, 10, 0, 0, 0, time.UTC)
+	store := newTestRedisStore(t, "redis://"+server.Addr())
+
+	// The script and the in-memory implementation must agree step for step
+	var tat time.Time
+	for _, at := range []time.Duration{0, 0, 0, 0, time.Second, 7 * time.Second, 12 * time.Second, 13 * time.Second, 50 * time.Second} {
+		now := start.Add(at)
+		server.SetTime(now)
+
+		got, err := store.Take(context.Background(), "k", 4, 40*time.Second)
+		if err != nil {
+			t.Fatalf("Take at +%v: %v", at, err)
+		}
+		want, newTAT := gcra(tat, now, 4, 40*time.Second)
+		if got != want {
+			t.Fatalf("at +%v: redis %+v, gcra %+v", at, got, want)
+		}
+		if want.Allowed {
+			tat = newTAT
+		}
+	}
+}
+
+func TestRedisStoreLoadsScriptOnNoScript(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := newTestRedisStore(t, "redis://"+server.Addr())
+	ctx := context.Background()
+
+	scriptLoaded := func() bool {
+		t.Helper()
+		reply, err := store.client.do(ctx, "SCRIPT", "EXISTS", store.scriptSHA)
+		if err != nil {
+			t.Fatalf("SCRIPT EXISTS: %v", err)
+		}
+		return reflect.DeepEqual(reply, []interface{}{int64(1)})
+	}
+
+	// EVALSHA of an unknown script is answered with a NOSCRIPT error reply
+	_, err := store.client.do(ctx, "EVALSHA", store.scriptSHA, "1", "k", "1000000", "1")
+	if rerr, ok := err.(redisError); !ok || !strings.HasPrefix(string(rerr), "NOSCRIPT") {
+		t.Fatalf("EVALSHA before the first Take: %v, want a NOSCRIPT redisError", err)
+	}
+
+	// The first Take falls back to EVAL, which caches the script for the next EVALSHA
+	if result, err := store.Take(ctx, "a", 5, time.Minute); err != nil || !result.Allowed {
+		t.Fatalf("first Take: got %+v, %v", result, err)
+	}
+	if !scriptLoaded() {
+		t.Fatal("script not cached after the first Take")
+	}
+	if result, err := store.Take(ctx, "a", 5, time.Minute); err != nil || result.Remaining != 3 {
+		t.Fatalf("EVALSHA Take: got %+v, %v", result, err)
+	}
+
+	// A flushed script cache is reloaded transparently
+	server.FlushAll()
+	if _, err := store.client.do(ctx, "SCRIPT", "FLUSH"); err != nil {
+		t.Fatalf("SCRIPT FLUSH: %v", err)
+	}
+	if scriptLoaded() {
+		t.Fatal("script still cached after SCRIPT FLUSH")
+	}
+	if result, err := store.Take(ctx, "a", 5, time.Minute); err != nil || result.Remaining != 4 {
+		t.Fatalf("Take after SCRIPT FLUSH: got %+v, %v", result, err)
+	}
+}
+
+func TestRedisStoreAuthAndDatabase(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("s3cret")
+
+	if _, err := NewRedisStore(context.Background(), "redis://:wrong@"+server.Addr(), "ratelimit:"); err == nil {
+		t.Fatal("NewRedisStore with a wrong password succeeded")
+	}
+	if _, err := NewRedisStore(context.Background(), "redis://"+server.Addr(), "ratelimit:"); err == nil {
+		t.Fatal("NewRedisStore without a password succeeded")
+	}
+
+	store := newTestRedisStore(t, "redis://:s3cret@"+server.Addr()+"/2")
+	if _, err := store.Take(context.Background(), "k", 1, time.Minute); err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if !server.DB(2).Exists("ratelimit:k") {
+		t.Fatal("key not written to database 2")
+	}
+	if server.DB(0).Exists("ratelimit:k") {
+		t.Fatal("key written to database 0")
+	}
+}
+
+func TestRedisStoreErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	store := newTestRedisStore(t, "redis://"+server.Addr())
+	ctx := context.Background()
+
+	// Error replies leave the connection usable
+	_, err := store.client.do(ctx, "NOSUCHCOMMAND")
+	if _, ok := err.(redisError); !ok {
+		t.Fatalf("unknown command: %v, want a redisError", err)
+	}
+	if reply, err := store.client.do(ctx, "PING"); err != nil || reply != "PONG" {
+		t.Fatalf("PING after an error reply: %v, %v", reply, err)
+	}
+
+	server.Close()
+	if _, err := store.Take(ctx, "k", 1, time.Minute); err == nil {
+		t.Fatal("Take succeeded with the server down")
+	}
+
+	store.Close()
+	if _, err := store.client.do(ctx, "PING"); err == nil {
+		t.Fatal("closed client still sends commands")
+	}
+}
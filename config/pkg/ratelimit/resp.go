@@ -0,0 +1,284 @@
+package ratelimit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file holds a minimal RESP2 client, just enough to run the limiter script
+// against Redis or a Redis-compatible server (Valkey, KeyDB, Dragonfly ...).
+
+const (
+	redisDialTimeout = 5 * time.Second
+	redisIOTimeout   = 2 * time.Second
+	redisMaxIdle     = 16
+)
+
+// redisError is an error reply sent by the server, e.g. "NOSCRIPT No matching script".
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// redisOptions is the parsed form of a redis:// or rediss:// URL.
+type redisOptions struct {
+	addr     string
+	username string
+	password string
+	db       int
+	tls      *tls.Config
+}
+
+// parseRedisURL parses "redis://[[user]:password@]host[:port][/db]". The rediss scheme enables TLS.
+func parseRedisURL(rawURL string) (redisOptions, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redisOptions{}, err
+	}
+
+	var opts redisOptions
+	switch u.Scheme {
+	case "redis":
+	case "rediss":
+		opts.tls = &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}
+	default:
+		return redisOptions{}, fmt.Errorf("unsupported scheme %q, expected redis or rediss", u.Scheme)
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if host == "" {
+		host = "localhost"
+	}
+	if port == "" {
+		port = "6379"
+	}
+	opts.addr = net.JoinHostPort(host, port)
+
+	if u.User != nil {
+		opts.username = u.User.Username()
+		opts.password, _ = u.User.Password()
+	}
+
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		opts.db, err = strconv.Atoi(path)
+		if err != nil || opts.db < 0 {
+			return redisOptions{}, fmt.Errorf("invalid database %q", path)
+		}
+	}
+
+	return opts, nil
+}
+
+// redisConn is one connection to the server.
+type redisConn struct {
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// redisClient is a small connection pool that sends commands one at a time per connection.
+type redisClient struct {
+	opts redisOptions
+
+	mu     sync.Mutex
+	idle   []*redisConn
+	closed bool
+}
+
+func newRedisClient(opts redisOptions) *redisClient {
+	return &redisClient{opts: opts}
+}
+
+// do sends one command and returns its reply: string, int64, []interface{} or nil.
+// Error replies are returned as redisError.
+func (c *redisClient) do(ctx context.Context, args ...string) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, args...)
+	if err != nil {
+		// The connection state is unknown after an I/O or protocol error
+		cn.conn.Close()
+		return nil, err
+	}
+
+	c.put(cn)
+	if rerr, ok := reply.(redisError); ok {
+		return nil, rerr
+	}
+	return reply, nil
+}
+
+// Close closes every idle connection. Connections in use are closed when returned.
+func (c *redisClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for _, cn := range c.idle {
+		cn.conn.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+func (c *redisClient) get(ctx context.Context) (*redisConn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, errors.New("redis client is closed")
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	return c.dial(ctx)
+}
+
+func (c *redisClient) put(cn *redisConn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || len(c.idle) >= redisMaxIdle {
+		cn.conn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// dial opens a connection, then authenticates and selects the database if configured.
+func (c *redisClient) dial(ctx context.Context) (*redisConn, error) {
+	dialer := net.Dialer{Timeout: redisDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.opts.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.opts.tls != nil {
+		tlsConn := tls.Client(conn, c.opts.tls)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	cn := &redisConn{conn: conn, rd: bufio.NewReader(conn)}
+
+	var setup [][]string
+	if c.opts.password != "" {
+		if c.opts.username != "" {
+			setup = append(setup, []string{"AUTH", c.opts.username, c.opts.password})
+		} else {
+			setup = append(setup, []string{"AUTH", c.opts.password})
+		}
+	}
+	if c.opts.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.opts.db)})
+	}
+
+	for _, args := range setup {
+		reply, err := cn.do(ctx, args...)
+		if err == nil {
+			if rerr, ok := reply.(redisError); ok {
+				err = rerr
+			}
+		}
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis %s: %w", args[0], err)
+		}
+	}
+
+	return cn, nil
+}
+
+// do writes one command and reads its reply within the context deadline.
+func (cn *redisConn) do(ctx context.Context, args ...string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisIOTimeout)
+	}
+	if err := cn.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n")
+		buf.WriteString(arg)
+		buf.WriteString("\r\n")
+	}
+	if _, err := cn.conn.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return readReply(cn.rd)
+}
+
+// readReply reads one RESP2 reply.
+func readReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return redisError(body), nil
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(rd); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
@@ -0,0 +1,264 @@
+// Package scheduler runs named periodic jobs inside the server process.
+//
+// Job state (next run, lease, consecutive failures) lives in a Store shared by every
+// server instance, so each run happens on exactly one instance. A failed run is retried
+// with exponential backoff, never waiting longer than the job's regular interval.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	minRetryDelay = 10 * time.Second // minRetryDelay is the wait before retrying a job after its first failure.
+	storeTimeout  = 10 * time.Second // storeTimeout bounds one Store call.
+)
+
+// ErrJobRunning is returned by RunNow when another instance holds the job's lease.
+var ErrJobRunning = errors.New("job is already running")
+
+// Store keeps the state of jobs.
+type Store interface {
+	// Register creates the job's state if it doesn't exist yet, first due at firstRun.
+	Register(ctx context.Context, name string, firstRun time.Time) error
+
+	// ClaimDue leases the job to owner until leaseUntil if it is due at now and not leased by anyone.
+	// It reports whether the lease was taken and how many runs in a row have failed so far.
+	ClaimDue(ctx context.Context, name, owner string, now, leaseUntil time.Time) (failures int, ok bool, err error)
+
+	// Claim is ClaimDue without the due check, for running a job on demand.
+	Claim(ctx context.Context, name, owner string, now, leaseUntil time.Time) (failures int, ok bool, err error)
+
+	// Succeed releases owner's lease, clears the failure count and schedules the next run.
+	Succeed(ctx context.Context, name, owner string, finishedAt, nextRun time.Time) error
+
+	// Fail releases owner's lease, counts one more failure and schedules the retry.
+	Fail(ctx context.Context, name, owner string, finishedAt, retryAt time.Time, errMsg string) error
+}
+
+// Job is a named piece of work run every Interval.
+type Job struct {
+	Name     string
+	Interval time.Duration // Interval is the time between the end of a successful run and the next start.
+	Timeout  time.Duration // Timeout bounds one run, zero means Interval.
+
+	// Run does the work. It should be idempotent: a run cut short by a crash is run again.
+	Run func(ctx context.Context) error
+}
+
+func (j Job) timeout() time.Duration {
+	if j.Timeout > 0 {
+		return j.Timeout
+	}
+	return j.Interval
+}
+
+// Scheduler polls the store and starts jobs when they are due.
+type Scheduler struct {
+	store Store
+	owner string
+	poll  time.Duration
+	jobs  map[string]Job
+	now   func() time.Time // now is the clock for job state, replaced in tests
+
+	stop     chan struct{}
+	loopDone chan struct{}
+	inFlight sync.WaitGroup
+}
+
+// New returns a Scheduler that checks for due jobs every poll interval.
+func New(store Store, poll time.Duration) *Scheduler {
+	host, _ := os.Hostname()
+
+	return &Scheduler{
+		store: store,
+		owner: fmt.Sprintf("%s-%d", host, os.Getpid()),
+		poll:  poll,
+		jobs:  make(map[string]Job),
+		now:   time.Now,
+	}
+}
+
+// Add registers a job. It must be called before Start.
+func (s *Scheduler) Add(job Job) {
+	if job.Name == "" || job.Interval <= 0 || job.Run == nil {
+		panic("scheduler: job needs a name, a positive interval and a run function")
+	}
+	if _, ok := s.jobs[job.Name]; ok {
+		panic("scheduler: duplicate job " + job.Name)
+	}
+	s.jobs[job.Name] = job
+}
+
+// Names returns the names of the registered jobs, sorted.
+func (s *Scheduler) Names() []string {
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Start records every job in the store, due immediately if it is new, and starts polling.
+func (s *Scheduler) Start(ctx context.Context) error {
+	now := s.now()
+	for _, name := range s.Names() {
+		if err := s.store.Register(ctx, name, now); err != nil {
+			return fmt.Errorf("register job %s: %w", name, err)
+		}
+	}
+
+	s.stop = make(chan struct{})
+	s.loopDone = make(chan struct{})
+	go s.loop()
+
+	return nil
+}
+
+// Shutdown stops starting new runs and waits for the runs in flight to finish,
+// or for ctx to be done. Runs are not cancelled; each is still bounded by its timeout.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s.stop == nil {
+		return nil
+	}
+	close(s.stop)
+	<-s.loopDone
+
+	drained := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs still running: %w", ctx.Err())
+	}
+}
+
+// RunNow runs a job once in the calling goroutine, whether or not it is due, and records
+// the outcome like a scheduled run. It returns the job's error.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	job, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("unknown job %q, available: %s", name, strings.Join(s.Names(), ", "))
+	}
+
+	if err := s.store.Register(ctx, name, s.now()); err != nil {
+		return fmt.Errorf("register job %s: %w", name, err)
+	}
+
+	now := s.now()
+	failures, ok, err := s.store.Claim(ctx, name, s.owner, now, now.Add(job.timeout()+storeTimeout))
+	if err != nil {
+		return fmt.Errorf("claim job %s: %w", name, err)
+	}
+	if !ok {
+		return ErrJobRunning
+	}
+
+	return s.execute(job, failures)
+}
+
+func (s *Scheduler) loop() {
+	defer close(s.loopDone)
+
+	ticker := time.NewTicker(s.poll)
+	defer ticker.Stop()
+
+	for {
+		s.startDue()
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startDue claims every due job and runs it in its own goroutine.
+func (s *Scheduler) startDue() {
+	for _, name := range s.Names() {
+		job := s.jobs[name]
+
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		now := s.now()
+		failures, ok, err := s.store.ClaimDue(ctx, name, s.owner, now, now.Add(job.timeout()+storeTimeout))
+		cancel()
+
+		if err != nil {
+			log.Printf("❌ [Job] Failed to claim %s: %v", name, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		s.inFlight.Add(1)
+		go func() {
+			defer s.inFlight.Done()
+			s.execute(job, failures)
+		}()
+	}
+}
+
+// execute runs the job and records its outcome. failures is the number of failed runs before this one.
+func (s *Scheduler) execute(job Job, failures int) error {
+	started := s.now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), job.timeout())
+	err := run(ctx, job)
+	cancel()
+
+	finished := s.now()
+	recordCtx, recordCancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer recordCancel()
+
+	if err != nil {
+		retryAt := finished.Add(retryDelay(failures+1, job.Interval))
+		log.Printf("❌ [Job] %s failed (attempt %d), retrying at %s: %v", job.Name, failures+1, retryAt.Format(time.RFC3339), err)
+
+		if recordErr := s.store.Fail(recordCtx, job.Name, s.owner, finished, retryAt, err.Error()); recordErr != nil {
+			log.Printf("❌ [Job] Failed to record failure of %s: %v", job.Name, recordErr)
+		}
+		return err
+	}
+
+	if recordErr := s.store.Succeed(recordCtx, job.Name, s.owner, finished, finished.Add(job.Interval)); recordErr != nil {
+		log.Printf("❌ [Job] Failed to record success of %s: %v", job.Name, recordErr)
+	}
+	if failures > 0 {
+		log.Printf("✅ [Job] %s succeeded after %d failed attempt(s) in %s", job.Name, failures, finished.Sub(started))
+	}
+	return nil
+}
+
+// run calls job.Run, turning a panic into an error so one broken job can't take the server down.
+func run(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ [Job] %s panicked: %v\n%s", job.Name, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// retryDelay doubles the wait after every failure, starting at minRetryDelay and capped at interval.
+func retryDelay(failures int, interval time.Duration) time.Duration {
+	delay := minRetryDelay << min(failures-1, 16)
+	return min(delay, interval)
+}
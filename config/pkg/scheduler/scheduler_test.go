@@ -0,0 +1,408 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+type jobState struct {
+	nextRun     time.Time
+	lockedBy    string
+	lockedUntil time.Time
+	failures    int
+	lastError   string
+}
+
+// memStore is a Store in memory with the lease rules of the job repository.
+type memStore struct {
+	mu   sync.Mutex
+	jobs map[string]*jobState
+}
+
+func newMemStore() *memStore {
+	return &memStore{jobs: map[string]*jobState{}}
+}
+
+func (m *memStore) Register(_ context.Context, name string, firstRun time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.jobs[name]; !ok {
+		m.jobs[name] = &jobState{nextRun: firstRun}
+	}
+	return nil
+}
+
+func (m *memStore) ClaimDue(_ context.Context, name, owner string, now, leaseUntil time.Time) (int, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.jobs[name]
+	if job == nil || job.nextRun.After(now) {
+		return 0, false, nil
+	}
+	return job.claim(owner, now, leaseUntil)
+}
+
+func (m *memStore) Claim(_ context.Context, name, owner string, now, leaseUntil time.Time) (int, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.jobs[name]
+	if job == nil {
+		return 0, false, nil
+	}
+	return job.claim(owner, now, leaseUntil)
+}
+
+func (j *jobState) claim(owner string, now, leaseUntil time.Time) (int, bool, error) {
+	if !j.lockedUntil.IsZero() && !j.lockedUntil.Before(now) {
+		return 0, false, nil
+	}
+	j.lockedBy, j.lockedUntil = owner, leaseUntil
+	return j.failures, true, nil
+}
+
+func (m *memStore) Succeed(_ context.Context, name, owner string, _, nextRun time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job := m.jobs[name]; job != nil && job.lockedBy == owner {
+		job.lockedBy, job.lockedUntil = "", time.Time{}
+		job.failures, job.lastError = 0, ""
+		job.nextRun = nextRun
+	}
+	return nil
+}
+
+func (m *memStore) Fail(_ context.Context, name, owner string, _, retryAt time.Time, errMsg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job := m.jobs[name]; job != nil && job.lockedBy == owner {
+		job.lockedBy, job.lockedUntil = "", time.Time{}
+		job.failures++
+		job.lastError = errMsg
+		job.nextRun = retryAt
+	}
+	return nil
+}
+
+func (m *memStore) get(t *testing.T, name string) jobState {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job := m.jobs[name]
+	if job == nil {
+		t.Fatalf("job %s is not registered", name)
+	}
+	return *job
+}
+
+func newTestScheduler(store Store, clock *fakeClock, owner string) *Scheduler {
+	s := New(store, 5*time.Millisecond)
+	s.now = clock.now
+	s.owner = owner
+	return s
+}
+
+// blockingJob starts a run on started and returns once release is closed.
+func blockingJob(started chan<- struct{}, release <-chan struct{}) Job {
+	return Job{
+		Name:     "blocking",
+		Interval: time.Hour,
+		Timeout:  time.Minute,
+		Run: func(context.Context) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		},
+	}
+}
+
+func waitStarted(t *testing.T, started <-chan struct{}) {
+	t.Helper()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job did not start")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		interval time.Duration
+		want     time.Duration
+	}{
+		{1, time.Hour, 10 * time.Second},
+		{2, time.Hour, 20 * time.Second},
+		{3, time.Hour, 40 * time.Second},
+		{9, time.Hour, 2560 * time.Second},
+		{10, time.Hour, time.Hour}, // capped at the interval
+		{1000, time.Hour, time.Hour},
+		{2, 15 * time.Second, 15 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.failures, tt.interval); got != tt.want {
+			t.Errorf("retryDelay(%d, %v) = %v, want %v", tt.failures, tt.interval, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteRetriesWithBackoff(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	store := newMemStore()
+	s := newTestScheduler(store, clock, "a")
+	ctx := context.Background()
+
+	outcomes := []error{errors.New("boom"), errors.New("boom again"), nil}
+	job := Job{
+		Name:     "flaky",
+		Interval: time.Hour,
+		Run: func(context.Context) error {
+			err := outcomes[0]
+			outcomes = outcomes[1:]
+			return err
+		},
+	}
+	if err := store.Register(ctx, job.Name, clock.now()); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	steps := []struct {
+		wait      time.Duration // after the previous run, before claiming
+		failures  int
+		nextRun   time.Duration // after the run finished
+		lastError string
+	}{
+		{0, 1, 10 * time.Second, "boom"},
+		{10 * time.Second, 2, 20 * time.Second, "boom again"},
+		{20 * time.Second, 0, time.Hour, ""},
+	}
+
+	for i, step := range steps {
+		// Not due before the retry
+		if step.wait > 0 {
+			if _, ok, _ := store.ClaimDue(ctx, job.Name, "a", clock.now(), clock.now().Add(time.Minute)); ok {
+				t.Fatalf("step %d: claimed before the retry was due", i)
+			}
+			clock.advance(step.wait)
+		}
+
+		failures, ok, err := store.ClaimDue(ctx, job.Name, "a", clock.now(), clock.now().Add(time.Minute))
+		if err != nil || !ok {
+			t.Fatalf("step %d: ClaimDue = %v, %v", i, ok, err)
+		}
+		if failures != i {
+			t.Errorf("step %d: claimed with %d failures, want %d", i, failures, i)
+		}
+		if err := s.execute(job, failures); (err != nil) != (step.lastError != "") {
+			t.Fatalf("step %d: execute = %v", i, err)
+		}
+
+		state := store.get(t, job.Name)
+		if state.failures != step.failures || state.lastError != step.lastError || state.lockedBy != "" {
+			t.Errorf("step %d: state %+v, want %d failures with error %q and no lease", i, state, step.failures, step.lastError)
+		}
+		if want := clock.now().Add(step.nextRun); !state.nextRun.Equal(want) {
+			t.Errorf("step %d: next run %s, want %s", i, state.nextRun, want)
+		}
+	}
+}
+
+func TestExecuteRecoversPanic(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	store := newMemStore()
+	s := newTestScheduler(store, clock, "a")
+	ctx := context.Background()
+
+	job := Job{Name: "broken", Interval: time.Hour, Run: func(context.Context) error { panic("nil map") }}
+	if err := store.Register(ctx, job.Name, clock.now()); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, ok, err := store.Claim(ctx, job.Name, "a", clock.now(), clock.now().Add(time.Minute)); err != nil || !ok {
+		t.Fatalf("Claim = %v, %v", ok, err)
+	}
+
+	err := s.execute(job, 0)
+	if err == nil || !strings.Contains(err.Error(), "panic: nil map") {
+		t.Fatalf("execute = %v, want the panic as an error", err)
+	}
+	if state := store.get(t, job.Name); state.failures != 1 || state.lockedBy != "" || !strings.Contains(state.lastError, "nil map") {
+		t.Errorf("state %+v, want one recorded failure and no lease", state)
+	}
+}
+
+func TestStartDueLease(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	store := newMemStore()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	// Two instances sharing the store
+	a := newTestScheduler(store, clock, "a")
+	b := newTestScheduler(store, clock, "b")
+	job := blockingJob(started, release)
+	a.Add(job)
+	b.Add(job)
+	if err := store.Register(context.Background(), job.Name, clock.now()); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	a.startDue()
+	waitStarted(t, started)
+	state := store.get(t, job.Name)
+	if want := clock.now().Add(job.Timeout + storeTimeout); state.lockedBy != "a" || !state.lockedUntil.Equal(want) {
+		t.Fatalf("lease %s until %s, want a until %s", state.lockedBy, state.lockedUntil, want)
+	}
+
+	// The lease is held, so the other instance doesn't start the job
+	b.startDue()
+	if state := store.get(t, job.Name); state.lockedBy != "a" {
+		t.Fatalf("lease taken by %s while a held it", state.lockedBy)
+	}
+
+	// A run that outlives its lease, e.g. on a crashed instance, is taken over
+	clock.advance(job.Timeout + storeTimeout + time.Second)
+	b.startDue()
+	waitStarted(t, started)
+	if state := store.get(t, job.Name); state.lockedBy != "b" {
+		t.Fatalf("lease held by %s after it expired, want b", state.lockedBy)
+	}
+
+	close(release)
+	a.inFlight.Wait()
+	b.inFlight.Wait()
+
+	// Only the owner of the lease records the outcome
+	state = store.get(t, job.Name)
+	if state.lockedBy != "" || !state.nextRun.Equal(clock.now().Add(job.Interval)) {
+		t.Errorf("state %+v, want the lease released and the next run an interval later", state)
+	}
+}
+
+func TestShutdownDrainsInFlight(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	store := newMemStore()
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	s := newTestScheduler(store, clock, "a")
+	s.Add(blockingJob(started, release))
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitStarted(t, started)
+
+	done := make(chan error, 1)
+	go func() { done <- s.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown = %v while a job was running", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return after the job finished")
+	}
+
+	if state := store.get(t, "blocking"); state.lockedBy != "" || state.failures != 0 {
+		t.Errorf("state %+v, want the run recorded as a success", state)
+	}
+}
+
+func TestShutdownGivesUp(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	s := newTestScheduler(newMemStore(), clock, "a")
+	s.Add(blockingJob(started, release))
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitStarted(t, started)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Shutdown = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	s.inFlight.Wait()
+}
+
+func TestRunNow(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)}
+	store := newMemStore()
+	ctx := context.Background()
+
+	var runs atomic.Int32
+	s := newTestScheduler(store, clock, "a")
+	s.Add(Job{Name: "report", Interval: time.Hour, Run: func(context.Context) error {
+		runs.Add(1)
+		return errors.New("mail server down")
+	}})
+
+	if err := s.RunNow(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "report") {
+		t.Errorf("RunNow of an unknown job = %v, want an error listing the jobs", err)
+	}
+
+	// Not due yet, but run anyway; the job's error is returned and recorded
+	if err := store.Register(ctx, "report", clock.now().Add(time.Hour)); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := s.RunNow(ctx, "report"); err == nil || err.Error() != "mail server down" {
+		t.Errorf("RunNow = %v, want the job's error", err)
+	}
+	if state := store.get(t, "report"); runs.Load() != 1 || state.failures != 1 {
+		t.Errorf("%d runs and state %+v, want one recorded failure", runs.Load(), state)
+	}
+
+	// Leased by another instance
+	if _, ok, _ := store.Claim(ctx, "report", "b", clock.now(), clock.now().Add(time.Minute)); !ok {
+		t.Fatal("Claim by b failed")
+	}
+	if err := s.RunNow(ctx, "report"); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("RunNow = %v, want ErrJobRunning", err)
+	}
+	if runs.Load() != 1 {
+		t.Errorf("job ran %d times, want once", runs.Load())
+	}
+}
@@ -1,36 +1,134 @@
 package token
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Token types stored in the "typ" claim, so a refresh token can never be used as an access token and vice versa.
+const (
+	typeAccess      = "access"
+	typeRefresh     = "refresh"
+	typeEmailVerify = "email_verify"
+	typeTwoFactor   = "2fa_challenge"
+)
+
+// GenerateToken creates an RS256 signed access token carrying the given user data.
+// Every access token gets a random jti so it can be revoked individually.
 func GenerateToken(data *UserAuthToken) (string, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", err
+	}
+
 	// Create a new token using RS256 algorithm
 	token := jwt.New(jwt.SigningMethodRS256)
 
 	// Set token claims
 	claims := token.Claims.(jwt.MapClaims)
 	claims["data"] = data
+	claims["typ"] = typeAccess
+	claims["jti"] = jti
 	claims["iss"] = "myApp"
 	claims["iat"] = time.Now().Unix()
-	claims["exp"] = time.Now().Add(time.Duration(jwtConfig.jwtLifeTime) * time.Second).Unix()
+	claims["exp"] = time.Now().Add(AccessTokenLifeTime()).Unix()
 
 	// Sign the token and return
 	return token.SignedString(jwtConfig.privateKey)
 }
 
+// GenerateRefreshToken creates an RS256 signed refresh token for the given user ID.
+// Every token carries a random jti so two tokens issued in the same second never collide.
 func GenerateRefreshToken(id int) (string, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", err
+	}
+
 	token := jwt.New(jwt.SigningMethodRS256)
 
 	claims := token.Claims.(jwt.MapClaims)
 	claims["data"] = map[string]int{
 		"id": id,
 	}
+	claims["typ"] = typeRefresh
+	claims["jti"] = jti
 	claims["iss"] = "myApp"
 	claims["iat"] = time.Now().Unix()
-	claims["exp"] = time.Now().Add(time.Duration(jwtConfig.jwtLifeTime) * time.Second).Unix()
+	claims["exp"] = time.Now().Add(RefreshTokenLifeTime()).Unix()
 
 	return token.SignedString(jwtConfig.privateKey)
 }
+
+// GenerateEmailVerificationToken creates an RS256 signed token proving ownership of the given email.
+// The email is embedded so the link stops working once the user changes their address.
+func GenerateEmailVerificationToken(id int, email string) (string, error) {
+	token := jwt.New(jwt.SigningMethodRS256)
+
+	claims := token.Claims.(jwt.MapClaims)
+	claims["data"] = map[string]any{
+		"id":    id,
+		"email": email,
+	}
+	claims["typ"] = typeEmailVerify
+	claims["iss"] = "myApp"
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(EmailVerifyLifeTime()).Unix()
+
+	return token.SignedString(jwtConfig.privateKey)
+}
+
+// GenerateTwoFactorChallengeToken creates an RS256 signed token proving the password step of a
+// login succeeded for the given user ID. It carries a jti so it can be used only once.
+func GenerateTwoFactorChallengeToken(id int) (string, error) {
+	jti, err := randomID()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.New(jwt.SigningMethodRS256)
+
+	claims := token.Claims.(jwt.MapClaims)
+	claims["data"] = map[string]int{
+		"id": id,
+	}
+	claims["typ"] = typeTwoFactor
+	claims["jti"] = jti
+	claims["iss"] = "myApp"
+	claims["iat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(TwoFactorLifeTime()).Unix()
+
+	return token.SignedString(jwtConfig.privateKey)
+}
+
+// AccessTokenLifeTime returns how long a freshly issued access token stays valid.
+func AccessTokenLifeTime() time.Duration {
+	return time.Duration(jwtConfig.jwtLifeTime) * time.Second
+}
+
+// RefreshTokenLifeTime returns how long a freshly issued refresh token stays valid.
+func RefreshTokenLifeTime() time.Duration {
+	return time.Duration(jwtConfig.jwtRefreshLifeTime) * time.Second
+}
+
+// EmailVerifyLifeTime returns how long an email verification link stays valid.
+func EmailVerifyLifeTime() time.Duration {
+	return time.Duration(jwtConfig.emailVerifyLifeTime) * time.Second
+}
+
+// TwoFactorLifeTime returns how long a login 2FA challenge token stays valid.
+func TwoFactorLifeTime() time.Duration {
+	return time.Duration(jwtConfig.twoFactorLifeTime) * time.Second
+}
+
+// randomID returns a random 128-bit hex string used as a token jti.
+func randomID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
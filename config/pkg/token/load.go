@@ -14,10 +14,12 @@ import (
 var jwtConfig *jwtStruct
 
 type jwtStruct struct {
-	jwtLifeTime        uint
-	jwtRefreshLifeTime uint
-	privateKey         *rsa.PrivateKey
-	publicKey          *rsa.PublicKey
+	jwtLifeTime         uint
+	jwtRefreshLifeTime  uint
+	emailVerifyLifeTime uint
+	twoFactorLifeTime   uint
+	privateKey          *rsa.PrivateKey
+	publicKey           *rsa.PublicKey
 }
 
 // Load reads RSA public and private keys from configured file paths,
@@ -49,9 +51,11 @@ func Load() {
 	}
 
 	jwtConfig = &jwtStruct{
-		jwtLifeTime:        cfg.AccessTokenLifeTime,
-		jwtRefreshLifeTime: cfg.RefreshTokenLifeTime,
-		publicKey:          publicKey,
-		privateKey:         privateKey,
+		jwtLifeTime:         cfg.AccessTokenLifeTime,
+		jwtRefreshLifeTime:  cfg.RefreshTokenLifeTime,
+		emailVerifyLifeTime: cfg.EmailVerifyLifeTime,
+		twoFactorLifeTime:   cfg.TwoFactorLifeTime,
+		publicKey:           publicKey,
+		privateKey:          privateKey,
 	}
 }
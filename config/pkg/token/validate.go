@@ -4,14 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// UserAuthToken is the payload carried in the "data" claim of an access token.
+// TokenID and ExpiresAt are filled from the registered jti/exp claims on validation.
 type UserAuthToken struct {
-	ID       int    `json:"id"`
-	Email    string `json:"email"`
-	Username string `json:"username"`
+	ID           int       `json:"id"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	TokenVersion int       `json:"ver"`
+	TokenID      string    `json:"-"`
+	ExpiresAt    time.Time `json:"-"`
+	TwoFactor    bool      `json:"-"` // Whether the user has 2FA enabled, filled from the database on each request
 }
 
 // ValidateRefreshToken parses and validates a JWT refresh token string,
@@ -32,6 +39,10 @@ func ValidateRefreshToken(token string) (int, error) {
 		return 0, errors.New("invalid token claims or token not valid")
 	}
 
+	if claims["typ"] != typeRefresh {
+		return 0, errors.New("token is not a refresh token")
+	}
+
 	dataRaw, ok := claims["data"]
 	if !ok {
 		return 0, errors.New(`missing "data" field in token claims`)
@@ -61,6 +72,8 @@ func ValidateRefreshToken(token string) (int, error) {
 	return int(idFloat), nil
 }
 
+// ValidateAccessToken parses and validates a JWT access token string signed with the RSA private key,
+// returning the user data embedded in the token if valid, or an error if invalid.
 func ValidateAccessToken(token string) (*UserAuthToken, error) {
 	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
@@ -77,6 +90,10 @@ func ValidateAccessToken(token string) (*UserAuthToken, error) {
 		return nil, errors.New("invalid token claims or token not valid")
 	}
 
+	if claims["typ"] != typeAccess {
+		return nil, errors.New("token is not an access token")
+	}
+
 	data, ok := claims["data"]
 	if !ok {
 		return nil, errors.New(`missing "data" field in token claims`)
@@ -92,5 +109,104 @@ func ValidateAccessToken(token string) (*UserAuthToken, error) {
 		return nil, fmt.Errorf("failed to unmarshal token data into UserAuthToken: %w", err)
 	}
 
+	user.TokenID, _ = claims["jti"].(string)
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		user.ExpiresAt = exp.Time
+	}
+
 	return &user, nil
 }
+
+// ValidateEmailVerificationToken parses and validates an email verification token string,
+// returning the user ID and email it was issued for.
+func ValidateEmailVerificationToken(token string) (int, string, error) {
+	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return jwtConfig.publicKey, nil
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to parse verification token: %w", err)
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return 0, "", errors.New("invalid token claims or token not valid")
+	}
+
+	if claims["typ"] != typeEmailVerify {
+		return 0, "", errors.New("token is not an email verification token")
+	}
+
+	data, ok := claims["data"].(map[string]any)
+	if !ok {
+		return 0, "", errors.New(`invalid "data" field format in token claims`)
+	}
+
+	idFloat, ok := data["id"].(float64)
+	if !ok || idFloat != float64(int(idFloat)) {
+		return 0, "", fmt.Errorf(`"id" field is not an integer: %v`, data["id"])
+	}
+
+	email, ok := data["email"].(string)
+	if !ok || email == "" {
+		return 0, "", errors.New(`missing "email" field in token data`)
+	}
+
+	return int(idFloat), email, nil
+}
+
+// TwoFactorChallenge is the content of a validated login 2FA challenge token.
+type TwoFactorChallenge struct {
+	UserID    int
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+// ValidateTwoFactorChallengeToken parses and validates a login 2FA challenge token string.
+func ValidateTwoFactorChallengeToken(token string) (*TwoFactorChallenge, error) {
+	parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return jwtConfig.publicKey, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse challenge token: %w", err)
+	}
+
+	claims, ok := parsedToken.Claims.(jwt.MapClaims)
+	if !ok || !parsedToken.Valid {
+		return nil, errors.New("invalid token claims or token not valid")
+	}
+
+	if claims["typ"] != typeTwoFactor {
+		return nil, errors.New("token is not a 2FA challenge token")
+	}
+
+	data, ok := claims["data"].(map[string]any)
+	if !ok {
+		return nil, errors.New(`invalid "data" field format in token claims`)
+	}
+
+	idFloat, ok := data["id"].(float64)
+	if !ok || idFloat != float64(int(idFloat)) {
+		return nil, fmt.Errorf(`"id" field is not an integer: %v`, data["id"])
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return nil, errors.New(`missing "jti" claim`)
+	}
+
+	challenge := &TwoFactorChallenge{
+		UserID:  int(idFloat),
+		TokenID: jti,
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		challenge.ExpiresAt = exp.Time
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,133 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the ASCII key "12345678901234567890" used by the test vectors of RFC 4226 and RFC 6238.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// RFC 4226 Appendix D: six digit HOTP values for counters 0-9.
+func TestHOTPVectors(t *testing.T) {
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	key, err := decodeSecret(rfcSecret)
+	if err != nil {
+		t.Fatalf("decodeSecret: %v", err)
+	}
+	for counter, code := range want {
+		if got := hotp(key, int64(counter), 6); got != code {
+			t.Errorf("hotp(counter %d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+// RFC 6238 Appendix B: eight digit SHA1 TOTP values.
+func TestTOTPVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	key, err := decodeSecret(rfcSecret)
+	if err != nil {
+		t.Fatalf("decodeSecret: %v", err)
+	}
+	for _, tt := range tests {
+		at := time.Unix(tt.unix, 0)
+		if got := hotp(key, Counter(at), 8); got != tt.code {
+			t.Errorf("T=%d: got %s, want %s", tt.unix, got, tt.code)
+		}
+
+		// The six digit code of an authenticator app is the last six digits
+		code, err := Code(rfcSecret, at)
+		if err != nil {
+			t.Fatalf("Code: %v", err)
+		}
+		if code != tt.code[2:] {
+			t.Errorf("T=%d: Code = %s, want %s", tt.unix, code, tt.code[2:])
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Counter(now)
+	key, _ := decodeSecret(rfcSecret)
+
+	tests := []struct {
+		name    string
+		secret  string
+		code    string
+		counter int64
+		ok      bool
+	}{
+		{"current step", rfcSecret, hotp(key, current, Digits), current, true},
+		{"previous step", rfcSecret, hotp(key, current-1, Digits), current - 1, true},
+		{"next step", rfcSecret, hotp(key, current+1, Digits), current + 1, true},
+		{"outside skew", rfcSecret, hotp(key, current-2, Digits), 0, false},
+		{"surrounding spaces", rfcSecret, " " + hotp(key, current, Digits) + " ", current, true},
+		{"lowercase padded secret", strings.ToLower(rfcSecret) + "====", hotp(key, current, Digits), current, true},
+		{"too short", rfcSecret, hotp(key, current, Digits)[1:], 0, false},
+		{"eight digits", rfcSecret, hotp(key, current, 8), 0, false},
+		{"invalid secret", "not base32!", hotp(key, current, Digits), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter, ok := Validate(tt.secret, tt.code, now, 1)
+			if ok != tt.ok || counter != tt.counter {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", counter, ok, tt.counter, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSealSecret(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	sealed, err := SealSecret(secret, key)
+	if err != nil {
+		t.Fatalf("SealSecret: %v", err)
+	}
+	if strings.Contains(sealed, secret) {
+		t.Fatalf("sealed secret %q contains the plaintext", sealed)
+	}
+	if len(sealed) > 255 {
+		t.Fatalf("sealed secret is %d characters, longer than the totp_secret column", len(sealed))
+	}
+
+	opened, err := OpenSecret(sealed, key)
+	if err != nil || opened != secret {
+		t.Fatalf("OpenSecret = (%q, %v), want %q", opened, err, secret)
+	}
+
+	if _, err := OpenSecret(sealed, []byte("fedcba9876543210fedcba9876543210")); err == nil {
+		t.Error("OpenSecret with the wrong key succeeded")
+	}
+	if _, err := OpenSecret(sealed, nil); err == nil {
+		t.Error("OpenSecret without a key succeeded")
+	}
+
+	// Secrets stored before a key was configured keep working
+	if opened, err := OpenSecret(secret, key); err != nil || opened != secret {
+		t.Errorf("OpenSecret(plaintext) = (%q, %v), want %q", opened, err, secret)
+	}
+	if stored, err := SealSecret(secret, nil); err != nil || stored != secret {
+		t.Errorf("SealSecret without a key = (%q, %v), want the plaintext", stored, err)
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/database"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/scheduler"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/repository"
+	"github.com/RaFYWStud/BackendBookingStudio/service"
+)
+
+// newScheduler returns the scheduler with every background job of the application.
+func newScheduler(cfg *config.AppConfigurationMap, repo *contract.Repository, serv *contract.Service) *scheduler.Scheduler {
+	jobs := scheduler.New(repo.Job, cfg.JobPollInterval)
+
+	// Release slots of bookings that were not paid in time
+	jobs.Add(scheduler.Job{
+		Name:     "booking_expiry",
+		Interval: cfg.BookingExpiryInterval,
+		Run: func(ctx context.Context) error {
+			expired, err := serv.Booking.ExpireOverdueBookings(ctx)
+			if expired > 0 {
+				log.Printf("⌛ Expired %d unpaid booking(s)", expired)
+			}
+			return err
+		},
+	})
+
+	// Mark confirmed bookings as completed once their session is over
+	jobs.Add(scheduler.Job{
+		Name:     "booking_completion",
+		Interval: cfg.BookingCompletionInterval,
+		Run: func(ctx context.Context) error {
+			completed, err := serv.Booking.CompleteFinishedBookings(ctx)
+			if completed > 0 {
+				log.Printf("🏁 Completed %d finished booking(s)", completed)
+			}
+			return err
+		},
+	})
+
+	// Deliver queued notifications; a run may take a while when a provider is slow
+	jobs.Add(scheduler.Job{
+		Name:     "notification_outbox",
+		Interval: cfg.OutboxDispatchInterval,
+		Timeout:  5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			sent, err := serv.Outbox.DispatchPending(ctx)
+			if sent > 0 {
+				log.Printf("📧 Delivered %d queued notification(s)", sent)
+			}
+			return err
+		},
+	})
+
+	return jobs
+}
+
+// RunJob runs one background job immediately, for the "job" command.
+func RunJob(name string) error {
+	cfg := config.Get()
+
+	db, _, err := database.ConnectDB()
+	if err != nil {
+		return err
+	}
+
+	repo := repository.New(db)
+	jobs := newScheduler(cfg, repo, service.New(repo))
+
+	return jobs.RunNow(context.Background(), name)
+}
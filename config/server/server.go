@@ -1,14 +1,20 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/RaFYWStud/BackendBookingStudio/config"
 	"github.com/RaFYWStud/BackendBookingStudio/config/database"
 	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/ratelimit"
 	"github.com/RaFYWStud/BackendBookingStudio/controller"
 	"github.com/gin-gonic/gin"
 
@@ -45,6 +51,15 @@ func startServer(cfg *config.AppConfigurationMap, db *gorm.DB) {
 	repo := repository.New(db)
 	serv := service.New(repo)
 
+	// Let the Auth middleware reject revoked sessions
+	middleware.SetSessionValidator(serv.Auth.ValidateSession)
+
+	// Start background jobs
+	jobs := newScheduler(cfg, repo, serv)
+	if err := jobs.Start(context.Background()); err != nil {
+		log.Fatal("Failed to start background jobs:", err)
+	}
+
 	// Set Gin mode
 	if cfg.IsProduction {
 		gin.SetMode(gin.ReleaseMode)
@@ -52,8 +67,28 @@ func startServer(cfg *config.AppConfigurationMap, db *gorm.DB) {
 
 	// Create Gin router
 	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
 	r.Use(middleware.CORSMiddleware())
-	r.Use(middleware.GlobalRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst, map[string]struct{}{}))
+	rateLimitStore, err := newRateLimitStore(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize rate limit store:", err)
+	}
+
+	r.Use(middleware.RateLimiter(rateLimitStore, cfg.RateLimitDefault, map[string]config.RateLimitPolicy{
+		"POST /auth/login":               cfg.RateLimitAuth,
+		"POST /auth/login/2fa":           cfg.RateLimitAuth,
+		"POST /auth/register":            cfg.RateLimitAuth,
+		"POST /auth/forgot-password":     cfg.RateLimitAuth,
+		"POST /auth/reset-password":      cfg.RateLimitAuth,
+		"POST /bookings":                 cfg.RateLimitBooking,
+		"GET /studios":                   cfg.RateLimitStudioRead,
+		"GET /studios/:id":               cfg.RateLimitStudioRead,
+		"POST /studios/:id/availability": cfg.RateLimitStudioRead,
+		"GET /studios/:id/calendar":      cfg.RateLimitStudioRead,
+	}))
+	r.Use(middleware.DBTimeout(cfg.DBRequestTimeout))
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 	r.Static("/static", "./static")
@@ -77,6 +112,47 @@ func startServer(cfg *config.AppConfigurationMap, db *gorm.DB) {
 		IdleTimeout:  120 * time.Second,
 	}
 
-	log.Printf("Server is running on port %d", cfg.Port)
-	log.Fatal(srv.ListenAndServe())
+	go func() {
+		log.Printf("Server is running on port %d", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// On SIGINT/SIGTERM stop taking new work, then let in-flight requests and jobs finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("❌ HTTP server did not shut down cleanly: %v", err)
+	}
+	if err := jobs.Shutdown(shutdownCtx); err != nil {
+		log.Printf("❌ Background jobs did not finish: %v", err)
+	}
+	rateLimitStore.Close()
+
+	log.Println("Server stopped")
+}
+
+// newRateLimitStore creates the rate limit store selected by RATE_LIMIT_STORE.
+func newRateLimitStore(cfg *config.AppConfigurationMap) (ratelimit.Store, error) {
+	switch cfg.RateLimitStore {
+	case config.RateLimitStoreRedis:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		store, err := ratelimit.NewRedisStore(ctx, cfg.RedisURL, "ratelimit:")
+		if err != nil {
+			return nil, err
+		}
+		log.Println("Rate limit state is stored in Redis")
+		return store, nil
+	default:
+		return ratelimit.NewMemoryStore(time.Minute), nil
+	}
 }
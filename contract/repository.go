@@ -1,6 +1,7 @@
 package contract
 
 import (
+	"context"
 	"time"
 
 	"github.com/RaFYWStud/BackendBookingStudio/database"
@@ -11,31 +12,146 @@ type Repository struct {
     Auth          AuthRepository
     Studio        StudioRepository
     Booking       BookingRepository   
+    RefreshToken  RefreshTokenRepository
+    RevokedToken  RevokedTokenRepository
+    PasswordReset PasswordResetRepository
+    User          UserRepository
+    LoginThrottle LoginThrottleRepository
+    TwoFactor     TwoFactorRepository
+    Lock          LockRepository
+    Job           JobRepository
+    Outbox        OutboxRepository
+    Closure       ClosureRepository
+    UnitOfWork    UnitOfWork
+}
+
+// UnitOfWork runs several repository calls atomically.
+type UnitOfWork interface {
+    // WithTx runs fn in a database transaction; every repository in repos takes part in it.
+    // The transaction is committed when fn returns nil and rolled back when it returns an error or panics.
+    // The error returned by fn is passed through unchanged.
+    WithTx(ctx context.Context, fn func(repos *Repository) error) error
+}
+
+// LockRepository coordinates work between server instances sharing one database.
+type LockRepository interface {
+    // TryAdvisoryLock takes the named lock for the rest of the current transaction without waiting.
+    // It reports false if another instance holds it.
+    TryAdvisoryLock(ctx context.Context, name string) (bool, error)
+
+    // LockStudioSchedule waits for the schedule lock of a studio, or of every studio when studioID is nil,
+    // and holds it for the rest of the current transaction.
+    LockStudioSchedule(ctx context.Context, studioID *int) error
+}
+
+// JobRepository keeps the state of background jobs; it is the scheduler.Store of the server.
+type JobRepository interface {
+    Register(ctx context.Context, name string, firstRun time.Time) error
+    ClaimDue(ctx context.Context, name, owner string, now, leaseUntil time.Time) (int, bool, error)
+    Claim(ctx context.Context, name, owner string, now, leaseUntil time.Time) (int, bool, error)
+    Succeed(ctx context.Context, name, owner string, finishedAt, nextRun time.Time) error
+    Fail(ctx context.Context, name, owner string, finishedAt, retryAt time.Time, errMsg string) error
 }
 
 type AuthRepository interface {
-    CreateUser(user *database.User) error
-    FindByEmail(email string) (*database.User, error)
-    FindByID(id int) (*database.User, error)
+    CreateUser(ctx context.Context, user *database.User) error
+    FindByEmail(ctx context.Context, email string) (*database.User, error)
+    FindByID(ctx context.Context, id int) (*database.User, error)
+    IncrementTokenVersion(ctx context.Context, id int) error
+    UpdatePassword(ctx context.Context, id int, hashedPassword string) error
+    MarkEmailVerified(ctx context.Context, id int) error
+    UpdateProfile(ctx context.Context, user *database.User) error
+}
+
+type UserRepository interface {
+    FindAll(ctx context.Context, filter dto.UserFilterRequest, createdFrom, createdTo *time.Time) ([]database.User, int64, error)
+    FindByID(ctx context.Context, id int) (*database.User, error)
+    UpdateRole(ctx context.Context, id int, role string) error
+    Suspend(ctx context.Context, id int, reason string) error
+    Unsuspend(ctx context.Context, id int) error
+    CountBookingsByStatus(ctx context.Context, userID int) ([]dto.BookingStatusCount, error)
+}
+
+type RefreshTokenRepository interface {
+    Create(ctx context.Context, token *database.RefreshToken) error
+    FindByHash(ctx context.Context, tokenHash string) (*database.RefreshToken, error)
+    Revoke(ctx context.Context, id int) (bool, error)
+    RevokeFamily(ctx context.Context, familyID string) error
+    RevokeAllByUserID(ctx context.Context, userID int) error
+}
+
+type RevokedTokenRepository interface {
+    Create(ctx context.Context, token *database.RevokedToken) error
+    IsRevoked(ctx context.Context, jti string) (bool, error)
+    DeleteExpired(ctx context.Context) error
+}
+
+type PasswordResetRepository interface {
+    Create(ctx context.Context, token *database.PasswordResetToken) error
+    FindByHash(ctx context.Context, tokenHash string) (*database.PasswordResetToken, error)
+    MarkUsed(ctx context.Context, id int) (bool, error)
+    InvalidateByUserID(ctx context.Context, userID int) error
+}
+
+type LoginThrottleRepository interface {
+    Find(ctx context.Context, scope, subject string) (*database.LoginThrottle, error)
+    RecordFailure(ctx context.Context, scope, subject string, windowStart time.Time) (*database.LoginThrottle, error)
+    Lock(ctx context.Context, id int, until time.Time) (bool, error)
+    Clear(ctx context.Context, scope, subject string) error
+    DeleteStale(ctx context.Context, before time.Time) error
+}
+
+type TwoFactorRepository interface {
+    SetPendingSecret(ctx context.Context, userID int, secret string) error
+    Enable(ctx context.Context, userID int, counter int64) error
+    Disable(ctx context.Context, userID int) error
+    AcceptCounter(ctx context.Context, userID int, counter int64) (bool, error)
+    ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+    UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+    CountUnusedRecoveryCodes(ctx context.Context, userID int) (int64, error)
 }
 
 type StudioRepository interface {
-    Create(studio *database.Studio) error
-    FindByID(id int) (*database.Studio, error)
-    FindAll(filter dto.StudioFilterRequest) ([]database.Studio, int64, error)
-    Update(studio *database.Studio) error
-    Delete(id int) error
-    FindBookingsByDateRange(studioID int, date time.Time) ([]database.Booking, error)
-    IsStudioAvailable(studioID int, date time.Time, startTime, endTime time.Time) (bool, error)
+    Create(ctx context.Context, studio *database.Studio) error
+    FindByID(ctx context.Context, id int) (*database.Studio, error)
+    FindAll(ctx context.Context, filter dto.StudioFilterRequest, freeDuring *database.SessionRange, now time.Time) ([]database.Studio, int64, error)
+    Update(ctx context.Context, studio *database.Studio) error
+    Delete(ctx context.Context, id int) error
+    FindBookingsByDateRange(ctx context.Context, studioID int, date time.Time) ([]database.Booking, error)
+    FindBookingsBetweenDates(ctx context.Context, studioID int, from, to time.Time) ([]database.Booking, error)
+    IsStudioAvailable(ctx context.Context, studioID int, date time.Time, startTime, endTime time.Time) (bool, error)
 }
 
 type BookingRepository interface {
-    Create(booking *database.Booking) error
-    FindByID(id int) (*database.Booking, error)
-    FindByIDWithRelations(id int) (*database.Booking, error)
-    FindAll(filter dto.BookingFilterRequest, userID *int) ([]database.Booking, int64, error)
-    Update(booking *database.Booking) error
-    FindByUserID(userID int, filter dto.BookingFilterRequest) ([]database.Booking, int64, error)
-    CountPendingBookings(userID int) (int64, error)
-    FindExpiredBookings() ([]database.Booking, error)
+    Create(ctx context.Context, booking *database.Booking) error
+    FindByID(ctx context.Context, id int) (*database.Booking, error)
+    FindByIDWithRelations(ctx context.Context, id int) (*database.Booking, error)
+    FindAll(ctx context.Context, filter dto.BookingFilterRequest, userID *int) ([]database.Booking, int64, error)
+    Update(ctx context.Context, booking *database.Booking) error
+    FindByUserID(ctx context.Context, userID int, filter dto.BookingFilterRequest) ([]database.Booking, int64, error)
+    CountPendingBookings(ctx context.Context, userID int) (int64, error)
+    FindExpiredBookings(ctx context.Context, now time.Time) ([]database.Booking, error)
+    Expire(ctx context.Context, id int, now time.Time) (bool, error)
+    CompleteFinished(ctx context.Context, now time.Time) (int64, error)
+}
+
+type OutboxRepository interface {
+    Create(ctx context.Context, msg *database.OutboxMessage) error
+    FindByID(ctx context.Context, id int) (*database.OutboxMessage, error)
+    FindAll(ctx context.Context, filter dto.OutboxFilterRequest) ([]database.OutboxMessage, int64, error)
+    FindDue(ctx context.Context, now time.Time, limit int) ([]database.OutboxMessage, error)
+    MarkSent(ctx context.Context, id int, sentAt time.Time) error
+    MarkFailed(ctx context.Context, id int, errMsg string, nextAttemptAt *time.Time) error
+    Requeue(ctx context.Context, id int, now time.Time) (bool, error)
+}
+
+type ClosureRepository interface {
+    Create(ctx context.Context, closure *database.StudioClosure) error
+    FindByID(ctx context.Context, id int) (*database.StudioClosure, error)
+    FindAll(ctx context.Context, filter dto.ClosureFilterRequest, now time.Time) ([]database.StudioClosure, int64, error)
+    Update(ctx context.Context, closure *database.StudioClosure) error
+    Delete(ctx context.Context, id int) error
+    FlagBookings(ctx context.Context, closureID int) ([]int, error)
+    FindFlaggedBookings(ctx context.Context, closureID int) ([]database.Booking, error)
+    FindOccurrences(ctx context.Context, studioID int, from, to time.Time) ([]database.ClosureOccurrence, error)
 }
\ No newline at end of file
@@ -1,6 +1,10 @@
 package contract
 
 import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/token"
 	"github.com/RaFYWStud/BackendBookingStudio/database"
 	"github.com/RaFYWStud/BackendBookingStudio/dto"
 )
@@ -10,35 +14,94 @@ type Service struct {
     Studio        StudioService
     Booking       BookingService
     Email         EmailService   
+    User          UserService
+    Outbox        OutboxService
+    Notification  NotificationService
+    Closure       ClosureService
 }
 
 type AuthService interface {
-    Register(req dto.RegisterRequest) (*dto.RegisterResponse, error)
-    Login(req dto.LoginRequest) (*dto.LoginResponse, error)
-    GetProfile(userID int) (*dto.ProfileResponse, error)
+    Register(ctx context.Context, req dto.RegisterRequest) (*dto.RegisterResponse, error)
+    Login(ctx context.Context, req dto.LoginRequest, clientIP string) (*dto.LoginResponse, error)
+    Refresh(ctx context.Context, req dto.RefreshTokenRequest) (*dto.RefreshTokenResponse, error)
+    Logout(ctx context.Context, session *token.UserAuthToken, req dto.LogoutRequest) (*dto.LogoutResponse, error)
+    LogoutAll(ctx context.Context, userID int) (*dto.LogoutResponse, error)
+    ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error)
+    ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, error)
+    VerifyEmail(ctx context.Context, verificationToken string) (*dto.VerifyEmailResponse, error)
+    ResendVerification(ctx context.Context, userID int) (*dto.ResendVerificationResponse, error)
+    ValidateSession(ctx context.Context, claims *token.UserAuthToken) (*token.UserAuthToken, error)
+    GetProfile(ctx context.Context, userID int) (*dto.ProfileResponse, error)
+    UpdateProfile(ctx context.Context, userID int, req dto.UpdateProfileRequest) (*dto.UpdateProfileResponse, error)
+    ChangePassword(ctx context.Context, userID int, req dto.ChangePasswordRequest) (*dto.ChangePasswordResponse, error)
+    LoginTwoFactor(ctx context.Context, req dto.LoginTwoFactorRequest, clientIP string) (*dto.LoginResponse, error)
+    GetTwoFactorStatus(ctx context.Context, userID int) (*dto.TwoFactorStatusResponse, error)
+    EnrollTwoFactor(ctx context.Context, userID int) (*dto.TwoFactorEnrollResponse, error)
+    ConfirmTwoFactor(ctx context.Context, userID int, req dto.TwoFactorCodeRequest) (*dto.TwoFactorRecoveryCodesResponse, error)
+    RegenerateRecoveryCodes(ctx context.Context, userID int, req dto.TwoFactorVerifyRequest) (*dto.TwoFactorRecoveryCodesResponse, error)
+    DisableTwoFactor(ctx context.Context, userID int, req dto.DisableTwoFactorRequest) (*dto.DisableTwoFactorResponse, error)
 }
 
 type StudioService interface {
-    GetAllStudios(filter dto.StudioFilterRequest) (*dto.StudioListResponse, error)
-    GetStudioByID(studioID int) (*dto.StudioResponse, error)
-    CheckAvailability(studioID int, req dto.CheckAvailabilityRequest) (*dto.AvailabilityResponse, error)
-    CreateStudio(req dto.CreateStudioRequest) (*dto.CreateStudioResponse, error)
-    UpdateStudio(studioID int, req dto.UpdateStudioRequest) (*dto.UpdateStudioResponse, error)
-    PatchStudio(studioID int, req dto.PatchStudioRequest) (*dto.PatchStudioResponse, error)
-    DeleteStudio(studioID int) (*dto.DeleteStudioResponse, error)
+    GetAllStudios(ctx context.Context, filter dto.StudioFilterRequest) (*dto.StudioListResponse, error)
+    GetStudioByID(ctx context.Context, studioID int) (*dto.StudioResponse, error)
+    CheckAvailability(ctx context.Context, studioID int, req dto.CheckAvailabilityRequest) (*dto.AvailabilityResponse, error)
+    GetCalendar(ctx context.Context, studioID int, req dto.CalendarRequest) (*dto.CalendarResponse, error)
+    CreateStudio(ctx context.Context, req dto.CreateStudioRequest) (*dto.CreateStudioResponse, error)
+    UpdateStudio(ctx context.Context, studioID int, req dto.UpdateStudioRequest) (*dto.UpdateStudioResponse, error)
+    PatchStudio(ctx context.Context, studioID int, req dto.PatchStudioRequest) (*dto.PatchStudioResponse, error)
+    DeleteStudio(ctx context.Context, studioID int) (*dto.DeleteStudioResponse, error)
 }
 
 type BookingService interface {
-    CreateBooking(userID int, req dto.CreateBookingRequest) (*dto.CreateBookingResponse, error)
-    GetMyBookings(userID int, filter dto.BookingFilterRequest) (*dto.BookingListResponse, error)
-    GetBookingDetail(bookingID int, userID int, isAdmin bool) (*dto.BookingResponse, error)
-    CancelBooking(bookingID int, userID int, req dto.CancelBookingRequest) (*dto.CancelBookingResponse, error)
-    GetAllBookings(filter dto.BookingFilterRequest) (*dto.BookingListResponse, error)
-    UpdateBookingStatus(bookingID int, req dto.UpdateBookingStatusRequest) (*dto.UpdateBookingStatusResponse, error)
+    CreateBooking(ctx context.Context, userID int, req dto.CreateBookingRequest) (*dto.CreateBookingResponse, error)
+    GetMyBookings(ctx context.Context, userID int, filter dto.BookingFilterRequest) (*dto.BookingListResponse, error)
+    GetBookingDetail(ctx context.Context, bookingID int, userID int, canViewAll bool) (*dto.BookingResponse, error)
+    CancelBooking(ctx context.Context, bookingID int, userID int, req dto.CancelBookingRequest) (*dto.CancelBookingResponse, error)
+    GetAllBookings(ctx context.Context, filter dto.BookingFilterRequest) (*dto.BookingListResponse, error)
+    UpdateBookingStatus(ctx context.Context, bookingID int, req dto.UpdateBookingStatusRequest) (*dto.UpdateBookingStatusResponse, error)
+    ExpireOverdueBookings(ctx context.Context) (int, error)
+    CompleteFinishedBookings(ctx context.Context) (int, error)
+}
+
+type UserService interface {
+    GetAllUsers(ctx context.Context, filter dto.UserFilterRequest) (*dto.UserListResponse, error)
+    GetUserDetail(ctx context.Context, userID int) (*dto.UserDetailResponse, error)
+    UpdateUserRole(ctx context.Context, adminID int, userID int, req dto.UpdateUserRoleRequest) (*dto.UpdateUserRoleResponse, error)
+    SuspendUser(ctx context.Context, adminID int, userID int, req dto.SuspendUserRequest) (*dto.UpdateUserStatusResponse, error)
+    UnsuspendUser(ctx context.Context, userID int) (*dto.UpdateUserStatusResponse, error)
+    UnlockUser(ctx context.Context, userID int) (*dto.UpdateUserStatusResponse, error)
+}
+
+type NotificationService interface {
+    ComposeBookingCreated(booking *database.Booking) (*database.OutboxMessage, error)
+    ComposeBookingConfirmed(booking *database.Booking) (*database.OutboxMessage, error)
+    ComposeBookingCancelled(booking *database.Booking, reason string) (*database.OutboxMessage, error)
+    ComposeBookingExpired(booking *database.Booking) (*database.OutboxMessage, error)
+    Deliver(ctx context.Context, msg *database.OutboxMessage) error
 }
 
 type EmailService interface {
-    SendBookingCreated(booking *database.Booking) error              
-    SendBookingConfirmed(booking *database.Booking) error           
-    SendBookingCancelled(booking *database.Booking, reason string) error     
+    ComposeBookingCreated(booking *database.Booking) (*database.OutboxMessage, error)
+    ComposeBookingConfirmed(booking *database.Booking) (*database.OutboxMessage, error)
+    ComposeBookingCancelled(booking *database.Booking, reason string) (*database.OutboxMessage, error)
+    ComposeBookingExpired(booking *database.Booking) (*database.OutboxMessage, error)
+    SendPasswordReset(ctx context.Context, user *database.User, token string, expiresIn time.Duration) error
+    SendEmailVerification(ctx context.Context, user *database.User, token string, expiresIn time.Duration) error
+    SendAccountLocked(ctx context.Context, user *database.User, lockedUntil time.Time) error
+}
+
+type OutboxService interface {
+    DispatchPending(ctx context.Context) (int, error)
+    GetMessages(ctx context.Context, filter dto.OutboxFilterRequest) (*dto.OutboxListResponse, error)
+    GetMessage(ctx context.Context, id int) (*dto.OutboxMessageResponse, error)
+    Resend(ctx context.Context, id int) (*dto.OutboxMessageResponse, error)
+}
+
+type ClosureService interface {
+    GetClosures(ctx context.Context, filter dto.ClosureFilterRequest) (*dto.ClosureListResponse, error)
+    GetClosure(ctx context.Context, id int) (*dto.ClosureResponse, error)
+    CreateClosure(ctx context.Context, req dto.CreateClosureRequest) (*dto.ClosureResponse, error)
+    UpdateClosure(ctx context.Context, id int, req dto.UpdateClosureRequest) (*dto.ClosureResponse, error)
+    DeleteClosure(ctx context.Context, id int) (*dto.DeleteClosureResponse, error)
 }
\ No newline at end of file
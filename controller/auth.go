@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/token"
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/dto"
 	"github.com/gin-gonic/gin"
@@ -26,9 +29,24 @@ func (a *AuthController) InitRoute(app *gin.RouterGroup) {
     // Public routes
     app.POST("/register", a.register)
     app.POST("/login", a.login)
+    app.POST("/login/2fa", a.loginTwoFactor)
+    app.POST("/refresh", a.refresh)
+    app.POST("/forgot-password", a.forgotPassword)
+    app.POST("/reset-password", a.resetPassword)
+    app.GET("/verify-email", a.verifyEmail)
     
     // Protected routes (require authentication)
     app.GET("/profile", middleware.Auth(), a.getProfile)
+    app.PUT("/profile", middleware.Auth(), a.updateProfile)
+    app.POST("/change-password", middleware.Auth(), a.changePassword)
+    app.POST("/logout", middleware.Auth(), a.logout)
+    app.POST("/logout-all", middleware.Auth(), a.logoutAll)
+    app.POST("/resend-verification", middleware.Auth(), a.resendVerification)
+    app.GET("/2fa", middleware.Auth(), a.getTwoFactorStatus)
+    app.POST("/2fa/enroll", middleware.Auth(), a.enrollTwoFactor)
+    app.POST("/2fa/confirm", middleware.Auth(), a.confirmTwoFactor)
+    app.POST("/2fa/recovery-codes", middleware.Auth(), a.regenerateRecoveryCodes)
+    app.POST("/2fa/disable", middleware.Auth(), a.disableTwoFactor)
 }
 
 // Register godoc
@@ -49,7 +67,7 @@ func (a *AuthController) register(ctx *gin.Context) {
         return
     }
 
-    response, err := a.service.Register(payload)
+    response, err := a.service.Register(ctx.Request.Context(), payload)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -60,13 +78,15 @@ func (a *AuthController) register(ctx *gin.Context) {
 
 // Login godoc
 // @Summary      Login user
-// @Description  Login dengan email & password, mengembalikan JWT token
+// @Description  Login dengan email & password, mengembalikan JWT token. Jika akun memakai 2FA, response berisi challenge_token untuk /auth/login/2fa.
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
 // @Param        payload  body      dto.LoginRequest  true  "Data login user"
 // @Success      200      {object}  dto.LoginResponse
 // @Failure      400      {object}  dto.ErrorResponse "Invalid request payload / kredensial salah"
+// @Failure      403      {object}  dto.ErrorResponse "Akun disuspend"
+// @Failure      429      {object}  dto.ErrorResponse "Terlalu banyak percobaan gagal (code: LOGIN_THROTTLED, ACCOUNT_LOCKED, IP_LOCKED)"
 // @Failure      500      {object}  dto.ErrorResponse "Internal server error"
 // @Router       /auth/login [post]
 func (a *AuthController) login(ctx *gin.Context) {
@@ -76,7 +96,225 @@ func (a *AuthController) login(ctx *gin.Context) {
         return
     }
 
-    response, err := a.service.Login(payload)
+    response, err := a.service.Login(ctx.Request.Context(), payload, ctx.ClientIP())
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// Refresh godoc
+// @Summary      Refresh access token
+// @Description  Menukar refresh token dengan access token & refresh token baru (rotasi). Refresh token lama tidak bisa dipakai lagi.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.RefreshTokenRequest  true  "Refresh token"
+// @Success      200      {object}  dto.RefreshTokenResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload"
+// @Failure      401      {object}  dto.ErrorResponse "Refresh token invalid, expired, atau sudah dipakai"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/refresh [post]
+func (a *AuthController) refresh(ctx *gin.Context) {
+    var payload dto.RefreshTokenRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.Refresh(ctx.Request.Context(), payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// ForgotPassword godoc
+// @Summary      Lupa password
+// @Description  Mengirim link reset password ke email user. Response selalu sama walaupun email tidak terdaftar.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.ForgotPasswordRequest  true  "Email user"
+// @Success      200      {object}  dto.ForgotPasswordResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/forgot-password [post]
+func (a *AuthController) forgotPassword(ctx *gin.Context) {
+    var payload dto.ForgotPasswordRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.ForgotPassword(ctx.Request.Context(), payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Mengganti password menggunakan token dari email reset password. Token hanya bisa dipakai sekali dan semua sesi login akan dicabut.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.ResetPasswordRequest  true  "Token reset & password baru"
+// @Success      200      {object}  dto.ResetPasswordResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload / token invalid atau expired"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/reset-password [post]
+func (a *AuthController) resetPassword(ctx *gin.Context) {
+    var payload dto.ResetPasswordRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.ResetPassword(ctx.Request.Context(), payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// VerifyEmail godoc
+// @Summary      Verifikasi email
+// @Description  Memverifikasi alamat email menggunakan link yang dikirim saat registrasi
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        token  query     string  true  "Token verifikasi dari email"
+// @Success      200    {object}  dto.VerifyEmailResponse
+// @Failure      400    {object}  dto.ErrorResponse "Token invalid atau expired"
+// @Failure      500    {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/verify-email [get]
+func (a *AuthController) verifyEmail(ctx *gin.Context) {
+    verificationToken := ctx.Query("token")
+    if verificationToken == "" {
+        HandlerError(ctx, errs.BadRequest("token is required"))
+        return
+    }
+
+    response, err := a.service.VerifyEmail(ctx.Request.Context(), verificationToken)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// ResendVerification godoc
+// @Summary      Kirim ulang email verifikasi
+// @Description  Mengirim ulang link verifikasi ke email user login
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.ResendVerificationResponse
+// @Failure      400  {object}  dto.ErrorResponse "Email sudah terverifikasi"
+// @Failure      401  {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500  {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/resend-verification [post]
+func (a *AuthController) resendVerification(ctx *gin.Context) {
+    rawID, exists := ctx.Get("user_id")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return
+    }
+
+    id, ok := rawID.(int)
+    if !ok {
+        HandlerError(ctx, errs.InternalServerError("invalid user id type"))
+        return
+    }
+
+    response, err := a.service.ResendVerification(ctx.Request.Context(), id)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// Logout godoc
+// @Summary      Logout
+// @Description  Mencabut access token yang sedang dipakai. Jika refresh_token dikirim, seluruh refresh token dari sesi login tersebut juga dicabut.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.LogoutRequest  false  "Refresh token (opsional)"
+// @Success      200      {object}  dto.LogoutResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload"
+// @Failure      401      {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/logout [post]
+func (a *AuthController) logout(ctx *gin.Context) {
+    rawSession, exists := ctx.Get("auth_token")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return
+    }
+
+    session, ok := rawSession.(*token.UserAuthToken)
+    if !ok {
+        HandlerError(ctx, errs.InternalServerError("invalid session type"))
+        return
+    }
+
+    // Body is optional
+    var payload dto.LogoutRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil && !errors.Is(err, io.EOF) {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.Logout(ctx.Request.Context(), session, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// LogoutAll godoc
+// @Summary      Logout dari semua perangkat
+// @Description  Mencabut semua access token & refresh token milik user login
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.LogoutResponse
+// @Failure      401  {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500  {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/logout-all [post]
+func (a *AuthController) logoutAll(ctx *gin.Context) {
+    rawID, exists := ctx.Get("user_id")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return
+    }
+
+    id, ok := rawID.(int)
+    if !ok {
+        HandlerError(ctx, errs.InternalServerError("invalid user id type"))
+        return
+    }
+
+    response, err := a.service.LogoutAll(ctx.Request.Context(), id)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -109,11 +347,293 @@ func (a *AuthController) getProfile(ctx *gin.Context) {
         return
     }
 
-    response, err := a.service.GetProfile(id)
+    response, err := a.service.GetProfile(ctx.Request.Context(), id)
     if err != nil {
         HandlerError(ctx, err)
         return
     }
 
     ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateProfile godoc
+// @Summary      Update profil user login
+// @Description  Mengubah nama dan/atau email. Mengganti email akan mereset status verifikasi dan mengirim email verifikasi baru.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.UpdateProfileRequest  true  "Data profil baru"
+// @Success      200      {object}  dto.UpdateProfileResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload / email sudah terdaftar"
+// @Failure      401      {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/profile [put]
+func (a *AuthController) updateProfile(ctx *gin.Context) {
+    rawID, exists := ctx.Get("user_id")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return
+    }
+
+    id, ok := rawID.(int)
+    if !ok {
+        HandlerError(ctx, errs.InternalServerError("invalid user id type"))
+        return
+    }
+
+    var payload dto.UpdateProfileRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.UpdateProfile(ctx.Request.Context(), id, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// ChangePassword godoc
+// @Summary      Ganti password
+// @Description  Mengganti password dengan verifikasi password lama. Semua sesi login akan dicabut.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.ChangePasswordRequest  true  "Password lama & baru"
+// @Success      200      {object}  dto.ChangePasswordResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload / password lama salah"
+// @Failure      401      {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/change-password [post]
+func (a *AuthController) changePassword(ctx *gin.Context) {
+    rawID, exists := ctx.Get("user_id")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return
+    }
+
+    id, ok := rawID.(int)
+    if !ok {
+        HandlerError(ctx, errs.InternalServerError("invalid user id type"))
+        return
+    }
+
+    var payload dto.ChangePasswordRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.ChangePassword(ctx.Request.Context(), id, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// LoginTwoFactor godoc
+// @Summary      Login langkah kedua (2FA)
+// @Description  Menukar challenge_token dari /auth/login dan kode TOTP (atau recovery code) dengan JWT token
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.LoginTwoFactorRequest  true  "Challenge token & kode 2FA"
+// @Success      200      {object}  dto.LoginResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload"
+// @Failure      401      {object}  dto.ErrorResponse "Challenge token invalid / kode salah"
+// @Failure      429      {object}  dto.ErrorResponse "Terlalu banyak percobaan gagal"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/login/2fa [post]
+func (a *AuthController) loginTwoFactor(ctx *gin.Context) {
+    var payload dto.LoginTwoFactorRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.LoginTwoFactor(ctx.Request.Context(), payload, ctx.ClientIP())
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// GetTwoFactorStatus godoc
+// @Summary      Status 2FA
+// @Description  Menampilkan apakah 2FA aktif, wajib, dan sisa recovery code
+// @Tags         Auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dto.TwoFactorStatusResponse
+// @Failure      401  {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500  {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/2fa [get]
+func (a *AuthController) getTwoFactorStatus(ctx *gin.Context) {
+    id, ok := currentUserID(ctx)
+    if !ok {
+        return
+    }
+
+    response, err := a.service.GetTwoFactorStatus(ctx.Request.Context(), id)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// EnrollTwoFactor godoc
+// @Summary      Mulai aktivasi 2FA
+// @Description  Membuat secret TOTP baru dan URI otpauth:// untuk QR code. 2FA belum aktif sampai dikonfirmasi.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Produce      json
+// @Success      200  {object}  dto.TwoFactorEnrollResponse
+// @Failure      400  {object}  dto.ErrorResponse "2FA sudah aktif"
+// @Failure      401  {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500  {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/2fa/enroll [post]
+func (a *AuthController) enrollTwoFactor(ctx *gin.Context) {
+    id, ok := currentUserID(ctx)
+    if !ok {
+        return
+    }
+
+    response, err := a.service.EnrollTwoFactor(ctx.Request.Context(), id)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// ConfirmTwoFactor godoc
+// @Summary      Konfirmasi aktivasi 2FA
+// @Description  Mengaktifkan 2FA dengan kode dari aplikasi authenticator dan mengembalikan recovery code (hanya ditampilkan sekali)
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.TwoFactorCodeRequest  true  "Kode 6 digit"
+// @Success      200      {object}  dto.TwoFactorRecoveryCodesResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload / kode salah"
+// @Failure      401      {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/2fa/confirm [post]
+func (a *AuthController) confirmTwoFactor(ctx *gin.Context) {
+    id, ok := currentUserID(ctx)
+    if !ok {
+        return
+    }
+
+    var payload dto.TwoFactorCodeRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.ConfirmTwoFactor(ctx.Request.Context(), id, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Buat ulang recovery code
+// @Description  Mengganti semua recovery code. Recovery code lama tidak berlaku lagi.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.TwoFactorVerifyRequest  true  "Kode TOTP atau recovery code"
+// @Success      200      {object}  dto.TwoFactorRecoveryCodesResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload / kode salah"
+// @Failure      401      {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/2fa/recovery-codes [post]
+func (a *AuthController) regenerateRecoveryCodes(ctx *gin.Context) {
+    id, ok := currentUserID(ctx)
+    if !ok {
+        return
+    }
+
+    var payload dto.TwoFactorVerifyRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.RegenerateRecoveryCodes(ctx.Request.Context(), id, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// DisableTwoFactor godoc
+// @Summary      Nonaktifkan 2FA
+// @Description  Menonaktifkan 2FA dengan verifikasi password dan kode TOTP/recovery code. Tidak bisa jika 2FA wajib untuk role user.
+// @Tags         Auth
+// @Security     BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      dto.DisableTwoFactorRequest  true  "Password & kode 2FA"
+// @Success      200      {object}  dto.DisableTwoFactorResponse
+// @Failure      400      {object}  dto.ErrorResponse "Invalid request payload / password atau kode salah"
+// @Failure      401      {object}  dto.ErrorResponse "Unauthorized / token invalid"
+// @Failure      403      {object}  dto.ErrorResponse "2FA wajib untuk role ini"
+// @Failure      500      {object}  dto.ErrorResponse "Internal server error"
+// @Router       /auth/2fa/disable [post]
+func (a *AuthController) disableTwoFactor(ctx *gin.Context) {
+    id, ok := currentUserID(ctx)
+    if !ok {
+        return
+    }
+
+    var payload dto.DisableTwoFactorRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := a.service.DisableTwoFactor(ctx.Request.Context(), id, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// currentUserID - Read the authenticated user ID set by middleware.Auth, writing the error response if missing
+func currentUserID(ctx *gin.Context) (int, bool) {
+    rawID, exists := ctx.Get("user_id")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return 0, false
+    }
+
+    id, ok := rawID.(int)
+    if !ok {
+        HandlerError(ctx, errs.InternalServerError("invalid user id type"))
+        return 0, false
+    }
+
+    return id, true
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 
 	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/dto"
 	"github.com/gin-gonic/gin"
@@ -34,12 +35,12 @@ func (bc *BookingController) InitRoute(app *gin.RouterGroup) {
         customer.POST("/:id/cancel", bc.cancelBooking)
     }
 
-    // Admin routes
+    // Staff/admin routes
     admin := app.Group("/admin")
-    admin.Use(middleware.Auth(), middleware.AdminOnly())
+    admin.Use(middleware.Auth())
     {
-        admin.GET("", bc.getAllBookings)
-        admin.PUT("/:id/status", bc.updateBookingStatus)
+        admin.GET("", middleware.RequirePermission(permission.BookingReadAll), bc.getAllBookings)
+        admin.PUT("/:id/status", middleware.RequirePermission(permission.BookingUpdateStatus), bc.updateBookingStatus)
     }
 }
 
@@ -54,6 +55,7 @@ func (bc *BookingController) InitRoute(app *gin.RouterGroup) {
 // @Success      201      {object}  dto.CreateBookingResponse
 // @Failure      400      {object}  dto.ErrorResponse
 // @Failure      401      {object}  dto.ErrorResponse
+// @Failure      409      {object}  dto.ErrorResponse
 // @Failure      500      {object}  dto.ErrorResponse
 // @Router       /bookings [post]
 func (bc *BookingController) createBooking(ctx *gin.Context) {
@@ -69,7 +71,7 @@ func (bc *BookingController) createBooking(ctx *gin.Context) {
         return
     }
 
-    response, err := bc.service.CreateBooking(userID.(int), payload)
+    response, err := bc.service.CreateBooking(ctx.Request.Context(), userID.(int), payload)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -105,7 +107,7 @@ func (bc *BookingController) getMyBookings(ctx *gin.Context) {
         return
     }
 
-    response, err := bc.service.GetMyBookings(userID.(int), filter)
+    response, err := bc.service.GetMyBookings(ctx.Request.Context(), userID.(int), filter)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -135,7 +137,7 @@ func (bc *BookingController) getBookingDetail(ctx *gin.Context) {
     }
 
     userRole, _ := ctx.Get("user_role")
-    isAdmin := userRole == "admin"
+    canViewAll := permission.Has(userRole.(string), permission.BookingReadAll)
 
     idParam := ctx.Param("id")
     bookingID, err := strconv.Atoi(idParam)
@@ -144,7 +146,7 @@ func (bc *BookingController) getBookingDetail(ctx *gin.Context) {
         return
     }
 
-    response, err := bc.service.GetBookingDetail(bookingID, userID.(int), isAdmin)
+    response, err := bc.service.GetBookingDetail(ctx.Request.Context(), bookingID, userID.(int), canViewAll)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -186,7 +188,7 @@ func (bc *BookingController) cancelBooking(ctx *gin.Context) {
         return
     }
 
-    response, err := bc.service.CancelBooking(bookingID, userID.(int), payload)
+    response, err := bc.service.CancelBooking(ctx.Request.Context(), bookingID, userID.(int), payload)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -196,13 +198,14 @@ func (bc *BookingController) cancelBooking(ctx *gin.Context) {
 }
 
 // GetAllBookings godoc
-// @Summary      Ambil semua booking (Admin Only)
+// @Summary      Ambil semua booking (Staff/Admin)
 // @Description  Mengambil semua booking dengan filter
 // @Tags         Bookings
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        status   query string false "Filter status"
+// @Param        flagged  query bool   false "Hanya booking yang tertutup closure baru"
 // @Param        page     query int    false "Halaman"
 // @Param        limit    query int    false "Limit"
 // @Success      200      {object} dto.BookingListResponse
@@ -216,7 +219,7 @@ func (bc *BookingController) getAllBookings(ctx *gin.Context) {
         return
     }
 
-    response, err := bc.service.GetAllBookings(filter)
+    response, err := bc.service.GetAllBookings(ctx.Request.Context(), filter)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -226,8 +229,8 @@ func (bc *BookingController) getAllBookings(ctx *gin.Context) {
 }
 
 // UpdateBookingStatus godoc
-// @Summary      Update status booking (Admin Only)
-// @Description  Admin mengubah status booking
+// @Summary      Update status booking (Staff/Admin)
+// @Description  Staff/admin mengubah status booking (konfirmasi pembayaran, check-in, pembatalan)
 // @Tags         Bookings
 // @Accept       json
 // @Produce      json
@@ -237,6 +240,7 @@ func (bc *BookingController) getAllBookings(ctx *gin.Context) {
 // @Success      200      {object} dto.UpdateBookingStatusResponse
 // @Failure      400      {object} dto.ErrorResponse
 // @Failure      401      {object} dto.ErrorResponse
+// @Failure      409      {object} dto.ErrorResponse
 // @Router       /bookings/admin/{id}/status [put]
 func (bc *BookingController) updateBookingStatus(ctx *gin.Context) {
     idParam := ctx.Param("id")
@@ -252,7 +256,7 @@ func (bc *BookingController) updateBookingStatus(ctx *gin.Context) {
         return
     }
 
-    response, err := bc.service.UpdateBookingStatus(bookingID, payload)
+    response, err := bc.service.UpdateBookingStatus(ctx.Request.Context(), bookingID, payload)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type ClosureController struct {
+    service contract.ClosureService
+}
+
+func (cc *ClosureController) GetPrefix() string {
+    return "/admin/closures"
+}
+
+func (cc *ClosureController) InitService(service *contract.Service) {
+    cc.service = service.Closure
+}
+
+func (cc *ClosureController) InitRoute(app *gin.RouterGroup) {
+    // Admin-only routes
+    app.Use(middleware.Auth(), middleware.RequirePermission(permission.ClosureManage))
+    {
+        app.GET("", cc.getClosures)
+        app.GET("/:id", cc.getClosure)
+        app.POST("", cc.createClosure)
+        app.PATCH("/:id", cc.updateClosure)
+        app.DELETE("/:id", cc.deleteClosure)
+    }
+}
+
+// GetClosures godoc
+// @Summary      Ambil daftar closure studio (Admin Only)
+// @Description  Mengambil jadwal penutupan studio (maintenance, libur nasional, private event), urut dari yang paling awal
+// @Tags         Closures
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        studio_id  query   int   false  "Closure studio ini, termasuk closure semua studio"
+// @Param        upcoming   query   bool  false  "Hanya closure yang belum selesai atau masih berulang"
+// @Param        page       query   int   false  "Halaman"
+// @Param        limit      query   int   false  "Jumlah per halaman"
+// @Success      200  {object}  dto.ClosureListResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid query parameters"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      500  {object}  dto.ErrorResponse  "Internal server error"
+// @Router       /admin/closures [get]
+func (cc *ClosureController) getClosures(ctx *gin.Context) {
+    var filter dto.ClosureFilterRequest
+    if err := ctx.ShouldBindQuery(&filter); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid query parameters"))
+        return
+    }
+
+    response, err := cc.service.GetClosures(ctx.Request.Context(), filter)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// GetClosure godoc
+// @Summary      Detail closure studio (Admin Only)
+// @Description  Mengambil satu closure beserta booking yang sudah ada di dalamnya dan perlu ditindaklanjuti
+// @Tags         Closures
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID Closure"
+// @Success      200  {object}  dto.ClosureResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid closure ID"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404  {object}  dto.ErrorResponse  "Closure not found"
+// @Router       /admin/closures/{id} [get]
+func (cc *ClosureController) getClosure(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    closureID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid closure ID"))
+        return
+    }
+
+    response, err := cc.service.GetClosure(ctx.Request.Context(), closureID)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// CreateClosure godoc
+// @Summary      Tutup studio untuk sementara (Admin Only)
+// @Description  Menutup satu studio, atau semua studio jika studio_id kosong, sekali atau berulang (weekly, yearly). Booking yang sudah ada di dalam closure ditandai untuk admin.
+// @Tags         Closures
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        payload  body      dto.CreateClosureRequest  true  "Data closure baru"
+// @Success      201      {object}  dto.ClosureResponse
+// @Failure      400      {object}  dto.ErrorResponse  "Invalid request payload"
+// @Failure      401      {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404      {object}  dto.ErrorResponse  "Studio not found"
+// @Failure      500      {object}  dto.ErrorResponse  "Internal server error"
+// @Router       /admin/closures [post]
+func (cc *ClosureController) createClosure(ctx *gin.Context) {
+    var payload dto.CreateClosureRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := cc.service.CreateClosure(ctx.Request.Context(), payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusCreated, response)
+}
+
+// UpdateClosure godoc
+// @Summary      Ubah closure studio (Admin Only)
+// @Description  Mengubah sebagian field closure. Booking di dalam closure ditandai ulang.
+// @Tags         Closures
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                       true  "ID Closure"
+// @Param        payload  body      dto.UpdateClosureRequest  true  "Field closure yang diubah"
+// @Success      200      {object}  dto.ClosureResponse
+// @Failure      400      {object}  dto.ErrorResponse  "Invalid closure ID / payload"
+// @Failure      401      {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404      {object}  dto.ErrorResponse  "Closure not found"
+// @Failure      500      {object}  dto.ErrorResponse  "Internal server error"
+// @Router       /admin/closures/{id} [patch]
+func (cc *ClosureController) updateClosure(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    closureID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid closure ID"))
+        return
+    }
+
+    var payload dto.UpdateClosureRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := cc.service.UpdateClosure(ctx.Request.Context(), closureID, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// DeleteClosure godoc
+// @Summary      Hapus closure studio (Admin Only)
+// @Description  Membuka kembali studio; tanda pada booking di dalam closure dihapus
+// @Tags         Closures
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID Closure"
+// @Success      200  {object}  dto.DeleteClosureResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid closure ID"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404  {object}  dto.ErrorResponse  "Closure not found"
+// @Failure      500  {object}  dto.ErrorResponse  "Internal server error"
+// @Router       /admin/closures/{id} [delete]
+func (cc *ClosureController) deleteClosure(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    closureID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid closure ID"))
+        return
+    }
+
+    response, err := cc.service.DeleteClosure(ctx.Request.Context(), closureID)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
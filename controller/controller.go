@@ -3,11 +3,14 @@ package controller
 import (
 	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
 	"github.com/gin-gonic/gin"
 )
 
@@ -28,6 +31,9 @@ func New(app *gin.Engine, service *contract.Service) {
 		&AuthController{},
 		&StudioController{},
 		&BookingController{},
+		&UserController{},
+		&OutboxController{},
+		&ClosureController{},
 		// Add your controller here
 	}
 
@@ -44,6 +50,20 @@ func New(app *gin.Engine, service *contract.Service) {
 // handlerError is a helper function to handle errors in the controller.
 // It checks if the error is of type MessageError and responds with the appropriate status code and message.
 func HandlerError(ctx *gin.Context, err error) {
+	// Errors with a machine-readable code are reported as dto.ErrorResponse
+	var codedErr *errs.CodedError
+	if errors.As(err, &codedErr) {
+		if codedErr.RetryAfter > 0 {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(codedErr.RetryAfter.Seconds()))))
+		}
+		ctx.JSON(codedErr.Status(), dto.ErrorResponse{
+			Success: false,
+			Error:   codedErr.Message(),
+			Code:    codedErr.Code(),
+		})
+		return
+	}
+
 	var messageErr errs.MessageError
 	if errors.As(err, &messageErr) {
 		ctx.JSON(messageErr.Status(), messageErr)
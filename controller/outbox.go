@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type OutboxController struct {
+    service contract.OutboxService
+}
+
+func (oc *OutboxController) GetPrefix() string {
+    return "/admin/outbox"
+}
+
+func (oc *OutboxController) InitService(service *contract.Service) {
+    oc.service = service.Outbox
+}
+
+func (oc *OutboxController) InitRoute(app *gin.RouterGroup) {
+    // Admin-only routes
+    app.Use(middleware.Auth(), middleware.RequirePermission(permission.OutboxManage))
+    {
+        app.GET("", oc.getMessages)
+        app.GET("/:id", oc.getMessage)
+        app.POST("/:id/resend", oc.resendMessage)
+    }
+}
+
+// GetMessages godoc
+// @Summary      Ambil daftar notification outbox (Admin Only)
+// @Description  Mengambil notifikasi (email, WhatsApp, SMS) yang antri, terkirim atau gagal (dead), terbaru dulu
+// @Tags         Outbox
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status      query   string  false  "Filter status (pending, sent, dead)"
+// @Param        kind        query   string  false  "Filter jenis notifikasi (booking_created, booking_confirmed, booking_cancelled, booking_expired)"
+// @Param        channel     query   string  false  "Filter channel (email, whatsapp, sms)"
+// @Param        recipient   query   string  false  "Filter penerima, email atau nomor telepon (partial match)"
+// @Param        booking_id  query   int     false  "Filter ID booking"
+// @Param        page        query   int     false  "Halaman"
+// @Param        limit       query   int     false  "Jumlah per halaman"
+// @Success      200  {object}  dto.OutboxListResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid query parameters"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      500  {object}  dto.ErrorResponse  "Internal server error"
+// @Router       /admin/outbox [get]
+func (oc *OutboxController) getMessages(ctx *gin.Context) {
+    var filter dto.OutboxFilterRequest
+    if err := ctx.ShouldBindQuery(&filter); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid query parameters"))
+        return
+    }
+
+    response, err := oc.service.GetMessages(ctx.Request.Context(), filter)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// GetMessage godoc
+// @Summary      Detail notification outbox (Admin Only)
+// @Description  Mengambil status pengiriman satu notifikasi, termasuk jumlah percobaan dan error terakhir
+// @Tags         Outbox
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID Message"
+// @Success      200  {object}  dto.OutboxMessageResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid message ID"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404  {object}  dto.ErrorResponse  "Message not found"
+// @Router       /admin/outbox/{id} [get]
+func (oc *OutboxController) getMessage(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    messageID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid message ID"))
+        return
+    }
+
+    response, err := oc.service.GetMessage(ctx.Request.Context(), messageID)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// ResendMessage godoc
+// @Summary      Kirim ulang notifikasi yang gagal (Admin Only)
+// @Description  Mengantrikan kembali notifikasi berstatus dead dengan jatah percobaan baru
+// @Tags         Outbox
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID Message"
+// @Success      200  {object}  dto.OutboxMessageResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid message ID / notifikasi tidak berstatus dead"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404  {object}  dto.ErrorResponse  "Message not found"
+// @Router       /admin/outbox/{id}/resend [post]
+func (oc *OutboxController) resendMessage(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    messageID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid message ID"))
+        return
+    }
+
+    response, err := oc.service.Resend(ctx.Request.Context(), messageID)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 
 	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/dto"
 	"github.com/gin-gonic/gin"
@@ -28,21 +29,22 @@ func (sc *StudioController) InitRoute(app *gin.RouterGroup) {
     app.GET("", sc.getAllStudios)
     app.GET("/:id", sc.getStudioByID)
     app.POST("/:id/availability", sc.checkAvailability)
+    app.GET("/:id/calendar", sc.getCalendar)
 
     // Admin-only routes
     admin := app.Group("")
-    admin.Use(middleware.Auth(), middleware.AdminOnly())
+    admin.Use(middleware.Auth())
     {
-        admin.POST("", sc.createStudio)
-        admin.PUT("/:id", sc.updateStudio)
-        admin.PATCH("/:id", sc.patchStudio)
-        admin.DELETE("/:id", sc.deleteStudio)
+        admin.POST("", middleware.RequirePermission(permission.StudioWrite), sc.createStudio)
+        admin.PUT("/:id", middleware.RequirePermission(permission.StudioWrite), sc.updateStudio)
+        admin.PATCH("/:id", middleware.RequirePermission(permission.StudioWrite), sc.patchStudio)
+        admin.DELETE("/:id", middleware.RequirePermission(permission.StudioDelete), sc.deleteStudio)
     }
 }
 
 // GetAllStudios godoc
 // @Summary      Ambil semua studio
-// @Description  Mengambil daftar semua studio dengan filter dan pagination
+// @Description  Mengambil daftar semua studio dengan filter dan pagination. Dengan date, start_time dan end_time hanya studio aktif yang buka dan belum dibooking atau ditutup pada waktu tersebut.
 // @Tags         Studios
 // @Accept       json
 // @Produce      json
@@ -54,6 +56,9 @@ func (sc *StudioController) InitRoute(app *gin.RouterGroup) {
 // @Param        page       query     int     false  "Halaman"                default(1)
 // @Param        limit      query     int     false  "Jumlah data per halaman" default(10)
 // @Param        sort_by    query     string  false  "Sortir (price_asc, price_desc, name_asc, name_desc)"
+// @Param        date       query     string  false  "Hanya studio yang kosong pada tanggal ini (YYYY-MM-DD), wajib bersama start_time dan end_time"
+// @Param        start_time query     string  false  "Jam mulai (HH:MM)"
+// @Param        end_time   query     string  false  "Jam selesai (HH:MM), sebelum start_time berarti hari berikutnya"
 // @Success      200        {object}  dto.StudioListResponse
 // @Failure      400        {object}  dto.ErrorResponse  "Invalid query parameters"
 // @Failure      500        {object}  dto.ErrorResponse  "Internal server error"
@@ -65,7 +70,7 @@ func (sc *StudioController) getAllStudios(ctx *gin.Context) {
         return
     }
 
-    response, err := sc.service.GetAllStudios(filter)
+    response, err := sc.service.GetAllStudios(ctx.Request.Context(), filter)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -94,7 +99,7 @@ func (sc *StudioController) getStudioByID(ctx *gin.Context) {
         return
     }
 
-    response, err := sc.service.GetStudioByID(studioID)
+    response, err := sc.service.GetStudioByID(ctx.Request.Context(), studioID)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -105,7 +110,7 @@ func (sc *StudioController) getStudioByID(ctx *gin.Context) {
 
 // CheckAvailability godoc
 // @Summary      Cek jadwal ketersediaan studio
-// @Description  Mengecek apakah studio tersedia pada waktu tertentu
+// @Description  Menghitung semua waktu kosong studio pada tanggal tertentu (jam operasional, booking, closure, buffer dan durasi minimum) beserta jam mulai yang bisa dibooking. Jika start_time dan end_time dikirim, juga mengecek apakah waktu tersebut tersedia.
 // @Tags         Studios
 // @Accept       json
 // @Produce      json
@@ -129,7 +134,44 @@ func (sc *StudioController) checkAvailability(ctx *gin.Context) {
         return
     }
 
-    response, err := sc.service.CheckAvailability(studioID, payload)
+    response, err := sc.service.CheckAvailability(ctx.Request.Context(), studioID, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// GetCalendar godoc
+// @Summary      Kalender ketersediaan studio
+// @Description  Jam buka, rentang yang sudah dibooking, rentang kosong dan persentase okupansi per hari, maksimal 62 hari sekaligus
+// @Tags         Studios
+// @Accept       json
+// @Produce      json
+// @Param        id    path   int     true   "ID Studio"
+// @Param        from  query  string  false  "Tanggal awal (YYYY-MM-DD), default hari ini"
+// @Param        to    query  string  false  "Tanggal akhir (YYYY-MM-DD), default 6 hari setelah from"
+// @Success      200   {object} dto.CalendarResponse
+// @Failure      400   {object} dto.ErrorResponse  "Invalid studio ID / query parameters"
+// @Failure      404   {object} dto.ErrorResponse  "Studio not found"
+// @Failure      500   {object} dto.ErrorResponse  "Internal server error"
+// @Router       /studios/{id}/calendar [get]
+func (sc *StudioController) getCalendar(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    studioID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid studio ID"))
+        return
+    }
+
+    var req dto.CalendarRequest
+    if err := ctx.ShouldBindQuery(&req); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid query parameters"))
+        return
+    }
+
+    response, err := sc.service.GetCalendar(ctx.Request.Context(), studioID, req)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -159,7 +201,7 @@ func (sc *StudioController) createStudio(ctx *gin.Context) {
         return
     }
 
-    response, err := sc.service.CreateStudio(payload)
+    response, err := sc.service.CreateStudio(ctx.Request.Context(), payload)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -198,7 +240,7 @@ func (sc *StudioController) updateStudio(ctx *gin.Context) {
         return
     }
 
-    response, err := sc.service.UpdateStudio(studioID, payload)
+    response, err := sc.service.UpdateStudio(ctx.Request.Context(), studioID, payload)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -237,7 +279,7 @@ func (sc *StudioController) patchStudio(ctx *gin.Context) {
         return
     }
 
-    response, err := sc.service.PatchStudio(studioID, payload)
+    response, err := sc.service.PatchStudio(ctx.Request.Context(), studioID, payload)
     if err != nil {
         HandlerError(ctx, err)
         return
@@ -269,7 +311,7 @@ func (sc *StudioController) deleteStudio(ctx *gin.Context) {
         return
     }
 
-    response, err := sc.service.DeleteStudio(studioID)
+    response, err := sc.service.DeleteStudio(ctx.Request.Context(), studioID)
     if err != nil {
         HandlerError(ctx, err)
         return
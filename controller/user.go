@@ -0,0 +1,257 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config/middleware"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"github.com/gin-gonic/gin"
+)
+
+type UserController struct {
+    service contract.UserService
+}
+
+func (uc *UserController) GetPrefix() string {
+    return "/admin/users"
+}
+
+func (uc *UserController) InitService(service *contract.Service) {
+    uc.service = service.User
+}
+
+func (uc *UserController) InitRoute(app *gin.RouterGroup) {
+    // Admin-only routes
+    app.Use(middleware.Auth(), middleware.RequirePermission(permission.UserManage))
+    {
+        app.GET("", uc.getAllUsers)
+        app.GET("/:id", uc.getUserDetail)
+        app.PUT("/:id/role", uc.updateUserRole)
+        app.POST("/:id/suspend", uc.suspendUser)
+        app.POST("/:id/unsuspend", uc.unsuspendUser)
+        app.POST("/:id/unlock", uc.unlockUser)
+    }
+}
+
+// GetAllUsers godoc
+// @Summary      Ambil semua user (Admin Only)
+// @Description  Mengambil daftar user dengan filter role, email, status dan tanggal registrasi
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        role          query   string  false  "Filter role (customer, staff, admin)"
+// @Param        email         query   string  false  "Filter email (partial match)"
+// @Param        search        query   string  false  "Cari berdasarkan nama atau email"
+// @Param        is_active     query   bool    false  "false = hanya akun yang disuspend"
+// @Param        created_from  query   string  false  "Terdaftar sejak (YYYY-MM-DD)"
+// @Param        created_to    query   string  false  "Terdaftar sampai (YYYY-MM-DD)"
+// @Param        sort_by       query   string  false  "Sortir (created_asc, created_desc, name_asc, name_desc)"
+// @Param        page          query   int     false  "Halaman"
+// @Param        limit         query   int     false  "Jumlah per halaman"
+// @Success      200  {object}  dto.UserListResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid query parameters"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      500  {object}  dto.ErrorResponse  "Internal server error"
+// @Router       /admin/users [get]
+func (uc *UserController) getAllUsers(ctx *gin.Context) {
+    var filter dto.UserFilterRequest
+    if err := ctx.ShouldBindQuery(&filter); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid query parameters"))
+        return
+    }
+
+    response, err := uc.service.GetAllUsers(ctx.Request.Context(), filter)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// GetUserDetail godoc
+// @Summary      Detail user (Admin Only)
+// @Description  Mengambil detail user beserta ringkasan booking
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID User"
+// @Success      200  {object}  dto.UserDetailResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid user ID"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404  {object}  dto.ErrorResponse  "User not found"
+// @Router       /admin/users/{id} [get]
+func (uc *UserController) getUserDetail(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    userID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid user ID"))
+        return
+    }
+
+    response, err := uc.service.GetUserDetail(ctx.Request.Context(), userID)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// UpdateUserRole godoc
+// @Summary      Ubah role user (Admin Only)
+// @Description  Promote/demote user. Berlaku langsung pada request berikutnya dari user tersebut.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                        true  "ID User"
+// @Param        payload  body      dto.UpdateUserRoleRequest  true  "Role baru"
+// @Success      200      {object}  dto.UpdateUserRoleResponse
+// @Failure      400      {object}  dto.ErrorResponse  "Invalid user ID / payload"
+// @Failure      401      {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404      {object}  dto.ErrorResponse  "User not found"
+// @Router       /admin/users/{id}/role [put]
+func (uc *UserController) updateUserRole(ctx *gin.Context) {
+    adminID, exists := ctx.Get("user_id")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return
+    }
+
+    idParam := ctx.Param("id")
+    userID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid user ID"))
+        return
+    }
+
+    var payload dto.UpdateUserRoleRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := uc.service.UpdateUserRole(ctx.Request.Context(), adminID.(int), userID, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// SuspendUser godoc
+// @Summary      Suspend user (Admin Only)
+// @Description  Menonaktifkan akun user dan mencabut semua sesi login
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                     true  "ID User"
+// @Param        payload  body      dto.SuspendUserRequest  true  "Alasan suspend"
+// @Success      200      {object}  dto.UpdateUserStatusResponse
+// @Failure      400      {object}  dto.ErrorResponse  "Invalid user ID / payload"
+// @Failure      401      {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404      {object}  dto.ErrorResponse  "User not found"
+// @Router       /admin/users/{id}/suspend [post]
+func (uc *UserController) suspendUser(ctx *gin.Context) {
+    adminID, exists := ctx.Get("user_id")
+    if !exists {
+        HandlerError(ctx, errs.Unauthorized("user not authenticated"))
+        return
+    }
+
+    idParam := ctx.Param("id")
+    userID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid user ID"))
+        return
+    }
+
+    var payload dto.SuspendUserRequest
+    if err := ctx.ShouldBindJSON(&payload); err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid request payload"))
+        return
+    }
+
+    response, err := uc.service.SuspendUser(ctx.Request.Context(), adminID.(int), userID, payload)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// UnsuspendUser godoc
+// @Summary      Aktifkan kembali user (Admin Only)
+// @Description  Mengaktifkan kembali akun user yang disuspend
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID User"
+// @Success      200  {object}  dto.UpdateUserStatusResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid user ID / user tidak disuspend"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404  {object}  dto.ErrorResponse  "User not found"
+// @Router       /admin/users/{id}/unsuspend [post]
+func (uc *UserController) unsuspendUser(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    userID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid user ID"))
+        return
+    }
+
+    response, err := uc.service.UnsuspendUser(ctx.Request.Context(), userID)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
+
+// UnlockUser godoc
+// @Summary      Buka kunci login user (Admin Only)
+// @Description  Menghapus lockout login akibat terlalu banyak percobaan password yang salah
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "ID User"
+// @Success      200  {object}  dto.UpdateUserStatusResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Invalid user ID"
+// @Failure      401  {object}  dto.ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  dto.ErrorResponse  "Forbidden (bukan admin)"
+// @Failure      404  {object}  dto.ErrorResponse  "User not found"
+// @Router       /admin/users/{id}/unlock [post]
+func (uc *UserController) unlockUser(ctx *gin.Context) {
+    idParam := ctx.Param("id")
+    userID, err := strconv.Atoi(idParam)
+    if err != nil {
+        HandlerError(ctx, errs.BadRequest("invalid user ID"))
+        return
+    }
+
+    response, err := uc.service.UnlockUser(ctx.Request.Context(), userID)
+    if err != nil {
+        HandlerError(ctx, err)
+        return
+    }
+
+    ctx.JSON(http.StatusOK, response)
+}
\ No newline at end of file
@@ -2,7 +2,9 @@ package database
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/RaFYWStud/BackendBookingStudio/config"
 	"gorm.io/gorm"
 )
 
@@ -13,6 +15,15 @@ func RunMigration(db *gorm.DB) error {
         &User{},
         &Studio{},
         &Booking{},
+        &RefreshToken{},
+        &RevokedToken{},
+        &PasswordResetToken{},
+        &LoginThrottle{},
+        &RecoveryCode{},
+        &BookingStatusLog{},
+        &Job{},
+        &OutboxMessage{},
+        &StudioClosure{},
     ); err != nil {
         return fmt.Errorf("gagal migrasi: %w", err)
     }
@@ -27,10 +38,274 @@ func RunMigration(db *gorm.DB) error {
         fmt.Printf("⚠️  Warning: Failed to create composite index: %v\n", err)
     }
 
+    if err := migrateUserEmailUnique(db); err != nil {
+        return fmt.Errorf("gagal membuat index email: %w", err)
+    }
+
+    if err := migrateBookingOverlap(db); err != nil {
+        return fmt.Errorf("gagal membuat constraint booking: %w", err)
+    }
+
+    if err := migrateOperatingHours(db); err != nil {
+        return fmt.Errorf("gagal migrasi jam operasional: %w", err)
+    }
+
+    if err := migrateClosureOccurrences(db); err != nil {
+        return fmt.Errorf("gagal membuat fungsi closure: %w", err)
+    }
+
+    if err := migrateStudioIsOpen(db); err != nil {
+        return fmt.Errorf("gagal membuat fungsi jam operasional: %w", err)
+    }
+
+    // Pending bookings made before payment deadlines existed get one counted from their creation
+    if err := db.Exec(
+        `UPDATE bookings SET payment_deadline = created_at + ? * INTERVAL '1 second'
+        WHERE status = ? AND payment_deadline IS NULL`,
+        int64(config.Get().BookingPaymentHold.Seconds()), BookingStatusPending,
+    ).Error; err != nil {
+        return fmt.Errorf("gagal mengisi payment_deadline: %w", err)
+    }
+
     fmt.Println("🌱 Seeding database...")
     if err := Seed(db); err != nil {
         return fmt.Errorf("gagal seeding: %w", err)
     }
 
     return nil
+}
+
+// migrateUserEmailUnique makes emails unique regardless of case. Accounts registered before emails were
+// normalized may differ only in case; which of them should stay can't be decided here, so their IDs are
+// reported and the migration stops until they are merged or renamed.
+func migrateUserEmailUnique(db *gorm.DB) error {
+    var duplicates []struct {
+        Email string
+        IDs   string
+    }
+    if err := db.Raw(
+        `SELECT lower(email) AS email, string_agg(id::text, ', ' ORDER BY id) AS ids
+        FROM users GROUP BY lower(email) HAVING count(*) > 1 ORDER BY lower(email)`,
+    ).Scan(&duplicates).Error; err != nil {
+        return err
+    }
+
+    if len(duplicates) > 0 {
+        conflicts := make([]string, len(duplicates))
+        for i, d := range duplicates {
+            conflicts[i] = fmt.Sprintf("%s (users %s)", d.Email, d.IDs)
+        }
+        return fmt.Errorf("emails used by more than one account: %s", strings.Join(conflicts, "; "))
+    }
+
+    return db.Exec(fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s ON users (lower(email))`, UserEmailLowerIndex)).Error
+}
+
+// migrateBookingOverlap lets PostgreSQL guarantee that active bookings of one studio never overlap,
+// even when two requests pass the availability check at the same time.
+// The period column is generated from booking_date/start_time/end_time; the wall clock times are
+// read as UTC, which is fine because ranges are only ever compared within one studio.
+// A session ending at or before its start time runs past midnight.
+// Cancelled and expired bookings don't hold their slot, same as StudioRepository.IsStudioAvailable.
+// The studio's buffer time is not part of the constraint, it is enforced by IsStudioAvailable under the
+// studio schedule lock. Overlaps made before the constraint existed are resolved first, see resolveBookingOverlaps.
+func migrateBookingOverlap(db *gorm.DB) error {
+    statements := []string{
+        `CREATE EXTENSION IF NOT EXISTS btree_gist`,
+        `ALTER TABLE bookings ADD COLUMN IF NOT EXISTS period tstzrange
+            GENERATED ALWAYS AS (tstzrange(
+                timezone('UTC', booking_date + start_time),
+                timezone('UTC', CASE WHEN end_time <= start_time THEN booking_date + 1 ELSE booking_date END + end_time),
+                '[)'
+            )) STORED`,
+    }
+
+    for _, stmt := range statements {
+        if err := db.Exec(stmt).Error; err != nil {
+            return err
+        }
+    }
+
+    var exists bool
+    if err := db.Raw(
+        `SELECT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = ? AND conrelid = 'bookings'::regclass)`,
+        BookingNoOverlapConstraint,
+    ).Scan(&exists).Error; err != nil {
+        return err
+    }
+    if exists {
+        return nil
+    }
+
+    return db.Transaction(func(tx *gorm.DB) error {
+        // No booking can be made between resolving the overlaps and adding the constraint
+        if err := tx.Exec(`LOCK TABLE bookings IN SHARE ROW EXCLUSIVE MODE`).Error; err != nil {
+            return err
+        }
+        if err := resolveBookingOverlaps(tx); err != nil {
+            return err
+        }
+        return tx.Exec(fmt.Sprintf(`ALTER TABLE bookings ADD CONSTRAINT %s
+            EXCLUDE USING gist (studio_id WITH =, period WITH &&)
+            WHERE (status NOT IN ('cancelled', 'expired'))`, BookingNoOverlapConstraint)).Error
+    })
+}
+
+// resolveBookingOverlaps cancels the active bookings that overlap another one of the same studio, which
+// the double-booking race left behind before the constraint existed. Confirmed and completed bookings are
+// kept before pending ones, then the earliest made; a booking only goes if it overlaps one that stays.
+// Every cancellation gets a warning and a status log entry naming the booking that was kept.
+func resolveBookingOverlaps(tx *gorm.DB) error {
+    inactive := []BookingStatus{BookingStatusCancelled, BookingStatusExpired}
+
+    var pairs []struct {
+        First  int
+        Second int
+    }
+    if err := tx.Raw(
+        `SELECT a.id AS first, b.id AS second FROM bookings a
+        JOIN bookings b ON b.studio_id = a.studio_id AND b.id > a.id AND b.period && a.period
+        WHERE a.status NOT IN ? AND b.status NOT IN ?`,
+        inactive, inactive,
+    ).Scan(&pairs).Error; err != nil {
+        return err
+    }
+    if len(pairs) == 0 {
+        return nil
+    }
+
+    overlapping := map[int][]int{}
+    for _, pair := range pairs {
+        overlapping[pair.First] = append(overlapping[pair.First], pair.Second)
+        overlapping[pair.Second] = append(overlapping[pair.Second], pair.First)
+    }
+    ids := make([]int, 0, len(overlapping))
+    for id := range overlapping {
+        ids = append(ids, id)
+    }
+
+    var candidates []Booking
+    // false sorts first, so pending bookings come last
+    if err := tx.Where("id IN ?", ids).Order("status = 'pending', created_at, id").Find(&candidates).Error; err != nil {
+        return err
+    }
+
+    kept := map[int]bool{}
+    for _, booking := range candidates {
+        keptOverlap := 0
+        for _, other := range overlapping[booking.ID] {
+            if kept[other] {
+                keptOverlap = other
+                break
+            }
+        }
+        if keptOverlap == 0 {
+            kept[booking.ID] = true
+            continue
+        }
+
+        reason := fmt.Sprintf("overlaps booking %d, cancelled when the no-overlap constraint was added", keptOverlap)
+        fmt.Printf("⚠️  Warning: studio %d: booking %d %s\n", booking.StudioID, booking.ID, reason)
+        if err := tx.Model(&Booking{}).Where("id = ?", booking.ID).Update("status", BookingStatusCancelled).Error; err != nil {
+            return err
+        }
+        if err := tx.Create(&BookingStatusLog{
+            BookingID:  booking.ID,
+            FromStatus: booking.Status,
+            ToStatus:   BookingStatusCancelled,
+            Reason:     reason,
+        }).Error; err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+// migrateOperatingHours converts the old free-form operating_hours column ("09:00-22:00") into
+// weekly_hours, with that window every day, then drops it. Values that can't be read get
+// DefaultOperatingHours and a warning, so the admin can correct them.
+func migrateOperatingHours(db *gorm.DB) error {
+    if !db.Migrator().HasColumn(&Studio{}, "operating_hours") {
+        return nil
+    }
+
+    return db.Transaction(func(tx *gorm.DB) error {
+        var legacy []struct {
+            ID             int
+            OperatingHours *string
+        }
+        if err := tx.Table("studios").Select("id", "operating_hours").Where("weekly_hours IS NULL").Scan(&legacy).Error; err != nil {
+            return err
+        }
+
+        for _, studio := range legacy {
+            hours := DefaultOperatingHours
+            if studio.OperatingHours != nil {
+                parsed, err := ParseLegacyOperatingHours(*studio.OperatingHours)
+                if err != nil {
+                    fmt.Printf("⚠️  Warning: studio %d: %v, using 09:00-22:00 every day\n", studio.ID, err)
+                } else {
+                    hours = parsed
+                }
+            }
+
+            if err := tx.Model(&Studio{}).Where("id = ?", studio.ID).Update("weekly_hours", hours).Error; err != nil {
+                return err
+            }
+        }
+
+        return tx.Migrator().DropColumn(&Studio{}, "operating_hours")
+    })
+}
+
+// migrateClosureOccurrences creates closure_occurrences(), which expands one studio closure into its
+// occurrences overlapping [range_from, range_to), as '[)' ranges comparable with the period of bookings.
+// Repeats are computed on the wall clock (timestamp without time zone), so they don't shift with DST.
+// range_to must be finite: a closure that repeats forever has no last occurrence.
+func migrateClosureOccurrences(db *gorm.DB) error {
+    return db.Exec(`CREATE OR REPLACE FUNCTION closure_occurrences(
+            starts_at timestamptz, ends_at timestamptz, recurrence text, repeat_until date,
+            range_from timestamptz, range_to timestamptz
+        ) RETURNS TABLE (occurrence tstzrange) AS $$
+            SELECT tstzrange(
+                timezone('UTC', s),
+                timezone('UTC', s + (timezone('UTC', ends_at) - timezone('UTC', starts_at))),
+                '[)'
+            )
+            FROM generate_series(
+                timezone('UTC', starts_at),
+                CASE WHEN recurrence = 'none' THEN timezone('UTC', starts_at)
+                     ELSE LEAST(timezone('UTC', range_to), (repeat_until + 1)::timestamp - interval '1 microsecond') END,
+                CASE WHEN recurrence = 'yearly' THEN interval '1 year' ELSE interval '1 week' END
+            ) AS s
+            WHERE timezone('UTC', s) < range_to
+              AND timezone('UTC', s + (timezone('UTC', ends_at) - timezone('UTC', starts_at))) > range_from
+        $$ LANGUAGE sql IMMUTABLE`).Error
+}
+
+// migrateStudioIsOpen creates studio_is_open(), the SQL counterpart of WeeklyHours.Covers: whether a session,
+// as wall clock times stored as UTC, lies within the opening periods of weekly_hours. Periods of the day
+// before that run past midnight count, and touching periods join. The session is covered when its start
+// and every period end inside it fall within some period.
+func migrateStudioIsOpen(db *gorm.DB) error {
+    return db.Exec(`CREATE OR REPLACE FUNCTION studio_is_open(
+            weekly_hours jsonb, session_from timestamptz, session_to timestamptz
+        ) RETURNS boolean AS $$
+            WITH periods AS (
+                SELECT timezone('UTC', d + (h->>'open')::time) AS opens,
+                       timezone('UTC', d + (h->>'close')::time
+                           + CASE WHEN (h->>'close')::time <= (h->>'open')::time THEN interval '1 day' ELSE interval '0' END) AS closes
+                FROM generate_series(-1, timezone('UTC', session_to)::date - timezone('UTC', session_from)::date) AS n,
+                    LATERAL (SELECT timezone('UTC', session_from)::date + n AS d) AS days,
+                    LATERAL (SELECT weekly_hours -> to_char(d, 'FMday') AS h) AS hours
+                WHERE jsonb_typeof(h) = 'object'
+            )
+            SELECT EXISTS (SELECT 1 FROM periods WHERE opens <= session_from AND session_from < closes)
+                AND NOT EXISTS (
+                    SELECT 1 FROM periods e
+                    WHERE e.closes > session_from AND e.closes < session_to
+                        AND NOT EXISTS (SELECT 1 FROM periods p WHERE p.opens <= e.closes AND e.closes < p.closes)
+                )
+        $$ LANGUAGE sql STABLE`).Error
 }
\ No newline at end of file
@@ -0,0 +1,143 @@
+package database_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"gorm.io/gorm"
+)
+
+func createUser(t *testing.T, db *gorm.DB, email string) *database.User {
+	t.Helper()
+
+	user := &database.User{Name: "Test Customer", Email: email, Password: "-", Role: "customer", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user %s: %v", email, err)
+	}
+	return user
+}
+
+func createBooking(t *testing.T, db *gorm.DB, userID, studioID int, from, to string, status database.BookingStatus) *database.Booking {
+	t.Helper()
+
+	start, err := time.Parse("15:04", from)
+	if err != nil {
+		t.Fatalf("parse %q: %v", from, err)
+	}
+	end, err := time.Parse("15:04", to)
+	if err != nil {
+		t.Fatalf("parse %q: %v", to, err)
+	}
+	booking := &database.Booking{
+		UserID:        userID,
+		StudioID:      studioID,
+		BookingDate:   time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC),
+		StartTime:     start,
+		EndTime:       end,
+		DurationHours: 2,
+		TotalPrice:    200000,
+		Status:        status,
+	}
+	if err := db.Create(booking).Error; err != nil {
+		t.Fatalf("create booking %s-%s: %v", from, to, err)
+	}
+	return booking
+}
+
+func TestMigrationResolvesBookingOverlaps(t *testing.T) {
+	db := dbtest.Open(t)
+
+	// Bookings from before the constraint, when the double-booking race was possible
+	if err := db.Exec("ALTER TABLE bookings DROP CONSTRAINT " + database.BookingNoOverlapConstraint).Error; err != nil {
+		t.Fatalf("drop constraint: %v", err)
+	}
+
+	user := createUser(t, db, "overlap@example.com")
+	studio := &database.Studio{Name: "Overlap", Location: "Overlap", PricePerHour: 100000,
+		OperatingHours: database.DailyHours("00:00", "00:00"), Timezone: "Asia/Jakarta", IsActive: true}
+	if err := db.Create(studio).Error; err != nil {
+		t.Fatalf("create studio: %v", err)
+	}
+
+	earlierPending := createBooking(t, db, user.ID, studio.ID, "09:00", "11:00", database.BookingStatusPending)
+	confirmed := createBooking(t, db, user.ID, studio.ID, "10:00", "12:00", database.BookingStatusConfirmed)
+	overlapsConfirmed := createBooking(t, db, user.ID, studio.ID, "11:00", "13:00", database.BookingStatusPending)
+	afterConfirmed := createBooking(t, db, user.ID, studio.ID, "12:00", "14:00", database.BookingStatusPending)
+	cancelled := createBooking(t, db, user.ID, studio.ID, "10:00", "12:00", database.BookingStatusCancelled)
+
+	if err := database.RunMigration(db); err != nil {
+		t.Fatalf("RunMigration: %v", err)
+	}
+
+	tests := []struct {
+		booking *database.Booking
+		want    database.BookingStatus
+	}{
+		{earlierPending, database.BookingStatusCancelled}, // A confirmed booking is kept first
+		{confirmed, database.BookingStatusConfirmed},
+		{overlapsConfirmed, database.BookingStatusCancelled},
+		{afterConfirmed, database.BookingStatusPending}, // Only overlapped a booking that was cancelled
+		{cancelled, database.BookingStatusCancelled},
+	}
+	for _, tt := range tests {
+		var got database.Booking
+		if err := db.First(&got, tt.booking.ID).Error; err != nil {
+			t.Fatalf("find booking %d: %v", tt.booking.ID, err)
+		}
+		if got.Status != tt.want {
+			t.Errorf("booking %s-%s is %s, want %s", tt.booking.StartTime.Format("15:04"), tt.booking.EndTime.Format("15:04"), got.Status, tt.want)
+		}
+	}
+
+	var logs []database.BookingStatusLog
+	if err := db.Order("booking_id").Find(&logs).Error; err != nil {
+		t.Fatalf("find status logs: %v", err)
+	}
+	if len(logs) != 2 || logs[0].BookingID != earlierPending.ID || logs[1].BookingID != overlapsConfirmed.ID {
+		t.Fatalf("status logs = %+v, want one for each cancelled booking", logs)
+	}
+	if want := fmt.Sprintf("overlaps booking %d", confirmed.ID); !strings.Contains(logs[0].Reason, want) {
+		t.Errorf("reason = %q, want it to name booking %d", logs[0].Reason, confirmed.ID)
+	}
+
+	// The constraint is in place again
+	err := db.Create(&database.Booking{UserID: user.ID, StudioID: studio.ID, BookingDate: confirmed.BookingDate,
+		StartTime: confirmed.StartTime, EndTime: confirmed.EndTime, DurationHours: 2, TotalPrice: 200000,
+		Status: database.BookingStatusPending}).Error
+	if err == nil {
+		t.Error("created a booking overlapping a confirmed one after the migration")
+	}
+}
+
+func TestMigrationCaseVariantEmails(t *testing.T) {
+	db := dbtest.Open(t)
+
+	// Accounts from before emails were normalized
+	if err := db.Exec("DROP INDEX " + database.UserEmailLowerIndex).Error; err != nil {
+		t.Fatalf("drop index: %v", err)
+	}
+	first := createUser(t, db, "dup@example.com")
+	second := createUser(t, db, "Dup@Example.com")
+
+	err := database.RunMigration(db)
+	if err == nil {
+		t.Fatal("RunMigration succeeded with case-variant emails")
+	}
+	if want := fmt.Sprintf("dup@example.com (users %d, %d)", first.ID, second.ID); !strings.Contains(err.Error(), want) {
+		t.Errorf("RunMigration = %v, want it to list %q", err, want)
+	}
+
+	if err := db.Delete(second).Error; err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+	if err := database.RunMigration(db); err != nil {
+		t.Fatalf("RunMigration after resolving: %v", err)
+	}
+	if err := db.Create(&database.User{Name: "Other", Email: "DUP@example.com", Password: "-", Role: "customer"}).Error; err == nil {
+		t.Error("created a case variant of an existing email")
+	}
+}
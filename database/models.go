@@ -3,18 +3,107 @@ package database
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"time"
 )
 
 // User model
 type User struct {
-    ID        int       `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
-    Name      string    `gorm:"column:name;not null"`
-    Email     string    `gorm:"column:email;uniqueIndex;not null"`
-    Password  string    `gorm:"column:password;not null"`
-    Role      string    `gorm:"column:role;type:varchar(50);not null;default:'customer'"` // customer, admin
+    ID              int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    Name            string     `gorm:"column:name;not null"`
+    Email           string     `gorm:"column:email;uniqueIndex;not null"`
+    Password        string     `gorm:"column:password;not null"`
+    Role            string     `gorm:"column:role;type:varchar(50);not null;default:'customer'"` // customer, staff, admin
+    TokenVersion    int        `gorm:"column:token_version;not null;default:0"`                  // Bumped to invalidate every issued access token
+    EmailVerifiedAt *time.Time `gorm:"column:email_verified_at"`                                 // NULL until the user clicks the verification link
+    IsActive        bool       `gorm:"column:is_active;not null;default:true;index"`             // false while the account is suspended
+    SuspendedAt     *time.Time `gorm:"column:suspended_at"`
+    SuspendReason   string     `gorm:"column:suspend_reason;type:text"`
+    Phone           string     `gorm:"column:phone;type:varchar(20)"`
+    NotifyChannel   string     `gorm:"column:notify_channel;type:varchar(20);not null;default:'email'"`
+    TOTPSecret      string     `gorm:"column:totp_secret;type:varchar(255)"`        // Base32 secret, encrypted with TOTP_ENCRYPTION_KEY when set; kept while 2FA is on
+    TOTPEnabledAt   *time.Time `gorm:"column:totp_enabled_at"`                      // NULL until enrollment is confirmed with a valid code
+    TOTPLastCounter int64      `gorm:"column:totp_last_counter;not null;default:0"` // Last accepted time step, so a code can't be replayed
+    CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime"`
+    UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// UserEmailLowerIndex is the unique index on lower(email), so no two accounts differ only in the case
+// of their email. Repositories report its violations as ErrEmailTaken.
+const UserEmailLowerIndex = "idx_users_email_lower"
+
+// ErrEmailTaken is returned when another account already uses the email address.
+var ErrEmailTaken = errors.New("email already registered")
+
+// RefreshToken model - one row per issued refresh token.
+// Tokens rotated from the same login share a FamilyID so the whole chain
+// can be revoked when an already-used token is presented again.
+type RefreshToken struct {
+    ID        int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    UserID    int        `gorm:"column:user_id;not null;index"`
+    FamilyID  string     `gorm:"column:family_id;type:varchar(64);not null;index"`
+    TokenHash string     `gorm:"column:token_hash;type:varchar(64);uniqueIndex;not null"` // sha256 of the raw token
+    ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+    RevokedAt *time.Time `gorm:"column:revoked_at"`
+    CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+
+    // Relations
+    User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// RevokedToken model - denylist of access tokens (by jti) revoked before they expire.
+// Rows can be purged once ExpiresAt has passed.
+type RevokedToken struct {
+    JTI       string    `gorm:"column:jti;primaryKey;type:varchar(64)"`
+    UserID    int       `gorm:"column:user_id;not null;index"`
+    ExpiresAt time.Time `gorm:"column:expires_at;not null;index"`
     CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
-    UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// PasswordResetToken model - single-use password reset link.
+// Only the SHA-256 hash of the emailed token is stored.
+type PasswordResetToken struct {
+    ID        int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    UserID    int        `gorm:"column:user_id;not null;index"`
+    TokenHash string     `gorm:"column:token_hash;type:varchar(64);uniqueIndex;not null"`
+    ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+    UsedAt    *time.Time `gorm:"column:used_at"`
+    CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+
+    // Relations
+    User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// RecoveryCode model - single-use 2FA backup code.
+// Only the SHA-256 hash of the code shown to the user is stored.
+type RecoveryCode struct {
+    ID        int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    UserID    int        `gorm:"column:user_id;not null;index"`
+    CodeHash  string     `gorm:"column:code_hash;type:varchar(64);not null;index"`
+    UsedAt    *time.Time `gorm:"column:used_at"`
+    CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+
+    // Relations
+    User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// Login throttle scopes
+const (
+    LoginThrottleScopeEmail = "email"
+    LoginThrottleScopeIP    = "ip"
+)
+
+// LoginThrottle model - failed login counter for one email address or client IP.
+// The counter starts over once LastFailedAt falls outside the attempt window.
+type LoginThrottle struct {
+    ID           int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    Scope        string     `gorm:"column:scope;type:varchar(10);not null;uniqueIndex:idx_login_throttle_subject"` // email, ip
+    Subject      string     `gorm:"column:subject;type:varchar(255);not null;uniqueIndex:idx_login_throttle_subject"`
+    FailedCount  int        `gorm:"column:failed_count;not null;default:0"`
+    LastFailedAt time.Time  `gorm:"column:last_failed_at;not null;index"`
+    LockedUntil  *time.Time `gorm:"column:locked_until"`
+    CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime"`
+    UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime"`
 }
 
 // StringArray type for JSONB arrays
@@ -45,12 +134,30 @@ type Studio struct {
     PricePerHour   int         `gorm:"column:price_per_hour;not null;index"`
     ImageURL       string      `gorm:"column:image_url;type:text"`
     Facilities     StringArray `gorm:"column:facilities;type:jsonb"`
-    OperatingHours string      `gorm:"column:operating_hours;type:varchar(100)"` // '09:00-22:00'
+    OperatingHours WeeklyHours `gorm:"column:weekly_hours;type:jsonb"`
+    Timezone       string      `gorm:"column:timezone;type:varchar(64);not null;default:'Asia/Jakarta'"` // IANA name, jam operasional & booking mengikuti zona ini
+    BufferMinutes  int         `gorm:"column:buffer_minutes;not null;default:0"`                        // Jeda kosong sebelum & sesudah setiap booking (persiapan, bersih-bersih)
     IsActive       bool        `gorm:"column:is_active;default:true;index"`
     CreatedAt      time.Time   `gorm:"column:created_at;autoCreateTime"`
     UpdatedAt      time.Time   `gorm:"column:updated_at;autoUpdateTime"`
 }
 
+// DefaultStudioTimezone is used for studios created without a timezone.
+const DefaultStudioTimezone = "Asia/Jakarta"
+
+// TimeLocation returns the studio's timezone, falling back to the server's local zone
+// if the stored name can't be loaded.
+func (s *Studio) TimeLocation() *time.Location {
+    if s.Timezone == "" {
+        return time.Local
+    }
+    loc, err := time.LoadLocation(s.Timezone)
+    if err != nil {
+        return time.Local
+    }
+    return loc
+}
+
 // BookingStatus enum - SIMPLIFIED
 type BookingStatus string
 
@@ -59,28 +166,133 @@ const (
     BookingStatusConfirmed BookingStatus = "confirmed" // Sudah bayar (dikonfirmasi admin)
     BookingStatusCompleted BookingStatus = "completed" // Selesai digunakan
     BookingStatusCancelled BookingStatus = "cancelled" // Dibatalkan
+    BookingStatusExpired   BookingStatus = "expired"   // Tidak dibayar sampai batas waktu
 )
 
+// BookingNoOverlapConstraint is the exclusion constraint that rejects overlapping active bookings
+// of the same studio. Repositories report its violations as ErrBookingOverlap.
+const BookingNoOverlapConstraint = "bookings_no_overlap"
+
+// ErrBookingOverlap is returned when a booking would overlap another active booking of the same studio.
+var ErrBookingOverlap = errors.New("booking overlaps an existing booking")
+
 // Booking model - SIMPLIFIED
 type Booking struct {
-    ID            int           `gorm:"primaryKey;autoIncrement" json:"id"`
-    UserID        int           `gorm:"not null;index" json:"user_id"`
-    StudioID      int           `gorm:"not null;index" json:"studio_id"`
-    BookingDate   time.Time     `gorm:"type:date;not null;index" json:"booking_date"`
-    StartTime     time.Time     `gorm:"type:time;not null" json:"start_time"`
-    EndTime       time.Time     `gorm:"type:time;not null" json:"end_time"`
-    DurationHours int           `gorm:"not null" json:"duration_hours"`
-    TotalPrice    int           `gorm:"not null" json:"total_price"`
-    Status        BookingStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
-    AdminNotes    string        `gorm:"type:text" json:"admin_notes"` // Catatan pembayaran dari admin
-    CreatedAt     time.Time     `gorm:"autoCreateTime" json:"created_at"`
-    UpdatedAt     time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+    ID              int           `gorm:"primaryKey;autoIncrement" json:"id"`
+    UserID          int           `gorm:"not null;index" json:"user_id"`
+    StudioID        int           `gorm:"not null;index" json:"studio_id"`
+    BookingDate     time.Time     `gorm:"type:date;not null;index" json:"booking_date"`
+    StartTime       time.Time     `gorm:"type:time;not null" json:"start_time"`
+    EndTime         time.Time     `gorm:"type:time;not null" json:"end_time"`
+    DurationHours   int           `gorm:"not null" json:"duration_hours"`
+    TotalPrice      int           `gorm:"not null" json:"total_price"`
+    Status          BookingStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+    AdminNotes      string        `gorm:"type:text" json:"admin_notes"`  // Catatan pembayaran dari admin
+    PaymentDeadline *time.Time    `gorm:"index" json:"payment_deadline"` // Booking pending menjadi expired setelah batas ini
+    ClosureID       *int          `gorm:"index" json:"closure_id"`       // Ditandai: closure yang dibuat belakangan menutup jadwal booking ini
+    CreatedAt       time.Time     `gorm:"autoCreateTime" json:"created_at"`
+    UpdatedAt       time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
 
     // Relations
-    User   *User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
-    Studio *Studio `gorm:"foreignKey:StudioID;constraint:OnDelete:CASCADE" json:"studio,omitempty"`
+    User    *User          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+    Studio  *Studio        `gorm:"foreignKey:StudioID;constraint:OnDelete:CASCADE" json:"studio,omitempty"`
+    Closure *StudioClosure `gorm:"foreignKey:ClosureID;constraint:OnDelete:SET NULL" json:"closure,omitempty"`
 }
 
 func (Booking) TableName() string {
     return "bookings"
+}
+
+// Closure recurrences
+const (
+    RecurrenceNone   = "none"
+    RecurrenceWeekly = "weekly"
+    RecurrenceYearly = "yearly"
+)
+
+// StudioClosure model - a window in which a studio, or every studio when StudioID is nil, can't be booked
+// (maintenance, national holidays, private events). StartsAt/EndsAt are wall clock times stored as UTC,
+// like the period of bookings, so a global closure follows the timezone of each studio.
+// Weekly and yearly closures repeat from StartsAt until RepeatUntil, or forever.
+type StudioClosure struct {
+    ID          int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    StudioID    *int       `gorm:"column:studio_id;index"` // nil: berlaku untuk semua studio
+    Reason      string     `gorm:"column:reason;type:varchar(255);not null"`
+    StartsAt    time.Time  `gorm:"column:starts_at;not null"`
+    EndsAt      time.Time  `gorm:"column:ends_at;not null"`
+    Recurrence  string     `gorm:"column:recurrence;type:varchar(10);not null;default:'none'"` // none, weekly, yearly
+    RepeatUntil *time.Time `gorm:"column:repeat_until;type:date"`                              // Tanggal terakhir pengulangan, nil: selamanya
+    CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime"`
+    UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+
+    // Relations
+    Studio *Studio `gorm:"foreignKey:StudioID;constraint:OnDelete:CASCADE"`
+}
+
+// ClosureOccurrence is one occurrence of a closure, with wall clock times stored as UTC.
+type ClosureOccurrence struct {
+    ClosureID int
+    StudioID  *int
+    Reason    string
+    StartsAt  time.Time
+    EndsAt    time.Time
+}
+
+// BookingStatusLog model - one row per status change made automatically by the system
+// (payment expiry, completion after the session ended), so they can be audited later.
+type BookingStatusLog struct {
+    ID         int           `gorm:"primaryKey;autoIncrement" json:"id"`
+    BookingID  int           `gorm:"not null;index" json:"booking_id"`
+    FromStatus BookingStatus `gorm:"type:varchar(20);not null" json:"from_status"`
+    ToStatus   BookingStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+    Reason     string        `gorm:"type:text" json:"reason"`
+    CreatedAt  time.Time     `gorm:"autoCreateTime" json:"created_at"`
+
+    // Relations
+    Booking *Booking `gorm:"foreignKey:BookingID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// Job model - state of a named background job, shared by every server instance.
+// An instance runs the job only while it holds the lease (LockedBy/LockedUntil).
+type Job struct {
+    ID             int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    Name           string     `gorm:"column:name;type:varchar(100);uniqueIndex;not null"`
+    NextRunAt      time.Time  `gorm:"column:next_run_at;not null;index"`
+    LockedBy       *string    `gorm:"column:locked_by;type:varchar(255)"` // Instance running the job, NULL when idle
+    LockedUntil    *time.Time `gorm:"column:locked_until"`                // Another instance may take over after this
+    Failures       int        `gorm:"column:failures;not null;default:0"` // Failed runs in a row, drives the retry backoff
+    LastError      string     `gorm:"column:last_error;type:text"`
+    LastStartedAt  *time.Time `gorm:"column:last_started_at"`
+    LastFinishedAt *time.Time `gorm:"column:last_finished_at"`
+    CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+    UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// Outbox message statuses
+const (
+    OutboxStatusPending = "pending" // Waiting for its (next) delivery attempt
+    OutboxStatusSent    = "sent"
+    OutboxStatusDead    = "dead" // Gave up after OUTBOX_MAX_ATTEMPTS, an admin can resend it
+)
+
+// OutboxMessage model - notification written in the same transaction as the change it reports,
+// delivered afterwards by the notification_outbox job. The body is rendered when the message is created.
+type OutboxMessage struct {
+    ID            int        `gorm:"column:id;primaryKey;autoIncrement;not null;<-:create"`
+    Kind          string     `gorm:"column:kind;type:varchar(50);not null;index"`              // Template name, e.g. booking_created
+    Channel       string     `gorm:"column:channel;type:varchar(20);not null;default:'email'"` // email, whatsapp, sms
+    Recipient     string     `gorm:"column:recipient;not null"`                                // Email address or phone number, depending on Channel
+    Subject       string     `gorm:"column:subject;not null"`                                  // Email only
+    Body          string     `gorm:"column:body;type:text;not null"`                           // HTML for email, plain text otherwise
+    BookingID     *int       `gorm:"column:booking_id;index"`
+    Status        string     `gorm:"column:status;type:varchar(20);not null;default:'pending';index:idx_outbox_due,priority:1"`
+    Attempts      int        `gorm:"column:attempts;not null;default:0"`
+    NextAttemptAt time.Time  `gorm:"column:next_attempt_at;not null;index:idx_outbox_due,priority:2"`
+    LastError     string     `gorm:"column:last_error;type:text"`
+    SentAt        *time.Time `gorm:"column:sent_at"`
+    CreatedAt     time.Time  `gorm:"column:created_at;autoCreateTime"`
+    UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime"`
+
+    // Relations
+    Booking *Booking `gorm:"foreignKey:BookingID;constraint:OnDelete:SET NULL"`
 }
\ No newline at end of file
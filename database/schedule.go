@@ -0,0 +1,236 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultOperatingHours is used for studios whose old free-form operating hours could not be read.
+var DefaultOperatingHours = DailyHours("09:00", "22:00")
+
+// DayHours is the opening window of one day, as "HH:MM" wall clock times in the studio's timezone.
+// A Close before Open means the studio closes after midnight; Open == Close means open around the clock.
+type DayHours struct {
+    Open  string `json:"open"`
+    Close string `json:"close"`
+}
+
+// WeeklyHours maps a lowercase weekday name ("monday" ... "sunday") to its opening window.
+// A day that is not in the map is closed.
+type WeeklyHours map[string]DayHours
+
+// SessionRange is a session as UTC timestamps of its wall clock times, see SessionBounds.
+type SessionRange struct {
+    From time.Time
+    To   time.Time
+}
+
+// OpenPeriod is a continuous opening period in minutes from midnight of a given date.
+// It may start before 0 (opened the day before) or end after 1440 (closes the next day).
+type OpenPeriod struct {
+    Start int
+    End   int
+}
+
+// DailyHours returns a schedule with the same opening window every day.
+func DailyHours(open, closeAt string) WeeklyHours {
+    schedule := WeeklyHours{}
+    for day := time.Sunday; day <= time.Saturday; day++ {
+        schedule[WeekdayKey(day)] = DayHours{Open: open, Close: closeAt}
+    }
+    return schedule
+}
+
+// WeekdayKey is the key of day in a WeeklyHours, e.g. "monday".
+func WeekdayKey(day time.Weekday) string {
+    return strings.ToLower(day.String())
+}
+
+func (w WeeklyHours) Value() (driver.Value, error) {
+    return json.Marshal(w)
+}
+
+func (w *WeeklyHours) Scan(value interface{}) error {
+    if value == nil {
+        *w = WeeklyHours{}
+        return nil
+    }
+    bytes, ok := value.([]byte)
+    if !ok {
+        return nil
+    }
+    return json.Unmarshal(bytes, w)
+}
+
+// Validate checks the weekday names and times, and that the studio opens at least once a week.
+func (w WeeklyHours) Validate() error {
+    if len(w) == 0 {
+        return fmt.Errorf("operating hours must open on at least one day")
+    }
+    for key, hours := range w {
+        if !isWeekdayKey(key) {
+            return fmt.Errorf("unknown day %q, use monday to sunday", key)
+        }
+        if _, ok := clockMinutes(hours.Open); !ok {
+            return fmt.Errorf("invalid open time %q on %s, use HH:MM", hours.Open, key)
+        }
+        if _, ok := clockMinutes(hours.Close); !ok {
+            return fmt.Errorf("invalid close time %q on %s, use HH:MM", hours.Close, key)
+        }
+    }
+    return nil
+}
+
+// Hours returns the opening window of day, and false if the studio is closed that day.
+func (w WeeklyHours) Hours(day time.Weekday) (DayHours, bool) {
+    hours, ok := w[WeekdayKey(day)]
+    return hours, ok
+}
+
+// OpenPeriods returns the opening periods overlapping date, merged and sorted. Periods of the day
+// before that run past midnight are included, as are periods that continue into the next day.
+func (w WeeklyHours) OpenPeriods(date time.Time) []OpenPeriod {
+    var periods []OpenPeriod
+    for offset := -1; offset <= 1; offset++ {
+        hours, ok := w.Hours(date.AddDate(0, 0, offset).Weekday())
+        if !ok {
+            continue
+        }
+        open, okOpen := clockMinutes(hours.Open)
+        closeAt, okClose := clockMinutes(hours.Close)
+        if !okOpen || !okClose {
+            continue
+        }
+        if closeAt <= open {
+            closeAt += 24 * 60
+        }
+        periods = append(periods, OpenPeriod{Start: open + offset*24*60, End: closeAt + offset*24*60})
+    }
+
+    sort.Slice(periods, func(i, j int) bool { return periods[i].Start < periods[j].Start })
+
+    var merged []OpenPeriod
+    for _, p := range periods {
+        if n := len(merged); n > 0 && p.Start <= merged[n-1].End {
+            merged[n-1].End = max(merged[n-1].End, p.End)
+            continue
+        }
+        merged = append(merged, p)
+    }
+
+    // Keep only what overlaps the date itself
+    result := merged[:0]
+    for _, p := range merged {
+        if p.End > 0 && p.Start < 24*60 {
+            result = append(result, p)
+        }
+    }
+    return result
+}
+
+// Covers reports whether a session on date from startTime to endTime lies within one opening period.
+// An endTime at or before startTime ends the next day.
+func (w WeeklyHours) Covers(date, startTime, endTime time.Time) bool {
+    start, end := SessionMinutes(startTime, endTime)
+    for _, p := range w.OpenPeriods(date) {
+        if p.Start <= start && end <= p.End {
+            return true
+        }
+    }
+    return false
+}
+
+// SubtractPeriods returns the parts of periods that none of busy covers, sorted. Busy periods may
+// overlap each other and extend past the periods.
+func SubtractPeriods(periods, busy []OpenPeriod) []OpenPeriod {
+    sorted := append([]OpenPeriod(nil), busy...)
+    sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+    var free []OpenPeriod
+    for _, p := range periods {
+        start := p.Start
+        for _, b := range sorted {
+            if b.End <= start || b.Start >= p.End {
+                continue
+            }
+            if b.Start > start {
+                free = append(free, OpenPeriod{Start: start, End: b.Start})
+            }
+            start = max(start, b.End)
+            if start >= p.End {
+                break
+            }
+        }
+        if start < p.End {
+            free = append(free, OpenPeriod{Start: start, End: p.End})
+        }
+    }
+
+    sort.Slice(free, func(i, j int) bool { return free[i].Start < free[j].Start })
+    return free
+}
+
+// SessionMinutes returns the start and end of a session in minutes from midnight of its date.
+// An endTime at or before startTime ends the next day, so end may exceed 1440.
+func SessionMinutes(startTime, endTime time.Time) (int, int) {
+    start := startTime.Hour()*60 + startTime.Minute()
+    end := endTime.Hour()*60 + endTime.Minute()
+    if end <= start {
+        end += 24 * 60
+    }
+    return start, end
+}
+
+// SessionBounds returns the start and end of a session as UTC timestamps of its wall clock times,
+// the same way the period column of bookings is generated.
+func SessionBounds(date, startTime, endTime time.Time) (time.Time, time.Time) {
+    start, end := SessionMinutes(startTime, endTime)
+    midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+    return midnight.Add(time.Duration(start) * time.Minute), midnight.Add(time.Duration(end) * time.Minute)
+}
+
+var legacyOperatingHours = regexp.MustCompile(`^\s*(\d{1,2})[:.](\d{2})\s*-\s*(\d{1,2})[:.](\d{2})\s*$`)
+
+// ParseLegacyOperatingHours converts the old free-form value, e.g. "09:00-22:00", into a schedule
+// with that window every day. "24:00" is read as midnight.
+func ParseLegacyOperatingHours(value string) (WeeklyHours, error) {
+    m := legacyOperatingHours.FindStringSubmatch(value)
+    if m == nil {
+        return nil, fmt.Errorf("unrecognised operating hours %q", value)
+    }
+
+    open := fmt.Sprintf("%02s:%s", m[1], m[2])
+    closeAt := fmt.Sprintf("%02s:%s", m[3], m[4])
+    if closeAt == "24:00" {
+        closeAt = "00:00"
+    }
+
+    schedule := DailyHours(open, closeAt)
+    if err := schedule.Validate(); err != nil {
+        return nil, fmt.Errorf("unrecognised operating hours %q", value)
+    }
+    return schedule, nil
+}
+
+func isWeekdayKey(key string) bool {
+    for day := time.Sunday; day <= time.Saturday; day++ {
+        if WeekdayKey(day) == key {
+            return true
+        }
+    }
+    return false
+}
+
+// clockMinutes parses "HH:MM" into minutes from midnight.
+func clockMinutes(value string) (int, bool) {
+    t, err := time.Parse("15:04", value)
+    if err != nil {
+        return 0, false
+    }
+    return t.Hour()*60 + t.Minute(), true
+}
\ No newline at end of file
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// monday is a Monday; the days after it follow the week.
+var monday = time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+func clockAt(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	c, err := time.Parse("15:04", value)
+	if err != nil {
+		t.Fatalf("parse %q: %v", value, err)
+	}
+	return c
+}
+
+func formatPeriods(periods []OpenPeriod) string {
+	if len(periods) == 0 {
+		return "[]"
+	}
+	return fmt.Sprint(periods)
+}
+
+func TestOpenPeriods(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours WeeklyHours
+		date  time.Time
+		want  []OpenPeriod
+	}{
+		{
+			name:  "daytime",
+			hours: DailyHours("09:00", "22:00"),
+			date:  monday,
+			want:  []OpenPeriod{{540, 1320}},
+		},
+		{
+			name:  "overnight every day",
+			hours: DailyHours("20:00", "02:00"),
+			date:  monday,
+			want:  []OpenPeriod{{-240, 120}, {1200, 1560}},
+		},
+		{
+			name:  "open equal to close is around the clock",
+			hours: DailyHours("00:00", "00:00"),
+			date:  monday,
+			want:  []OpenPeriod{{-1440, 2880}},
+		},
+		{
+			name:  "24 hours from a time of day",
+			hours: WeeklyHours{"monday": {Open: "10:00", Close: "10:00"}},
+			date:  monday,
+			want:  []OpenPeriod{{600, 2040}},
+		},
+		{
+			name:  "24 hours of the day before",
+			hours: WeeklyHours{"monday": {Open: "10:00", Close: "10:00"}},
+			date:  monday.AddDate(0, 0, 1),
+			want:  []OpenPeriod{{-840, 600}},
+		},
+		{
+			name: "touching periods join across midnight",
+			hours: WeeklyHours{
+				"monday":  {Open: "18:00", Close: "00:00"},
+				"tuesday": {Open: "00:00", Close: "06:00"},
+			},
+			date: monday,
+			want: []OpenPeriod{{1080, 1800}},
+		},
+		{
+			name: "touching periods seen from the next day",
+			hours: WeeklyHours{
+				"monday":  {Open: "18:00", Close: "00:00"},
+				"tuesday": {Open: "00:00", Close: "06:00"},
+			},
+			date: monday.AddDate(0, 0, 1),
+			want: []OpenPeriod{{-360, 360}},
+		},
+		{
+			name:  "overlapping windows merge",
+			hours: WeeklyHours{"sunday": {Open: "20:00", Close: "04:00"}, "monday": {Open: "02:00", Close: "12:00"}},
+			date:  monday,
+			want:  []OpenPeriod{{-240, 720}},
+		},
+		{
+			name:  "closed day with the night before",
+			hours: WeeklyHours{"sunday": {Open: "22:00", Close: "03:00"}},
+			date:  monday,
+			want:  []OpenPeriod{{-120, 180}},
+		},
+		{
+			name:  "closed day",
+			hours: WeeklyHours{"tuesday": {Open: "09:00", Close: "22:00"}},
+			date:  monday,
+			want:  nil,
+		},
+		{
+			name:  "window ending at midnight does not spill over",
+			hours: WeeklyHours{"sunday": {Open: "18:00", Close: "00:00"}},
+			date:  monday,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.OpenPeriods(tt.date); formatPeriods(got) != formatPeriods(tt.want) {
+				t.Errorf("OpenPeriods = %s, want %s", formatPeriods(got), formatPeriods(tt.want))
+			}
+		})
+	}
+}
+
+func TestCovers(t *testing.T) {
+	touching := WeeklyHours{
+		"monday":  {Open: "18:00", Close: "00:00"},
+		"tuesday": {Open: "00:00", Close: "06:00"},
+	}
+
+	tests := []struct {
+		name       string
+		hours      WeeklyHours
+		date       time.Time
+		start, end string
+		want       bool
+	}{
+		{"inside", DailyHours("09:00", "22:00"), monday, "10:00", "12:00", true},
+		{"whole window", DailyHours("09:00", "22:00"), monday, "09:00", "22:00", true},
+		{"starts before opening", DailyHours("09:00", "22:00"), monday, "08:00", "10:00", false},
+		{"ends after closing", DailyHours("09:00", "22:00"), monday, "21:00", "23:00", false},
+		{"overnight session in overnight window", DailyHours("20:00", "02:00"), monday, "23:00", "01:00", true},
+		{"after midnight in the window of the day before", DailyHours("20:00", "02:00"), monday, "01:00", "02:00", true},
+		{"past the overnight close", DailyHours("20:00", "02:00"), monday, "01:00", "03:00", false},
+		{"between overnight windows", DailyHours("20:00", "02:00"), monday, "02:00", "03:00", false},
+		{"24 hour session when always open", DailyHours("00:00", "00:00"), monday, "22:00", "22:00", true},
+		{"across touching periods", touching, monday, "22:00", "02:00", true},
+		{"past the joined period", touching, monday, "22:00", "07:00", false},
+		{"closed day", WeeklyHours{"tuesday": {Open: "09:00", Close: "22:00"}}, monday, "10:00", "12:00", false},
+		{"spanning a gap between days", WeeklyHours{"monday": {Open: "18:00", Close: "23:00"}, "tuesday": {Open: "00:00", Close: "06:00"}}, monday, "22:00", "01:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Covers(tt.date, clockAt(t, tt.start), clockAt(t, tt.end)); got != tt.want {
+				t.Errorf("Covers(%s-%s) = %v, want %v", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLegacyOperatingHours(t *testing.T) {
+	tests := []struct {
+		value     string
+		open      string
+		closeAt   string
+		wantError bool
+	}{
+		{value: "09:00-22:00", open: "09:00", closeAt: "22:00"},
+		{value: " 9.00 - 22.00 ", open: "09:00", closeAt: "22:00"},
+		{value: "10:00-24:00", open: "10:00", closeAt: "00:00"},
+		{value: "00:00-24:00", open: "00:00", closeAt: "00:00"},
+		{value: "18:00-02:00", open: "18:00", closeAt: "02:00"},
+		{value: "25:00-26:00", wantError: true},
+		{value: "09:60-22:00", wantError: true},
+		{value: "daily", wantError: true},
+		{value: "", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseLegacyOperatingHours(tt.value)
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("ParseLegacyOperatingHours(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLegacyOperatingHours(%q): %v", tt.value, err)
+			}
+
+			if len(got) != 7 {
+				t.Fatalf("schedule has %d days, want all 7", len(got))
+			}
+			for day, hours := range got {
+				if hours.Open != tt.open || hours.Close != tt.closeAt {
+					t.Errorf("%s = %s-%s, want %s-%s", day, hours.Open, hours.Close, tt.open, tt.closeAt)
+				}
+			}
+		})
+	}
+}
+
+func TestWeeklyHoursValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   WeeklyHours
+		wantErr bool
+	}{
+		{"every day", DailyHours("09:00", "22:00"), false},
+		{"one day, overnight", WeeklyHours{"friday": {Open: "22:00", Close: "04:00"}}, false},
+		{"no days", WeeklyHours{}, true},
+		{"unknown day", WeeklyHours{"funday": {Open: "09:00", Close: "22:00"}}, true},
+		{"24:00 is not a time", WeeklyHours{"monday": {Open: "09:00", Close: "24:00"}}, true},
+		{"bad open", WeeklyHours{"monday": {Open: "9am", Close: "22:00"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hours.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubtractPeriods(t *testing.T) {
+	tests := []struct {
+		name    string
+		periods []OpenPeriod
+		busy    []OpenPeriod
+		want    []OpenPeriod
+	}{
+		{"nothing busy", []OpenPeriod{{540, 1320}}, nil, []OpenPeriod{{540, 1320}}},
+		{"busy inside", []OpenPeriod{{540, 1320}}, []OpenPeriod{{600, 720}}, []OpenPeriod{{540, 600}, {720, 1320}}},
+		{"busy at both edges", []OpenPeriod{{540, 1320}}, []OpenPeriod{{1200, 1400}, {500, 540}, {480, 600}}, []OpenPeriod{{600, 1200}}},
+		{"unsorted and overlapping", []OpenPeriod{{0, 1440}}, []OpenPeriod{{700, 800}, {600, 750}, {900, 1000}}, []OpenPeriod{{0, 600}, {800, 900}, {1000, 1440}}},
+		{"touching busy leaves no empty gap", []OpenPeriod{{540, 1320}}, []OpenPeriod{{540, 600}, {600, 660}}, []OpenPeriod{{660, 1320}}},
+		{"fully busy", []OpenPeriod{{540, 1320}}, []OpenPeriod{{0, 1440}}, nil},
+		{"busy outside", []OpenPeriod{{540, 1320}}, []OpenPeriod{{0, 540}, {1320, 1440}}, []OpenPeriod{{540, 1320}}},
+		{
+			name:    "several periods past midnight",
+			periods: []OpenPeriod{{-240, 120}, {1200, 1560}},
+			busy:    []OpenPeriod{{-60, 60}, {1380, 1500}},
+			want:    []OpenPeriod{{-240, -60}, {60, 120}, {1200, 1380}, {1500, 1560}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubtractPeriods(tt.periods, tt.busy); formatPeriods(got) != formatPeriods(tt.want) {
+				t.Errorf("SubtractPeriods = %s, want %s", formatPeriods(got), formatPeriods(tt.want))
+			}
+		})
+	}
+}
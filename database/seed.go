@@ -2,7 +2,9 @@ package database
 
 import (
 	"log"
+	"time"
 
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -35,7 +37,7 @@ func Seed(db *gorm.DB) error {
 // seedDefaultAdmin creates default admin user
 func seedDefaultAdmin(db *gorm.DB) error {
     var count int64
-    db.Model(&User{}).Where("role = ?", "admin").Count(&count)
+    db.Model(&User{}).Where("role = ?", permission.RoleAdmin).Count(&count)
 
     if count > 0 {
         log.Println("⏭️  Admin user already exists, skipping...")
@@ -44,11 +46,14 @@ func seedDefaultAdmin(db *gorm.DB) error {
 
     log.Println("👤 Creating default admin user...")
 
+    verifiedAt := time.Now()
     admin := User{
-        Name:     "Admin",
-        Email:    "admin@studiobooking.com",
-        Password: hashPassword("admin123"),
-        Role:     "admin",
+        Name:            "Admin",
+        Email:           "admin@studiobooking.com",
+        Password:        hashPassword("admin123"),
+        Role:            string(permission.RoleAdmin),
+        EmailVerifiedAt: &verifiedAt,
+        IsActive:        true,
     }
 
     if err := db.Create(&admin).Error; err != nil {
@@ -93,7 +98,7 @@ func seedSampleStudios(db *gorm.DB) error {
                 "Recording Booth",
                 "Lounge Area",
             },
-            OperatingHours: "08:00-23:00",
+            OperatingHours: DailyHours("08:00", "23:00"),
             IsActive:       true,
         },
         {
@@ -110,7 +115,15 @@ func seedSampleStudios(db *gorm.DB) error {
                 "Microphones",
                 "Mixing Console",
             },
-            OperatingHours: "09:00-21:00",
+            // Late sessions on weekends, closed on Sunday
+            OperatingHours: WeeklyHours{
+                "monday":    {Open: "09:00", Close: "21:00"},
+                "tuesday":   {Open: "09:00", Close: "21:00"},
+                "wednesday": {Open: "09:00", Close: "21:00"},
+                "thursday":  {Open: "09:00", Close: "21:00"},
+                "friday":    {Open: "09:00", Close: "02:00"},
+                "saturday":  {Open: "09:00", Close: "02:00"},
+            },
             IsActive:       true,
         },
     }
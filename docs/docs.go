@@ -15,9 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Login dengan email \u0026 password, mengembalikan JWT token",
+        "/admin/closures": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengambil jadwal penutupan studio (maintenance, libur nasional, private event), urut dari yang paling awal",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,29 +30,56 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Closures"
                 ],
-                "summary": "Login user",
+                "summary": "Ambil daftar closure studio (Admin Only)",
                 "parameters": [
                     {
-                        "description": "Data login user",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
-                        }
+                        "type": "integer",
+                        "description": "Closure studio ini, termasuk closure semua studio",
+                        "name": "studio_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hanya closure yang belum selesai atau masih berulang",
+                        "name": "upcoming",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah per halaman",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginResponse"
+                            "$ref": "#/definitions/dto.ClosureListResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload / kredensial salah",
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -59,16 +91,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/profile": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mengambil data profil user berdasarkan JWT token",
+                "description": "Menutup satu studio, atau semua studio jika studio_id kosong, sekali atau berulang (weekly, yearly). Booking yang sudah ada di dalam closure ditandai untuk admin.",
                 "consumes": [
                     "application/json"
                 ],
@@ -76,18 +106,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Closures"
+                ],
+                "summary": "Tutup studio untuk sementara (Admin Only)",
+                "parameters": [
+                    {
+                        "description": "Data closure baru",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateClosureRequest"
+                        }
+                    }
                 ],
-                "summary": "Ambil profil user login",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.ProfileResponse"
+                            "$ref": "#/definitions/dto.ClosureResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized / token invalid",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Studio not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -101,9 +160,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Membuat akun baru (default role: customer)",
+        "/admin/closures/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengambil satu closure beserta booking yang sudah ada di dalamnya dan perlu ditindaklanjuti",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,50 +175,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Closures"
                 ],
-                "summary": "Register user baru",
+                "summary": "Detail closure studio (Admin Only)",
                 "parameters": [
                     {
-                        "description": "Data registrasi user",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RegisterRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID Closure",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.RegisterResponse"
+                            "$ref": "#/definitions/dto.ClosureResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload / validasi gagal",
+                        "description": "Invalid closure ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Closure not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/bookings": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mengambil semua booking milik user login",
+                "description": "Membuka kembali studio; tanda pada booking di dalam closure dihapus",
                 "consumes": [
                     "application/json"
                 ],
@@ -162,38 +234,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Bookings"
+                    "Closures"
                 ],
-                "summary": "Ambil riwayat booking user",
+                "summary": "Hapus closure studio (Admin Only)",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Filter status",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Halaman",
-                        "name": "page",
-                        "in": "query"
-                    },
                     {
                         "type": "integer",
-                        "description": "Jumlah per halaman",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ID Closure",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.BookingListResponse"
+                            "$ref": "#/definitions/dto.DeleteClosureResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid closure ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -203,16 +264,34 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Closure not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Customer membuat booking studio",
+                "description": "Mengubah sebagian field closure. Booking di dalam closure ditandai ulang.",
                 "consumes": [
                     "application/json"
                 ],
@@ -220,29 +299,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Bookings"
+                    "Closures"
                 ],
-                "summary": "Buat booking baru",
+                "summary": "Ubah closure studio (Admin Only)",
                 "parameters": [
                     {
-                        "description": "Data booking",
+                        "type": "integer",
+                        "description": "ID Closure",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Field closure yang diubah",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateBookingRequest"
+                            "$ref": "#/definitions/dto.UpdateClosureRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateBookingResponse"
+                            "$ref": "#/definitions/dto.ClosureResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid closure ID / payload",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -253,8 +339,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Closure not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -262,14 +360,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/bookings/admin": {
+        "/admin/outbox": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mengambil semua booking dengan filter",
+                "description": "Mengambil notifikasi (email, WhatsApp, SMS) yang antri, terkirim atau gagal (dead), terbaru dulu",
                 "consumes": [
                     "application/json"
                 ],
@@ -277,16 +375,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Bookings"
+                    "Outbox"
                 ],
-                "summary": "Ambil semua booking (Admin Only)",
+                "summary": "Ambil daftar notification outbox (Admin Only)",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter status",
+                        "description": "Filter status (pending, sent, dead)",
                         "name": "status",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "Filter jenis notifikasi (booking_created, booking_confirmed, booking_cancelled, booking_expired)",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter channel (email, whatsapp, sms)",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter penerima, email atau nomor telepon (partial match)",
+                        "name": "recipient",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter ID booking",
+                        "name": "booking_id",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Halaman",
@@ -295,7 +417,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Limit",
+                        "description": "Jumlah per halaman",
                         "name": "limit",
                         "in": "query"
                     }
@@ -304,11 +426,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.BookingListResponse"
+                            "$ref": "#/definitions/dto.OutboxListResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -318,18 +440,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bookings/admin/{id}/status": {
-            "put": {
+        "/admin/outbox/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Admin mengubah status booking",
+                "description": "Mengambil status pengiriman satu notifikasi, termasuk jumlah percobaan dan error terakhir",
                 "consumes": [
                     "application/json"
                 ],
@@ -337,36 +471,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Bookings"
+                    "Outbox"
                 ],
-                "summary": "Update status booking (Admin Only)",
+                "summary": "Detail notification outbox (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Booking",
+                        "description": "ID Message",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Status baru",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateBookingStatusRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateBookingStatusResponse"
+                            "$ref": "#/definitions/dto.OutboxMessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid message ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -376,18 +501,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Message not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/bookings/{id}": {
-            "get": {
+        "/admin/outbox/{id}/resend": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Customer/Admin bisa lihat detail booking",
+                "description": "Mengantrikan kembali notifikasi berstatus dead dengan jatah percobaan baru",
                 "consumes": [
                     "application/json"
                 ],
@@ -395,13 +532,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Bookings"
+                    "Outbox"
                 ],
-                "summary": "Ambil detail 1 booking",
+                "summary": "Kirim ulang notifikasi yang gagal (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Booking",
+                        "description": "ID Message",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -411,11 +548,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.BookingResponse"
+                            "$ref": "#/definitions/dto.OutboxMessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid message ID / notifikasi tidak berstatus dead",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -426,8 +563,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Message not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -435,14 +578,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/bookings/{id}/cancel": {
-            "post": {
+        "/admin/users": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Customer membatalkan booking yang masih aktif",
+                "description": "Mengambil daftar user dengan filter role, email, status dan tanggal registrasi",
                 "consumes": [
                     "application/json"
                 ],
@@ -450,119 +593,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Bookings"
+                    "Users"
                 ],
-                "summary": "Batalkan booking",
+                "summary": "Ambil semua user (Admin Only)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Booking",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Filter role (customer, staff, admin)",
+                        "name": "role",
+                        "in": "query"
                     },
                     {
-                        "description": "Alasan pembatalan",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CancelBookingRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/dto.CancelBookingResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
+                        "type": "string",
+                        "description": "Filter email (partial match)",
+                        "name": "email",
+                        "in": "query"
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/studios": {
-            "get": {
-                "description": "Mengambil daftar semua studio dengan filter dan pagination",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Studios"
-                ],
-                "summary": "Ambil semua studio",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter lokasi",
-                        "name": "location",
+                        "description": "Cari berdasarkan nama atau email",
+                        "name": "search",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Harga minimal",
-                        "name": "min_price",
+                        "type": "boolean",
+                        "description": "false = hanya akun yang disuspend",
+                        "name": "is_active",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "Harga maksimal",
-                        "name": "max_price",
+                        "type": "string",
+                        "description": "Terdaftar sejak (YYYY-MM-DD)",
+                        "name": "created_from",
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Hanya studio aktif",
-                        "name": "is_active",
+                        "type": "string",
+                        "description": "Terdaftar sampai (YYYY-MM-DD)",
+                        "name": "created_to",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Cari berdasarkan nama",
-                        "name": "search",
+                        "description": "Sortir (created_asc, created_desc, name_asc, name_desc)",
+                        "name": "sort_by",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 1,
                         "description": "Halaman",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "default": 10,
-                        "description": "Jumlah data per halaman",
+                        "description": "Jumlah per halaman",
                         "name": "limit",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sortir (price_asc, price_desc, name_asc, name_desc)",
-                        "name": "sort_by",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.StudioListResponse"
+                            "$ref": "#/definitions/dto.UserListResponse"
                         }
                     },
                     "400": {
@@ -571,6 +665,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -578,14 +684,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Menambahkan studio baru oleh admin",
+                "description": "Mengambil detail user beserta ringkasan booking",
                 "consumes": [
                     "application/json"
                 ],
@@ -593,29 +701,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Studios"
+                    "Users"
                 ],
-                "summary": "Buat studio baru (Admin Only)",
+                "summary": "Detail user (Admin Only)",
                 "parameters": [
                     {
-                        "description": "Data studio baru",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateStudioRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID User",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateStudioResponse"
+                            "$ref": "#/definitions/dto.UserDetailResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload",
+                        "description": "Invalid user ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -632,8 +738,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -641,9 +747,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/studios/{id}": {
-            "get": {
-                "description": "Mengambil detail studio berdasarkan ID",
+        "/admin/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promote/demote user. Berlaku langsung pada request berikutnya dari user tersebut.",
                 "consumes": [
                     "application/json"
                 ],
@@ -651,52 +762,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Studios"
+                    "Users"
                 ],
-                "summary": "Ambil 1 studio berdasarkan ID",
+                "summary": "Ubah role user (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Studio",
+                        "description": "ID User",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Role baru",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRoleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.StudioResponse"
+                            "$ref": "#/definitions/dto.UpdateUserRoleResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid studio ID",
+                        "description": "Invalid user ID / payload",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Studio not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/users/{id}/suspend": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mengupdate seluruh data studio",
+                "description": "Menonaktifkan akun user dan mencabut semua sesi login",
                 "consumes": [
                     "application/json"
                 ],
@@ -704,24 +832,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Studios"
+                    "Users"
                 ],
-                "summary": "Update studio lengkap (Admin Only)",
+                "summary": "Suspend user (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Studio",
+                        "description": "ID User",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Data update studio",
+                        "description": "Alasan suspend",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateStudioRequest"
+                            "$ref": "#/definitions/dto.SuspendUserRequest"
                         }
                     }
                 ],
@@ -729,11 +857,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateStudioResponse"
+                            "$ref": "#/definitions/dto.UpdateUserStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid studio ID / payload",
+                        "description": "Invalid user ID / payload",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -751,26 +879,22 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Studio not found",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/users/{id}/unlock": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Menghapus studio berdasarkan ID",
+                "description": "Menghapus lockout login akibat terlalu banyak percobaan password yang salah",
                 "consumes": [
                     "application/json"
                 ],
@@ -778,13 +902,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Studios"
+                    "Users"
                 ],
-                "summary": "Hapus studio (Admin Only)",
+                "summary": "Buka kunci login user (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Studio",
+                        "description": "ID User",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -794,11 +918,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.DeleteStudioResponse"
+                            "$ref": "#/definitions/dto.UpdateUserStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid studio ID",
+                        "description": "Invalid user ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -816,26 +940,22 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Studio not found",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/admin/users/{id}/unsuspend": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mengupdate sebagian field studio",
+                "description": "Mengaktifkan kembali akun user yang disuspend",
                 "consumes": [
                     "application/json"
                 ],
@@ -843,36 +963,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Studios"
+                    "Users"
                 ],
-                "summary": "Patch sebagian data studio (Admin Only)",
+                "summary": "Aktifkan kembali user (Admin Only)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID Studio",
+                        "description": "ID User",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Data patch studio",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.PatchStudioRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.PatchStudioResponse"
+                            "$ref": "#/definitions/dto.UpdateUserStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid studio ID / payload",
+                        "description": "Invalid user ID / user tidak disuspend",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -890,7 +1001,38 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Studio not found",
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Menampilkan apakah 2FA aktif, wajib, dan sisa recovery code",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Status 2FA",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -904,9 +1046,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/studios/{id}/availability": {
+        "/auth/2fa/confirm": {
             "post": {
-                "description": "Mengecek apakah studio tersedia pada waktu tertentu",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengaktifkan 2FA dengan kode dari aplikasi authenticator dan mengembalikan recovery code (hanya ditampilkan sekali)",
                 "consumes": [
                     "application/json"
                 ],
@@ -914,24 +1061,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Studios"
+                    "Auth"
                 ],
-                "summary": "Cek jadwal ketersediaan studio",
+                "summary": "Konfirmasi aktivasi 2FA",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID Studio",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Kode 6 digit",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorRecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / kode salah",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/disable": {
+            "post": {
+                "security": [
                     {
-                        "description": "Data untuk pengecekan jadwal",
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Menonaktifkan 2FA dengan verifikasi password dan kode TOTP/recovery code. Tidak bisa jika 2FA wajib untuk role user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Nonaktifkan 2FA",
+                "parameters": [
+                    {
+                        "description": "Password \u0026 kode 2FA",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CheckAvailabilityRequest"
+                            "$ref": "#/definitions/dto.DisableTwoFactorRequest"
                         }
                     }
                 ],
@@ -939,11 +1136,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.AvailabilityResponse"
+                            "$ref": "#/definitions/dto.DisableTwoFactorResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request payload / invalid studio ID",
+                        "description": "Invalid request payload / password atau kode salah",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "2FA wajib untuk role ini",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -956,153 +1165,2709 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.AvailabilityData": {
-            "type": "object",
-            "properties": {
-                "available_slots": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.TimeSlot"
+        },
+        "/auth/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
-                },
-                "booked_slots": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.BookedSlot"
+                ],
+                "description": "Membuat secret TOTP baru dan URI otpauth:// untuk QR code. 2FA belum aktif sampai dikonfirmasi.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Mulai aktivasi 2FA",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorEnrollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "2FA sudah aktif",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
-                },
-                "date": {
-                    "type": "string"
-                },
-                "studio_id": {
-                    "type": "integer"
                 }
             }
         },
-        "dto.AvailabilityResponse": {
-            "type": "object",
-            "properties": {
-                "available": {
-                    "type": "boolean"
-                },
-                "data": {
-                    "$ref": "#/definitions/dto.AvailabilityData"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "success": {
-                    "type": "boolean"
+        "/auth/2fa/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengganti semua recovery code. Recovery code lama tidak berlaku lagi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Buat ulang recovery code",
+                "parameters": [
+                    {
+                        "description": "Kode TOTP atau recovery code",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorRecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / kode salah",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "dto.BookedSlot": {
-            "type": "object",
-            "properties": {
-                "booking_id": {
-                    "type": "integer"
-                },
-                "end_time": {
-                    "type": "string"
-                },
-                "start_time": {
-                    "type": "string"
+        "/auth/change-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengganti password dengan verifikasi password lama. Semua sesi login akan dicabut.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Ganti password",
+                "parameters": [
+                    {
+                        "description": "Password lama \u0026 baru",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / password lama salah",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Mengirim link reset password ke email user. Response selalu sama walaupun email tidak terdaftar.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Lupa password",
+                "parameters": [
+                    {
+                        "description": "Email user",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ForgotPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Login dengan email \u0026 password, mengembalikan JWT token. Jika akun memakai 2FA, response berisi challenge_token untuk /auth/login/2fa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "Data login user",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / kredensial salah",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Akun disuspend",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Terlalu banyak percobaan gagal (code: LOGIN_THROTTLED, ACCOUNT_LOCKED, IP_LOCKED)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login/2fa": {
+            "post": {
+                "description": "Menukar challenge_token dari /auth/login dan kode TOTP (atau recovery code) dengan JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login langkah kedua (2FA)",
+                "parameters": [
+                    {
+                        "description": "Challenge token \u0026 kode 2FA",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginTwoFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Challenge token invalid / kode salah",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Terlalu banyak percobaan gagal",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mencabut access token yang sedang dipakai. Jika refresh_token dikirim, seluruh refresh token dari sesi login tersebut juga dicabut.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
+                    {
+                        "description": "Refresh token (opsional)",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mencabut semua access token \u0026 refresh token milik user login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout dari semua perangkat",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogoutResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengambil data profil user berdasarkan JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Ambil profil user login",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ProfileResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengubah nama dan/atau email. Mengganti email akan mereset status verifikasi dan mengirim email verifikasi baru.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Update profil user login",
+                "parameters": [
+                    {
+                        "description": "Data profil baru",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / email sudah terdaftar",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Menukar refresh token dengan access token \u0026 refresh token baru (rotasi). Refresh token lama tidak bisa dipakai lagi.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh token invalid, expired, atau sudah dipakai",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Membuat akun baru (default role: customer)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register user baru",
+                "parameters": [
+                    {
+                        "description": "Data registrasi user",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / validasi gagal",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-verification": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengirim ulang link verifikasi ke email user login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Kirim ulang email verifikasi",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResendVerificationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Email sudah terverifikasi",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized / token invalid",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Mengganti password menggunakan token dari email reset password. Token hanya bisa dipakai sekali dan semua sesi login akan dicabut.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Token reset \u0026 password baru",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResetPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / token invalid atau expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "get": {
+                "description": "Memverifikasi alamat email menggunakan link yang dikirim saat registrasi",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Verifikasi email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token verifikasi dari email",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.VerifyEmailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token invalid atau expired",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengambil semua booking milik user login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Ambil riwayat booking user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah per halaman",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BookingListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Customer membuat booking studio",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Buat booking baru",
+                "parameters": [
+                    {
+                        "description": "Data booking",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/admin": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengambil semua booking dengan filter",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Ambil semua booking (Staff/Admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hanya booking yang tertutup closure baru",
+                        "name": "flagged",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BookingListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/admin/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Staff/admin mengubah status booking (konfirmasi pembayaran, check-in, pembatalan)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Update status booking (Staff/Admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Booking",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status baru",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateBookingStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateBookingStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Customer/Admin bisa lihat detail booking",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Ambil detail 1 booking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Booking",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Customer membatalkan booking yang masih aktif",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookings"
+                ],
+                "summary": "Batalkan booking",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Booking",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alasan pembatalan",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelBookingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CancelBookingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/studios": {
+            "get": {
+                "description": "Mengambil daftar semua studio dengan filter dan pagination. Dengan date, start_time dan end_time hanya studio aktif yang buka dan belum dibooking atau ditutup pada waktu tersebut.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Ambil semua studio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter lokasi",
+                        "name": "location",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Harga minimal",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Harga maksimal",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hanya studio aktif",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cari berdasarkan nama",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Halaman",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Jumlah data per halaman",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sortir (price_asc, price_desc, name_asc, name_desc)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hanya studio yang kosong pada tanggal ini (YYYY-MM-DD), wajib bersama start_time dan end_time",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Jam mulai (HH:MM)",
+                        "name": "start_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Jam selesai (HH:MM), sebelum start_time berarti hari berikutnya",
+                        "name": "end_time",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StudioListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Menambahkan studio baru oleh admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Buat studio baru (Admin Only)",
+                "parameters": [
+                    {
+                        "description": "Data studio baru",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateStudioRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateStudioResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/studios/{id}": {
+            "get": {
+                "description": "Mengambil detail studio berdasarkan ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Ambil 1 studio berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Studio",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.StudioResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid studio ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Studio not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengupdate seluruh data studio",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Update studio lengkap (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Studio",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data update studio",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateStudioRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateStudioResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid studio ID / payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Studio not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Menghapus studio berdasarkan ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Hapus studio (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Studio",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteStudioResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid studio ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Studio not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mengupdate sebagian field studio",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Patch sebagian data studio (Admin Only)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Studio",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data patch studio",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchStudioRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchStudioResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid studio ID / payload",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden (bukan admin)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Studio not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/studios/{id}/availability": {
+            "post": {
+                "description": "Menghitung semua waktu kosong studio pada tanggal tertentu (jam operasional, booking, closure, buffer dan durasi minimum) beserta jam mulai yang bisa dibooking. Jika start_time dan end_time dikirim, juga mengecek apakah waktu tersebut tersedia.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Cek jadwal ketersediaan studio",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Studio",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data untuk pengecekan jadwal",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CheckAvailabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AvailabilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request payload / invalid studio ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/studios/{id}/calendar": {
+            "get": {
+                "description": "Jam buka, rentang yang sudah dibooking, rentang kosong dan persentase okupansi per hari, maksimal 62 hari sekaligus",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Studios"
+                ],
+                "summary": "Kalender ketersediaan studio",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID Studio",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal awal (YYYY-MM-DD), default hari ini",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD), default 6 hari setelah from",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CalendarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid studio ID / query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Studio not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AdminUserData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "suspend_reason": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "type": "string"
+                },
+                "two_factor_enabled": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AvailabilityData": {
+            "type": "object",
+            "properties": {
+                "available_slots": {
+                    "description": "Every free range at least min_duration long",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TimeSlot"
+                    }
+                },
+                "booked_slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BookedSlot"
+                    }
+                },
+                "buffer_minutes": {
+                    "description": "Free time kept before and after every booking",
+                    "type": "integer"
+                },
+                "closed_slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClosedSlot"
+                    }
+                },
+                "date": {
+                    "type": "string"
+                },
+                "min_duration": {
+                    "description": "Shortest bookable session in minutes",
+                    "type": "integer"
+                },
+                "slot_interval": {
+                    "description": "Minutes between start times",
+                    "type": "integer"
+                },
+                "start_times": {
+                    "description": "Bookable start times for the requested length, or min_duration",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "studio_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "data": {
+                    "$ref": "#/definitions/dto.AvailabilityData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.BookedSlot": {
+            "type": "object",
+            "properties": {
+                "booking_id": {
+                    "type": "integer"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BookingData": {
+            "type": "object",
+            "properties": {
+                "admin_notes": {
+                    "type": "string"
+                },
+                "booking_date": {
+                    "type": "string"
+                },
+                "closure_id": {
+                    "description": "Set when a closure added later covers this booking",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_hours": {
+                    "type": "integer"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payment_deadline": {
+                    "description": "Only meaningful while pending",
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "studio": {
+                    "$ref": "#/definitions/dto.StudioData"
+                },
+                "studio_id": {
+                    "type": "integer"
+                },
+                "total_price": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserData"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.BookingListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BookingData"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.PaginationMeta"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.BookingResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.BookingData"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CalendarData": {
+            "type": "object",
+            "properties": {
+                "buffer_minutes": {
+                    "description": "Free time kept before and after every booking",
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CalendarDay"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "min_duration": {
+                    "description": "Shortest bookable session in minutes",
+                    "type": "integer"
+                },
+                "occupancy": {
+                    "description": "Percentage of the open time that is booked",
+                    "type": "number"
+                },
+                "studio_id": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CalendarDay": {
+            "type": "object",
+            "properties": {
+                "booked_slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TimeSlot"
+                    }
+                },
+                "closed_slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClosedSlot"
+                    }
+                },
+                "date": {
+                    "type": "string"
+                },
+                "free_slots": {
+                    "description": "Free ranges at least min_duration long",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TimeSlot"
+                    }
+                },
+                "occupancy": {
+                    "description": "Percentage of the open time that is booked",
+                    "type": "number"
+                },
+                "open_hours": {
+                    "description": "Empty when the studio is closed that day",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TimeSlot"
+                    }
+                },
+                "weekday": {
+                    "description": "\"monday\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CalendarResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.CalendarData"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CancelBookingRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "minLength": 10
+                }
+            }
+        },
+        "dto.CancelBookingResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password",
+                "new_password_confirmation"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "new_password_confirmation": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ChangePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CheckAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Format: \"2025-11-20\"",
+                    "type": "string"
+                },
+                "end_time": {
+                    "description": "Format: \"17:00\"",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "Format: \"14:00\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ClosedSlot": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "description": "\"2025-12-26 00:00\"",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "description": "\"2025-12-25 00:00\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ClosureData": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "flagged_bookings": {
+                    "description": "Only in detail responses",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FlaggedBookingData"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "none, weekly, yearly",
+                    "type": "string"
+                },
+                "repeat_until": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "studio_id": {
+                    "description": "null: every studio",
+                    "type": "integer"
+                },
+                "studio_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ClosureListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ClosureData"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.PaginationMeta"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ClosureResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.ClosureData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CreateBookingRequest": {
+            "type": "object",
+            "required": [
+                "booking_date",
+                "end_time",
+                "start_time",
+                "studio_id"
+            ],
+            "properties": {
+                "booking_date": {
+                    "description": "YYYY-MM-DD",
+                    "type": "string"
+                },
+                "duration_hours": {
+                    "type": "integer"
+                },
+                "end_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "start_time": {
+                    "description": "HH:MM",
+                    "type": "string"
+                },
+                "studio_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateBookingResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.BookingData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.CreateClosureRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "reason",
+                "starts_at"
+            ],
+            "properties": {
+                "ends_at": {
+                    "description": "\"2025-12-26 00:00\"",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "recurrence": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "weekly",
+                        "yearly"
+                    ]
+                },
+                "repeat_until": {
+                    "description": "YYYY-MM-DD, last day an occurrence may start; empty: forever",
+                    "type": "string"
+                },
+                "starts_at": {
+                    "description": "\"2025-12-25 00:00\", wall clock time of the studio",
+                    "type": "string"
+                },
+                "studio_id": {
+                    "description": "Omit to close every studio",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateStudioRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "facilities",
+                "image_url",
+                "location",
+                "name",
+                "operating_hours",
+                "price_per_hour"
+            ],
+            "properties": {
+                "buffer_minutes": {
+                    "description": "Free time kept before and after every booking",
+                    "type": "integer",
+                    "maximum": 240,
+                    "minimum": 0
+                },
+                "description": {
+                    "type": "string"
+                },
+                "facilities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 3
+                },
+                "operating_hours": {
+                    "$ref": "#/definitions/dto.OperatingHoursData"
+                },
+                "price_per_hour": {
+                    "type": "integer",
+                    "minimum": 10000
+                },
+                "timezone": {
+                    "description": "IANA name, default: \"Asia/Jakarta\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateStudioResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.StudioData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.DayHoursData": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "description": "\"22:00\"; before open: closes after midnight, equal to open: open 24 hours",
+                    "type": "string"
+                },
+                "open": {
+                    "description": "\"09:00\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeleteClosureResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.DeleteStudioResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.DisableTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "recovery_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DisableTwoFactorResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.FlaggedBookingData": {
+            "type": "object",
+            "properties": {
+                "booking_date": {
+                    "type": "string"
+                },
+                "customer_email": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "studio_id": {
+                    "type": "integer"
+                },
+                "studio_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ForgotPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.LoginData": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "Access token lifetime in seconds",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "two_factor_setup_required": {
+                    "description": "Admin must enroll 2FA before using admin permissions",
+                    "type": "boolean"
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserData"
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "challenge": {
+                    "$ref": "#/definitions/dto.TwoFactorChallenge"
+                },
+                "data": {
+                    "$ref": "#/definitions/dto.LoginData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.LoginTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "description": "6-digit code from the authenticator app",
+                    "type": "string"
+                },
+                "recovery_code": {
+                    "description": "Alternative to code, each recovery code works once",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "Optional, revokes the refresh token family too",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.OperatingHoursData": {
+            "type": "object",
+            "properties": {
+                "friday": {
+                    "$ref": "#/definitions/dto.DayHoursData"
+                },
+                "monday": {
+                    "$ref": "#/definitions/dto.DayHoursData"
+                },
+                "saturday": {
+                    "$ref": "#/definitions/dto.DayHoursData"
+                },
+                "sunday": {
+                    "$ref": "#/definitions/dto.DayHoursData"
+                },
+                "thursday": {
+                    "$ref": "#/definitions/dto.DayHoursData"
+                },
+                "tuesday": {
+                    "$ref": "#/definitions/dto.DayHoursData"
+                },
+                "wednesday": {
+                    "$ref": "#/definitions/dto.DayHoursData"
+                }
+            }
+        },
+        "dto.OutboxListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.OutboxMessageData"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/dto.PaginationMeta"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.OutboxMessageData": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "booking_id": {
+                    "type": "integer"
+                },
+                "channel": {
+                    "description": "email, whatsapp, sms",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "Only while pending",
+                    "type": "string"
+                },
+                "recipient": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, sent, dead",
+                    "type": "string"
+                },
+                "subject": {
+                    "description": "Email only",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.OutboxMessageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.OutboxMessageData"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.Pagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "total_records": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PaginationMeta": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "per_page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.BookingData": {
+        "dto.PatchStudioRequest": {
             "type": "object",
             "properties": {
-                "admin_notes": {
-                    "type": "string"
-                },
-                "booking_date": {
-                    "type": "string"
+                "buffer_minutes": {
+                    "type": "integer",
+                    "maximum": 240,
+                    "minimum": 0
                 },
-                "created_at": {
+                "description": {
                     "type": "string"
                 },
-                "duration_hours": {
-                    "type": "integer"
+                "facilities": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "end_time": {
+                "image_url": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "start_time": {
+                "location": {
                     "type": "string"
                 },
-                "status": {
+                "name": {
                     "type": "string"
                 },
-                "studio": {
-                    "$ref": "#/definitions/dto.StudioData"
-                },
-                "studio_id": {
-                    "type": "integer"
+                "operating_hours": {
+                    "$ref": "#/definitions/dto.OperatingHoursData"
                 },
-                "total_price": {
+                "price_per_hour": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "timezone": {
                     "type": "string"
-                },
-                "user": {
-                    "$ref": "#/definitions/dto.UserData"
-                },
-                "user_id": {
-                    "type": "integer"
                 }
             }
         },
-        "dto.BookingListResponse": {
+        "dto.PatchStudioResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.BookingData"
-                    }
+                    "$ref": "#/definitions/dto.StudioData"
                 },
-                "meta": {
-                    "$ref": "#/definitions/dto.PaginationMeta"
+                "message": {
+                    "type": "string"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.BookingResponse": {
+        "dto.ProfileResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/dto.BookingData"
+                    "$ref": "#/definitions/dto.UserData"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.CancelBookingRequest": {
+        "dto.RefreshTokenRequest": {
             "type": "object",
             "required": [
-                "reason"
+                "refresh_token"
             ],
             "properties": {
-                "reason": {
-                    "type": "string",
-                    "minLength": 10
+                "refresh_token": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CancelBookingResponse": {
+        "dto.RefreshTokenResponse": {
             "type": "object",
             "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.LoginData"
+                },
                 "message": {
                     "type": "string"
                 },
@@ -1111,63 +3876,79 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.CheckAvailabilityRequest": {
+        "dto.RegisterRequest": {
             "type": "object",
             "required": [
-                "date",
-                "end_time",
-                "start_time"
+                "email",
+                "name",
+                "password",
+                "password_confirmation"
             ],
             "properties": {
-                "date": {
-                    "description": "Format: \"2025-11-20\"",
+                "email": {
                     "type": "string"
                 },
-                "end_time": {
-                    "description": "Format: \"17:00\"",
+                "name": {
+                    "type": "string",
+                    "minLength": 2
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "password_confirmation": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.UserData"
+                },
+                "message": {
                     "type": "string"
                 },
-                "start_time": {
-                    "description": "Format: \"14:00\"",
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ResendVerificationResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.CreateBookingRequest": {
+        "dto.ResetPasswordRequest": {
             "type": "object",
             "required": [
-                "booking_date",
-                "end_time",
-                "start_time",
-                "studio_id"
+                "password",
+                "password_confirmation",
+                "token"
             ],
             "properties": {
-                "booking_date": {
-                    "description": "YYYY-MM-DD",
-                    "type": "string"
-                },
-                "duration_hours": {
-                    "type": "integer"
+                "password": {
+                    "type": "string",
+                    "minLength": 6
                 },
-                "end_time": {
-                    "description": "HH:MM",
+                "password_confirmation": {
                     "type": "string"
                 },
-                "start_time": {
-                    "description": "HH:MM",
+                "token": {
                     "type": "string"
-                },
-                "studio_id": {
-                    "type": "integer"
                 }
             }
         },
-        "dto.CreateBookingResponse": {
+        "dto.ResetPasswordResponse": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/dto.BookingData"
-                },
                 "message": {
                     "type": "string"
                 },
@@ -1176,18 +3957,15 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.CreateStudioRequest": {
+        "dto.StudioData": {
             "type": "object",
-            "required": [
-                "description",
-                "facilities",
-                "image_url",
-                "location",
-                "name",
-                "operating_hours",
-                "price_per_hour"
-            ],
             "properties": {
+                "buffer_minutes": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -1197,96 +3975,129 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "id": {
+                    "type": "integer"
+                },
                 "image_url": {
                     "type": "string"
                 },
+                "is_active": {
+                    "type": "boolean"
+                },
                 "location": {
                     "type": "string"
                 },
                 "name": {
-                    "type": "string",
-                    "minLength": 3
+                    "type": "string"
                 },
                 "operating_hours": {
-                    "description": "Format: \"09:00-22:00\"",
-                    "type": "string"
+                    "$ref": "#/definitions/dto.OperatingHoursData"
                 },
                 "price_per_hour": {
-                    "type": "integer",
-                    "minimum": 10000
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreateStudioResponse": {
+        "dto.StudioListResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/dto.StudioData"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.StudioData"
+                    }
                 },
-                "message": {
-                    "type": "string"
+                "pagination": {
+                    "$ref": "#/definitions/dto.Pagination"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.DeleteStudioResponse": {
+        "dto.StudioResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/dto.StudioData"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.ErrorResponse": {
+        "dto.SuspendUserRequest": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "code": {
+                "reason": {
+                    "type": "string",
+                    "minLength": 5
+                }
+            }
+        },
+        "dto.TimeSlot": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "description": "\"12:00\"; before start_time: ends the next day",
                     "type": "string"
                 },
-                "error": {
+                "start_time": {
+                    "description": "\"09:00\"",
                     "type": "string"
-                },
-                "success": {
-                    "type": "boolean"
                 }
             }
         },
-        "dto.LoginData": {
+        "dto.TwoFactorChallenge": {
             "type": "object",
             "properties": {
-                "token": {
+                "challenge_token": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/dto.UserData"
+                "expires_in": {
+                    "description": "Challenge lifetime in seconds",
+                    "type": "integer"
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.TwoFactorCodeRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "code"
             ],
             "properties": {
-                "email": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorEnrollData": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "description": "Encode as QR code for authenticator apps",
                     "type": "string"
                 },
-                "password": {
+                "secret": {
+                    "description": "Base32 secret for manual entry",
                     "type": "string"
                 }
             }
         },
-        "dto.LoginResponse": {
+        "dto.TwoFactorEnrollResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/dto.LoginData"
+                    "$ref": "#/definitions/dto.TwoFactorEnrollData"
                 },
                 "message": {
                     "type": "string"
@@ -1296,77 +4107,95 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.Pagination": {
+        "dto.TwoFactorRecoveryCodesData": {
             "type": "object",
             "properties": {
-                "current_page": {
-                    "type": "integer"
-                },
-                "page_size": {
-                    "type": "integer"
+                "recovery_codes": {
+                    "description": "Shown only once",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.TwoFactorRecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/dto.TwoFactorRecoveryCodesData"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "message": {
+                    "type": "string"
                 },
-                "total_records": {
-                    "type": "integer"
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.PaginationMeta": {
+        "dto.TwoFactorStatusData": {
             "type": "object",
             "properties": {
-                "current_page": {
-                    "type": "integer"
-                },
-                "per_page": {
-                    "type": "integer"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "total": {
+                "recovery_codes_remaining": {
                     "type": "integer"
                 },
-                "total_pages": {
-                    "type": "integer"
+                "required": {
+                    "description": "Mandatory for this account's role",
+                    "type": "boolean"
                 }
             }
         },
-        "dto.PatchStudioRequest": {
+        "dto.TwoFactorStatusResponse": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "facilities": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "image_url": {
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/dto.TwoFactorStatusData"
                 },
-                "is_active": {
+                "success": {
                     "type": "boolean"
-                },
-                "location": {
+                }
+            }
+        },
+        "dto.TwoFactorVerifyRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
                 },
-                "name": {
+                "recovery_code": {
                     "type": "string"
-                },
-                "operating_hours": {
+                }
+            }
+        },
+        "dto.UpdateBookingStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "admin_notes": {
+                    "description": "Catatan pembayaran/perubahan status",
                     "type": "string"
                 },
-                "price_per_hour": {
-                    "type": "integer"
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "confirmed",
+                        "completed",
+                        "cancelled"
+                    ]
                 }
             }
         },
-        "dto.PatchStudioResponse": {
+        "dto.UpdateBookingStatusResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/dto.StudioData"
+                    "$ref": "#/definitions/dto.BookingData"
                 },
                 "message": {
                     "type": "string"
@@ -1376,25 +4205,36 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.ProfileResponse": {
+        "dto.UpdateClosureRequest": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/dto.UserData"
+                "ends_at": {
+                    "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "recurrence": {
+                    "type": "string",
+                    "enum": [
+                        "none",
+                        "weekly",
+                        "yearly"
+                    ]
+                },
+                "repeat_until": {
+                    "description": "\"\" repeats forever",
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
                 }
             }
         },
-        "dto.RegisterRequest": {
+        "dto.UpdateProfileRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "password_confirmation"
-            ],
             "properties": {
                 "email": {
                     "type": "string"
@@ -1403,16 +4243,22 @@ const docTemplate = `{
                     "type": "string",
                     "minLength": 2
                 },
-                "password": {
+                "notification_channel": {
+                    "description": "Where booking notifications are sent",
                     "type": "string",
-                    "minLength": 6
+                    "enum": [
+                        "email",
+                        "whatsapp",
+                        "sms"
+                    ]
                 },
-                "password_confirmation": {
+                "phone": {
+                    "description": "E.164, e.g. +6281234567890; \"\" removes it",
                     "type": "string"
                 }
             }
         },
-        "dto.RegisterResponse": {
+        "dto.UpdateProfileResponse": {
             "type": "object",
             "properties": {
                 "data": {
@@ -1426,11 +4272,13 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.StudioData": {
+        "dto.UpdateStudioRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "buffer_minutes": {
+                    "type": "integer",
+                    "maximum": 240,
+                    "minimum": 0
                 },
                 "description": {
                     "type": "string"
@@ -1441,9 +4289,6 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
-                "id": {
-                    "type": "integer"
-                },
                 "image_url": {
                     "type": "string"
                 },
@@ -1454,157 +4299,217 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "minLength": 3
                 },
                 "operating_hours": {
-                    "type": "string"
+                    "$ref": "#/definitions/dto.OperatingHoursData"
                 },
                 "price_per_hour": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 10000
                 },
-                "updated_at": {
+                "timezone": {
                     "type": "string"
                 }
             }
         },
-        "dto.StudioListResponse": {
+        "dto.UpdateStudioResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.StudioData"
-                    }
+                    "$ref": "#/definitions/dto.StudioData"
                 },
-                "pagination": {
-                    "$ref": "#/definitions/dto.Pagination"
+                "message": {
+                    "type": "string"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.StudioResponse": {
+        "dto.UpdateUserRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "customer",
+                        "staff",
+                        "admin"
+                    ]
+                }
+            }
+        },
+        "dto.UpdateUserRoleResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/dto.StudioData"
+                    "$ref": "#/definitions/dto.AdminUserData"
+                },
+                "message": {
+                    "type": "string"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.TimeSlot": {
+        "dto.UpdateUserStatusResponse": {
             "type": "object",
             "properties": {
-                "end_time": {
-                    "description": "\"12:00\"",
-                    "type": "string"
+                "data": {
+                    "$ref": "#/definitions/dto.AdminUserData"
                 },
-                "start_time": {
-                    "description": "\"09:00\"",
+                "message": {
                     "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
                 }
             }
         },
-        "dto.UpdateBookingStatusRequest": {
+        "dto.UserBookingSummary": {
             "type": "object",
-            "required": [
-                "status"
-            ],
             "properties": {
-                "admin_notes": {
-                    "description": "Catatan pembayaran/perubahan status",
-                    "type": "string"
+                "cancelled": {
+                    "type": "integer"
                 },
-                "status": {
-                    "type": "string",
-                    "enum": [
-                        "pending",
-                        "confirmed",
-                        "completed",
-                        "cancelled"
-                    ]
+                "completed": {
+                    "type": "integer"
+                },
+                "confirmed": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "total_bookings": {
+                    "type": "integer"
+                },
+                "total_spent": {
+                    "description": "Sum of confirmed and completed bookings",
+                    "type": "integer"
                 }
             }
         },
-        "dto.UpdateBookingStatusResponse": {
+        "dto.UserData": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/dto.BookingData"
+                "email": {
+                    "type": "string"
                 },
-                "message": {
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
-                "success": {
+                "notification_channel": {
+                    "description": "email, whatsapp, sms",
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "two_factor_enabled": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.UpdateStudioRequest": {
+        "dto.UserDetailData": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
+                "booking_summary": {
+                    "$ref": "#/definitions/dto.UserBookingSummary"
                 },
-                "facilities": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "created_at": {
+                    "type": "string"
                 },
-                "image_url": {
+                "email": {
                     "type": "string"
                 },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "is_active": {
                     "type": "boolean"
                 },
-                "location": {
+                "locked_until": {
+                    "description": "Set while login is locked after failed attempts",
                     "type": "string"
                 },
                 "name": {
-                    "type": "string",
-                    "minLength": 3
+                    "type": "string"
                 },
-                "operating_hours": {
+                "role": {
                     "type": "string"
                 },
-                "price_per_hour": {
-                    "type": "integer",
-                    "minimum": 10000
+                "suspend_reason": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "type": "string"
+                },
+                "two_factor_enabled": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "dto.UpdateStudioResponse": {
+        "dto.UserDetailResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/dto.StudioData"
-                },
-                "message": {
-                    "type": "string"
+                    "$ref": "#/definitions/dto.UserDetailData"
                 },
                 "success": {
                     "type": "boolean"
                 }
             }
         },
-        "dto.UserData": {
+        "dto.UserListResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdminUserData"
+                    }
                 },
-                "id": {
-                    "type": "integer"
+                "meta": {
+                    "$ref": "#/definitions/dto.PaginationMeta"
                 },
-                "name": {
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.VerifyEmailResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
                 },
-                "role": {
-                    "type": "string"
+                "success": {
+                    "type": "boolean"
                 }
             }
         }
@@ -20,15 +20,166 @@ type LoginRequest struct {
     Password string `json:"password" binding:"required"`
 }
 
+// LoginResponse carries either the tokens (Data) or, when the account has 2FA
+// enabled, a challenge to complete at /auth/login/2fa (TwoFactorRequired + Challenge)
 type LoginResponse struct {
+    Success           bool                `json:"success"`
+    Message           string              `json:"message"`
+    TwoFactorRequired bool                `json:"two_factor_required"`
+    Data              *LoginData          `json:"data,omitempty"`
+    Challenge         *TwoFactorChallenge `json:"challenge,omitempty"`
+}
+
+type LoginData struct {
+    Token                  string   `json:"token"`
+    RefreshToken           string   `json:"refresh_token"`
+    ExpiresIn              int      `json:"expires_in"`                          // Access token lifetime in seconds
+    TwoFactorSetupRequired bool     `json:"two_factor_setup_required,omitempty"` // Admin must enroll 2FA before using admin permissions
+    User                   UserData `json:"user"`
+}
+
+type TwoFactorChallenge struct {
+    ChallengeToken string `json:"challenge_token"`
+    ExpiresIn      int    `json:"expires_in"` // Challenge lifetime in seconds
+}
+
+// Login 2FA Request (second login step)
+type LoginTwoFactorRequest struct {
+    ChallengeToken string `json:"challenge_token" binding:"required"`
+    Code           string `json:"code"`          // 6-digit code from the authenticator app
+    RecoveryCode   string `json:"recovery_code"` // Alternative to code, each recovery code works once
+}
+
+// Refresh Token Request & Response
+type RefreshTokenRequest struct {
+    RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type RefreshTokenResponse struct {
     Success bool      `json:"success"`
     Message string    `json:"message"`
     Data    LoginData `json:"data"`
 }
 
-type LoginData struct {
-    Token string   `json:"token"`
-    User  UserData `json:"user"`
+// Logout Request & Response
+type LogoutRequest struct {
+    RefreshToken string `json:"refresh_token"` // Optional, revokes the refresh token family too
+}
+
+type LogoutResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+// Forgot / Reset Password Request & Response
+type ForgotPasswordRequest struct {
+    Email string `json:"email" binding:"required,email"`
+}
+
+type ForgotPasswordResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+type ResetPasswordRequest struct {
+    Token           string `json:"token" binding:"required"`
+    Password        string `json:"password" binding:"required,min=6"`
+    PasswordConfirm string `json:"password_confirmation" binding:"required,eqfield=Password"`
+}
+
+type ResetPasswordResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+// Email Verification Response
+type VerifyEmailResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+type ResendVerificationResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+// Update Profile Request & Response
+type UpdateProfileRequest struct {
+    Name                *string `json:"name" binding:"omitempty,min=2"`
+    Email               *string `json:"email" binding:"omitempty,email"`
+    Phone               *string `json:"phone" binding:"omitempty,e164"`                                     // E.164, e.g. +6281234567890; "" removes it
+    NotificationChannel *string `json:"notification_channel" binding:"omitempty,oneof=email whatsapp sms"` // Where booking notifications are sent
+}
+
+type UpdateProfileResponse struct {
+    Success bool     `json:"success"`
+    Message string   `json:"message"`
+    Data    UserData `json:"data"`
+}
+
+// Change Password Request & Response
+type ChangePasswordRequest struct {
+    CurrentPassword    string `json:"current_password" binding:"required"`
+    NewPassword        string `json:"new_password" binding:"required,min=6"`
+    NewPasswordConfirm string `json:"new_password_confirmation" binding:"required,eqfield=NewPassword"`
+}
+
+type ChangePasswordResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+// Two-Factor Authentication Requests & Responses
+type TwoFactorCodeRequest struct {
+    Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type TwoFactorVerifyRequest struct {
+    Code         string `json:"code"`
+    RecoveryCode string `json:"recovery_code"`
+}
+
+type DisableTwoFactorRequest struct {
+    Password     string `json:"password" binding:"required"`
+    Code         string `json:"code"`
+    RecoveryCode string `json:"recovery_code"`
+}
+
+type TwoFactorEnrollResponse struct {
+    Success bool                `json:"success"`
+    Message string              `json:"message"`
+    Data    TwoFactorEnrollData `json:"data"`
+}
+
+type TwoFactorEnrollData struct {
+    Secret     string `json:"secret"`      // Base32 secret for manual entry
+    OTPAuthURI string `json:"otpauth_uri"` // Encode as QR code for authenticator apps
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+    Success bool                       `json:"success"`
+    Message string                     `json:"message"`
+    Data    TwoFactorRecoveryCodesData `json:"data"`
+}
+
+type TwoFactorRecoveryCodesData struct {
+    RecoveryCodes []string `json:"recovery_codes"` // Shown only once
+}
+
+type TwoFactorStatusResponse struct {
+    Success bool                `json:"success"`
+    Data    TwoFactorStatusData `json:"data"`
+}
+
+type TwoFactorStatusData struct {
+    Enabled                bool  `json:"enabled"`
+    Required               bool  `json:"required"` // Mandatory for this account's role
+    RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+type DisableTwoFactorResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
 }
 
 // Profile Response
@@ -39,10 +190,14 @@ type ProfileResponse struct {
 
 // Common User Data
 type UserData struct {
-    ID    int    `json:"id"`
-    Name  string `json:"name"`
-    Email string `json:"email"`
-    Role  string `json:"role"`
+    ID               int    `json:"id"`
+    Name             string `json:"name"`
+    Email            string `json:"email"`
+    Role             string `json:"role"`
+    EmailVerified    bool   `json:"email_verified"`
+    TwoFactorEnabled bool   `json:"two_factor_enabled"`
+    Phone            string `json:"phone,omitempty"`
+    NotifyChannel    string `json:"notification_channel"` // email, whatsapp, sms
 }
 
 // Error Response (reusable)
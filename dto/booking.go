@@ -16,12 +16,13 @@ type UpdateBookingStatusRequest struct {
 }
 
 type BookingFilterRequest struct {
-    Status     string `form:"status"`       // pending, confirmed, completed, cancelled
+    Status     string `form:"status"`       // pending, confirmed, completed, cancelled, expired
     StudioID   int    `form:"studio_id"`    // Filter by studio
     UserID     int    `form:"user_id"`      // Filter by user (admin only)
     StartDate  string `form:"start_date"`   // Filter from date (YYYY-MM-DD)
     EndDate    string `form:"end_date"`     // Filter to date (YYYY-MM-DD)
     SortBy     string `form:"sort_by"`      // date_asc, date_desc, created_asc, created_desc
+    Flagged    bool   `form:"flagged"`      // Only bookings inside a closure added later (admin only)
     Page       int    `form:"page" binding:"min=1"`
     Limit      int    `form:"limit" binding:"min=1,max=100"`
 }
@@ -63,20 +64,22 @@ type UpdateBookingStatusResponse struct {
 // ============= DATA DTOs =============
 
 type BookingData struct {
-    ID            int         `json:"id"`
-    UserID        int         `json:"user_id"`
-    User          *UserData   `json:"user,omitempty"`
-    StudioID      int         `json:"studio_id"`
-    Studio        *StudioData `json:"studio,omitempty"`
-    BookingDate   string      `json:"booking_date"`
-    StartTime     string      `json:"start_time"`
-    EndTime       string      `json:"end_time"`
-    DurationHours int         `json:"duration_hours"`
-    TotalPrice    int         `json:"total_price"`
-    Status        string      `json:"status"`
-    AdminNotes    string      `json:"admin_notes,omitempty"`
-    CreatedAt     string      `json:"created_at"`
-    UpdatedAt     string      `json:"updated_at"`
+    ID              int         `json:"id"`
+    UserID          int         `json:"user_id"`
+    User            *UserData   `json:"user,omitempty"`
+    StudioID        int         `json:"studio_id"`
+    Studio          *StudioData `json:"studio,omitempty"`
+    BookingDate     string      `json:"booking_date"`
+    StartTime       string      `json:"start_time"`
+    EndTime         string      `json:"end_time"`
+    DurationHours   int         `json:"duration_hours"`
+    TotalPrice      int         `json:"total_price"`
+    Status          string      `json:"status"`
+    AdminNotes      string      `json:"admin_notes,omitempty"`
+    PaymentDeadline string      `json:"payment_deadline,omitempty"` // Only meaningful while pending
+    ClosureID       *int        `json:"closure_id,omitempty"`       // Set when a closure added later covers this booking
+    CreatedAt       string      `json:"created_at"`
+    UpdatedAt       string      `json:"updated_at"`
 }
 
 // PaginationMeta - Metadata untuk pagination
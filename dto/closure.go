@@ -0,0 +1,82 @@
+package dto
+
+// ============= REQUEST DTOs =============
+
+// CreateClosureRequest - Admin block a studio, or every studio, for a while
+type CreateClosureRequest struct {
+    StudioID    *int   `json:"studio_id"` // Omit to close every studio
+    Reason      string `json:"reason" binding:"required,min=3,max=255"`
+    StartsAt    string `json:"starts_at" binding:"required"` // "2025-12-25 00:00", wall clock time of the studio
+    EndsAt      string `json:"ends_at" binding:"required"`   // "2025-12-26 00:00"
+    Recurrence  string `json:"recurrence" binding:"omitempty,oneof=none weekly yearly"`
+    RepeatUntil string `json:"repeat_until"` // YYYY-MM-DD, last day an occurrence may start; empty: forever
+}
+
+// UpdateClosureRequest - Admin change a closure; send only the fields to change
+type UpdateClosureRequest struct {
+    Reason      *string `json:"reason" binding:"omitempty,min=3,max=255"`
+    StartsAt    *string `json:"starts_at"`
+    EndsAt      *string `json:"ends_at"`
+    Recurrence  *string `json:"recurrence" binding:"omitempty,oneof=none weekly yearly"`
+    RepeatUntil *string `json:"repeat_until"` // "" repeats forever
+}
+
+// ClosureFilterRequest - Query params for admin closure listing
+type ClosureFilterRequest struct {
+    StudioID int  `form:"studio_id"` // Closures of this studio, plus those of every studio
+    Upcoming bool `form:"upcoming"`  // Only closures that haven't ended yet, or still repeat
+    Page     int  `form:"page" binding:"omitempty,min=1"`
+    Limit    int  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ============= RESPONSE DTOs =============
+
+// ClosureListResponse - List of closures with pagination
+type ClosureListResponse struct {
+    Success bool           `json:"success"`
+    Data    []ClosureData  `json:"data"`
+    Meta    PaginationMeta `json:"meta"`
+}
+
+// ClosureResponse - Single closure, with the bookings it flagged
+type ClosureResponse struct {
+    Success bool        `json:"success"`
+    Message string      `json:"message,omitempty"`
+    Data    ClosureData `json:"data"`
+}
+
+// DeleteClosureResponse - Delete closure response
+type DeleteClosureResponse struct {
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+// ============= DATA DTOs =============
+
+// ClosureData - Closure information
+type ClosureData struct {
+    ID              int                  `json:"id"`
+    StudioID        *int                 `json:"studio_id"` // null: every studio
+    StudioName      string               `json:"studio_name,omitempty"`
+    Reason          string               `json:"reason"`
+    StartsAt        string               `json:"starts_at"`
+    EndsAt          string               `json:"ends_at"`
+    Recurrence      string               `json:"recurrence"` // none, weekly, yearly
+    RepeatUntil     string               `json:"repeat_until,omitempty"`
+    FlaggedBookings []FlaggedBookingData `json:"flagged_bookings,omitempty"` // Only in detail responses
+    CreatedAt       string               `json:"created_at"`
+    UpdatedAt       string               `json:"updated_at"`
+}
+
+// FlaggedBookingData - Booking that already existed inside a closure, for the admin to follow up
+type FlaggedBookingData struct {
+    ID            int    `json:"id"`
+    StudioID      int    `json:"studio_id"`
+    StudioName    string `json:"studio_name"`
+    CustomerName  string `json:"customer_name"`
+    CustomerEmail string `json:"customer_email"`
+    BookingDate   string `json:"booking_date"`
+    StartTime     string `json:"start_time"`
+    EndTime       string `json:"end_time"`
+    Status        string `json:"status"`
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package dto
+
+// ============= REQUEST DTOs =============
+
+// OutboxFilterRequest - Query params for admin outbox listing
+type OutboxFilterRequest struct {
+    Status    string `form:"status" binding:"omitempty,oneof=pending sent dead"`
+    Kind      string `form:"kind"`      // booking_created, booking_confirmed, booking_cancelled, booking_expired
+    Channel   string `form:"channel" binding:"omitempty,oneof=email whatsapp sms"`
+    Recipient string `form:"recipient"` // Partial match on recipient email or phone number
+    BookingID int    `form:"booking_id"`
+    Page      int    `form:"page" binding:"omitempty,min=1"`
+    Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ============= RESPONSE DTOs =============
+
+// OutboxListResponse - List of outbox messages with pagination
+type OutboxListResponse struct {
+    Success bool                `json:"success"`
+    Data    []OutboxMessageData `json:"data"`
+    Meta    PaginationMeta      `json:"meta"`
+}
+
+// OutboxMessageResponse - Single outbox message
+type OutboxMessageResponse struct {
+    Success bool              `json:"success"`
+    Message string            `json:"message,omitempty"`
+    Data    OutboxMessageData `json:"data"`
+}
+
+// ============= DATA DTOs =============
+
+// OutboxMessageData - Outbox message information visible to admins (without the rendered body)
+type OutboxMessageData struct {
+    ID            int    `json:"id"`
+    Kind          string `json:"kind"`
+    Channel       string `json:"channel"` // email, whatsapp, sms
+    Recipient     string `json:"recipient"`
+    Subject       string `json:"subject,omitempty"` // Email only
+    BookingID     *int   `json:"booking_id,omitempty"`
+    Status        string `json:"status"` // pending, sent, dead
+    Attempts      int    `json:"attempts"`
+    NextAttemptAt string `json:"next_attempt_at,omitempty"` // Only while pending
+    LastError     string `json:"last_error,omitempty"`
+    SentAt        string `json:"sent_at,omitempty"`
+    CreatedAt     string `json:"created_at"`
+    UpdatedAt     string `json:"updated_at"`
+}
\ No newline at end of file
@@ -4,25 +4,29 @@ package dto
 
 // CreateStudioRequest - Admin create new studio
 type CreateStudioRequest struct {
-    Name           string   `json:"name" binding:"required,min=3"`
-    Description    string   `json:"description" binding:"required"`
-    Location       string   `json:"location" binding:"required"`
-    PricePerHour   int      `json:"price_per_hour" binding:"required,min=10000"`
-    ImageURL       string   `json:"image_url" binding:"required,url"`
-    Facilities     []string `json:"facilities" binding:"required"`
-    OperatingHours string   `json:"operating_hours" binding:"required"` // Format: "09:00-22:00"
+    Name           string              `json:"name" binding:"required,min=3"`
+    Description    string              `json:"description" binding:"required"`
+    Location       string              `json:"location" binding:"required"`
+    PricePerHour   int                 `json:"price_per_hour" binding:"required,min=10000"`
+    ImageURL       string              `json:"image_url" binding:"required,url"`
+    Facilities     []string            `json:"facilities" binding:"required"`
+    OperatingHours *OperatingHoursData `json:"operating_hours" binding:"required"`
+    Timezone       string              `json:"timezone"` // IANA name, default: "Asia/Jakarta"
+    BufferMinutes  int                 `json:"buffer_minutes" binding:"min=0,max=240"` // Free time kept before and after every booking
 }
 
 // UpdateStudioRequest - Admin update studio
 type UpdateStudioRequest struct {
-    Name           *string  `json:"name" binding:"omitempty,min=3"`
-    Description    *string  `json:"description"`
-    Location       *string  `json:"location"`
-    PricePerHour   *int     `json:"price_per_hour" binding:"omitempty,min=10000"`
-    ImageURL       *string  `json:"image_url" binding:"omitempty,url"`
-    Facilities     []string `json:"facilities"`
-    OperatingHours *string  `json:"operating_hours"`
-    IsActive       *bool    `json:"is_active"`
+    Name           *string             `json:"name" binding:"omitempty,min=3"`
+    Description    *string             `json:"description"`
+    Location       *string             `json:"location"`
+    PricePerHour   *int                `json:"price_per_hour" binding:"omitempty,min=10000"`
+    ImageURL       *string             `json:"image_url" binding:"omitempty,url"`
+    Facilities     []string            `json:"facilities"`
+    OperatingHours *OperatingHoursData `json:"operating_hours"`
+    Timezone       *string             `json:"timezone"`
+    BufferMinutes  *int                `json:"buffer_minutes" binding:"omitempty,min=0,max=240"`
+    IsActive       *bool               `json:"is_active"`
 }
 
 // StudioFilterRequest - Query params for listing studios
@@ -35,24 +39,36 @@ type StudioFilterRequest struct {
     Page         int    `form:"page" binding:"min=1"`
     Limit        int    `form:"limit" binding:"min=1,max=100"`
     SortBy       string `form:"sort_by"` // price_asc, price_desc, name_asc, name_desc
+    Date         string `form:"date"`       // YYYY-MM-DD; with start_time and end_time, only studios free at that time
+    StartTime    string `form:"start_time"` // "14:00"
+    EndTime      string `form:"end_time"`   // "17:00"; before start_time: ends the next day
 }
 
-// CheckAvailabilityRequest - Check studio availability
+// CheckAvailabilityRequest - Check studio availability; without start_time and end_time only the free time
+// of the date is returned
 type CheckAvailabilityRequest struct {
     Date      string `json:"date" binding:"required"` // Format: "2025-11-20"
-    StartTime string `json:"start_time" binding:"required"` // Format: "14:00"
-    EndTime   string `json:"end_time" binding:"required"`   // Format: "17:00"
+    StartTime string `json:"start_time"` // Format: "14:00"
+    EndTime   string `json:"end_time"`   // Format: "17:00"
+}
+
+// CalendarRequest - Query params for the availability calendar of a studio
+type CalendarRequest struct {
+    From string `form:"from"` // YYYY-MM-DD, default today in the studio's timezone
+    To   string `form:"to"`   // YYYY-MM-DD inclusive, default 6 days after from
 }
 
 type PatchStudioRequest struct {
-    Name           *string  `json:"name,omitempty"`
-    Description    *string  `json:"description,omitempty"`
-    Location       *string  `json:"location,omitempty"`
-    PricePerHour   *int     `json:"price_per_hour,omitempty"`
-    ImageURL       *string  `json:"image_url,omitempty"`
-    Facilities     []string `json:"facilities,omitempty"`
-    OperatingHours *string  `json:"operating_hours,omitempty"`
-    IsActive       *bool    `json:"is_active,omitempty"`
+    Name           *string             `json:"name,omitempty"`
+    Description    *string             `json:"description,omitempty"`
+    Location       *string             `json:"location,omitempty"`
+    PricePerHour   *int                `json:"price_per_hour,omitempty"`
+    ImageURL       *string             `json:"image_url,omitempty"`
+    Facilities     []string            `json:"facilities,omitempty"`
+    OperatingHours *OperatingHoursData `json:"operating_hours,omitempty"`
+    Timezone       *string             `json:"timezone,omitempty"`
+    BufferMinutes  *int                `json:"buffer_minutes,omitempty" binding:"omitempty,min=0,max=240"`
+    IsActive       *bool               `json:"is_active,omitempty"`
 }
 
 
@@ -73,17 +89,36 @@ type StudioListResponse struct {
 
 // StudioData - Studio information
 type StudioData struct {
-    ID             int      `json:"id"`
-    Name           string   `json:"name"`
-    Description    string   `json:"description"`
-    Location       string   `json:"location"`
-    PricePerHour   int      `json:"price_per_hour"`
-    ImageURL       string   `json:"image_url"`
-    Facilities     []string `json:"facilities"`
-    OperatingHours string   `json:"operating_hours"`
-    IsActive       bool     `json:"is_active"`
-    CreatedAt      string   `json:"created_at"`
-    UpdatedAt      string   `json:"updated_at"`
+    ID             int                `json:"id"`
+    Name           string             `json:"name"`
+    Description    string             `json:"description"`
+    Location       string             `json:"location"`
+    PricePerHour   int                `json:"price_per_hour"`
+    ImageURL       string             `json:"image_url"`
+    Facilities     []string           `json:"facilities"`
+    OperatingHours OperatingHoursData `json:"operating_hours"`
+    Timezone       string             `json:"timezone"`
+    BufferMinutes  int                `json:"buffer_minutes"`
+    IsActive       bool               `json:"is_active"`
+    CreatedAt      string             `json:"created_at"`
+    UpdatedAt      string             `json:"updated_at"`
+}
+
+// OperatingHoursData - Weekly opening hours in the studio's timezone. A missing or null day is closed.
+type OperatingHoursData struct {
+    Monday    *DayHoursData `json:"monday"`
+    Tuesday   *DayHoursData `json:"tuesday"`
+    Wednesday *DayHoursData `json:"wednesday"`
+    Thursday  *DayHoursData `json:"thursday"`
+    Friday    *DayHoursData `json:"friday"`
+    Saturday  *DayHoursData `json:"saturday"`
+    Sunday    *DayHoursData `json:"sunday"`
+}
+
+// DayHoursData - Opening window of one day
+type DayHoursData struct {
+    Open  string `json:"open"`  // "09:00"
+    Close string `json:"close"` // "22:00"; before open: closes after midnight, equal to open: open 24 hours
 }
 
 // AvailabilityResponse - Studio availability check result
@@ -94,18 +129,30 @@ type AvailabilityResponse struct {
     Data      *AvailabilityData  `json:"data,omitempty"`
 }
 
-// AvailabilityData - Available time slots
+// AvailabilityData - Free time of the studio on the date
 type AvailabilityData struct {
     StudioID       int             `json:"studio_id"`
     Date           string          `json:"date"`
-    AvailableSlots []TimeSlot      `json:"available_slots"`
+    AvailableSlots []TimeSlot      `json:"available_slots"` // Every free range at least min_duration long
+    StartTimes     []string        `json:"start_times"`     // Bookable start times for the requested length, or min_duration
+    SlotInterval   int             `json:"slot_interval"`   // Minutes between start times
+    MinDuration    int             `json:"min_duration"`    // Shortest bookable session in minutes
+    BufferMinutes  int             `json:"buffer_minutes"`  // Free time kept before and after every booking
     BookedSlots    []BookedSlot    `json:"booked_slots"`
+    ClosedSlots    []ClosedSlot    `json:"closed_slots"`
 }
 
 // TimeSlot - Available time range
 type TimeSlot struct {
     StartTime string `json:"start_time"` // "09:00"
-    EndTime   string `json:"end_time"`   // "12:00"
+    EndTime   string `json:"end_time"`   // "12:00"; before start_time: ends the next day
+}
+
+// ClosedSlot - Closure of the studio during its opening hours on the date
+type ClosedSlot struct {
+    StartsAt string `json:"starts_at"` // "2025-12-25 00:00"
+    EndsAt   string `json:"ends_at"`   // "2025-12-26 00:00"
+    Reason   string `json:"reason"`
 }
 
 // BookedSlot - Booked time range
@@ -115,6 +162,35 @@ type BookedSlot struct {
     BookingID int    `json:"booking_id"`
 }
 
+// CalendarResponse - Availability of a studio day by day
+type CalendarResponse struct {
+    Success bool         `json:"success"`
+    Data    CalendarData `json:"data"`
+}
+
+// CalendarData - Days of the calendar with the overall occupancy
+type CalendarData struct {
+    StudioID      int           `json:"studio_id"`
+    Timezone      string        `json:"timezone"`
+    From          string        `json:"from"`
+    To            string        `json:"to"`
+    MinDuration   int           `json:"min_duration"`   // Shortest bookable session in minutes
+    BufferMinutes int           `json:"buffer_minutes"` // Free time kept before and after every booking
+    Occupancy     float64       `json:"occupancy"`      // Percentage of the open time that is booked
+    Days          []CalendarDay `json:"days"`
+}
+
+// CalendarDay - Open hours, booked and free ranges of one date
+type CalendarDay struct {
+    Date        string       `json:"date"`
+    Weekday     string       `json:"weekday"`      // "monday"
+    OpenHours   []TimeSlot   `json:"open_hours"`   // Empty when the studio is closed that day
+    BookedSlots []TimeSlot   `json:"booked_slots"`
+    FreeSlots   []TimeSlot   `json:"free_slots"`   // Free ranges at least min_duration long
+    ClosedSlots []ClosedSlot `json:"closed_slots"`
+    Occupancy   float64      `json:"occupancy"`    // Percentage of the open time that is booked
+}
+
 // Pagination - Pagination metadata
 type Pagination struct {
     CurrentPage  int   `json:"current_page"`
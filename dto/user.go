@@ -0,0 +1,97 @@
+package dto
+
+// ============= REQUEST DTOs =============
+
+// UserFilterRequest - Query params for admin user listing
+type UserFilterRequest struct {
+    Role        string `form:"role"`         // customer, staff, admin
+    Email       string `form:"email"`        // Partial match on email
+    Search      string `form:"search"`       // Search by name or email
+    IsActive    *bool  `form:"is_active"`    // false = suspended accounts only
+    CreatedFrom string `form:"created_from"` // Registered on/after date (YYYY-MM-DD)
+    CreatedTo   string `form:"created_to"`   // Registered on/before date (YYYY-MM-DD)
+    SortBy      string `form:"sort_by"`      // created_asc, created_desc, name_asc, name_desc
+    Page        int    `form:"page" binding:"omitempty,min=1"`
+    Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// UpdateUserRoleRequest - Admin promote/demote user
+type UpdateUserRoleRequest struct {
+    Role string `json:"role" binding:"required,oneof=customer staff admin"`
+}
+
+// SuspendUserRequest - Admin suspend user account
+type SuspendUserRequest struct {
+    Reason string `json:"reason" binding:"required,min=5"`
+}
+
+// ============= RESPONSE DTOs =============
+
+// UserListResponse - List of users with pagination
+type UserListResponse struct {
+    Success bool            `json:"success"`
+    Data    []AdminUserData `json:"data"`
+    Meta    PaginationMeta  `json:"meta"`
+}
+
+// UserDetailResponse - Single user with booking summary
+type UserDetailResponse struct {
+    Success bool           `json:"success"`
+    Data    UserDetailData `json:"data"`
+}
+
+// UpdateUserRoleResponse - Role change result
+type UpdateUserRoleResponse struct {
+    Success bool          `json:"success"`
+    Message string        `json:"message"`
+    Data    AdminUserData `json:"data"`
+}
+
+// UpdateUserStatusResponse - Suspend/unsuspend result
+type UpdateUserStatusResponse struct {
+    Success bool          `json:"success"`
+    Message string        `json:"message"`
+    Data    AdminUserData `json:"data"`
+}
+
+// ============= DATA DTOs =============
+
+// AdminUserData - User information visible to admins
+type AdminUserData struct {
+    ID               int    `json:"id"`
+    Name             string `json:"name"`
+    Email            string `json:"email"`
+    Role             string `json:"role"`
+    EmailVerified    bool   `json:"email_verified"`
+    TwoFactorEnabled bool   `json:"two_factor_enabled"`
+    IsActive         bool   `json:"is_active"`
+    SuspendedAt      string `json:"suspended_at,omitempty"`
+    SuspendReason    string `json:"suspend_reason,omitempty"`
+    CreatedAt        string `json:"created_at"`
+    UpdatedAt        string `json:"updated_at"`
+}
+
+// UserDetailData - User information plus booking summary
+type UserDetailData struct {
+    AdminUserData
+    LockedUntil    string             `json:"locked_until,omitempty"` // Set while login is locked after failed attempts
+    BookingSummary UserBookingSummary `json:"booking_summary"`
+}
+
+// UserBookingSummary - Booking counts per status for one user
+type UserBookingSummary struct {
+    TotalBookings int64 `json:"total_bookings"`
+    Pending       int64 `json:"pending"`
+    Confirmed     int64 `json:"confirmed"`
+    Completed     int64 `json:"completed"`
+    Cancelled     int64 `json:"cancelled"`
+    Expired       int64 `json:"expired"`
+    TotalSpent    int64 `json:"total_spent"` // Sum of confirmed and completed bookings
+}
+
+// BookingStatusCount - Aggregated bookings of one status
+type BookingStatusCount struct {
+    Status     string `json:"status"`
+    Count      int64  `json:"count"`
+    TotalPrice int64  `json:"total_price"`
+}
\ No newline at end of file
@@ -9,6 +9,7 @@ package main
 import (
 	"fmt"
 	"os"
+	_ "time/tzdata" // Studio timezones must load even on images without a zoneinfo database
 
 	"github.com/RaFYWStud/BackendBookingStudio/config"
 	dbConfig "github.com/RaFYWStud/BackendBookingStudio/config/database"
@@ -33,8 +34,11 @@ func main() {
         case "seed":
             runSeedOnly()
             return
+        case "job":
+            runJob()
+            return
         default:
-            fmt.Println("Unknown command. Use: migrate | reset | seed")
+            fmt.Println("Unknown command. Use: migrate | reset | seed | job <name>")
             return
         }
     }
@@ -77,7 +81,16 @@ func runReset() {
 
     fmt.Println("🗑️  Dropping all tables...")
     err = db.Migrator().DropTable(
+        &dbMigration.OutboxMessage{},
+        &dbMigration.Job{},
+        &dbMigration.BookingStatusLog{},
+        &dbMigration.RecoveryCode{},
+        &dbMigration.LoginThrottle{},
+        &dbMigration.PasswordResetToken{},
+        &dbMigration.RevokedToken{},
+        &dbMigration.RefreshToken{},
         &dbMigration.Booking{},
+        &dbMigration.StudioClosure{},
         &dbMigration.Studio{},
         &dbMigration.User{},
     )
@@ -104,4 +117,19 @@ func runSeedOnly() {
         panic(err)
     }
     fmt.Println("✅ Seeding completed")
+}
+
+func runJob() {
+    if len(os.Args) < 3 {
+        fmt.Println("Usage: job <name>")
+        os.Exit(1)
+    }
+
+    name := os.Args[2]
+    fmt.Printf("⚙️  Running job %s...\n", name)
+    if err := server.RunJob(name); err != nil {
+        fmt.Printf("❌ Job %s failed: %v\n", name, err)
+        os.Exit(1)
+    }
+    fmt.Printf("✅ Job %s completed\n", name)
 }
\ No newline at end of file
@@ -1,11 +1,19 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// pgUniqueViolation is the PostgreSQL SQLSTATE for a violated unique index.
+const pgUniqueViolation = "23505"
+
 type authRepository struct {
     db *gorm.DB
 }
@@ -14,24 +22,64 @@ func ImplAuthRepository(db *gorm.DB) contract.AuthRepository {
     return &authRepository{db: db}
 }
 
-func (r *authRepository) CreateUser(user *database.User) error {
-    return r.db.Create(user).Error
+func (r *authRepository) CreateUser(ctx context.Context, user *database.User) error {
+    return translateUserError(r.db.WithContext(ctx).Create(user).Error)
 }
 
-func (r *authRepository) FindByEmail(email string) (*database.User, error) {
+// FindByEmail looks up a user by a normalized (lowercase, trimmed) email. Accounts
+// registered before emails were normalized are matched case-insensitively.
+func (r *authRepository) FindByEmail(ctx context.Context, email string) (*database.User, error) {
     var user database.User
-    err := r.db.Where("email = ?", email).First(&user).Error
+    err := r.db.WithContext(ctx).Where("lower(email) = ?", email).First(&user).Error
     if err != nil {
         return nil, err
     }
     return &user, nil
 }
 
-func (r *authRepository) FindByID(id int) (*database.User, error) {
+func (r *authRepository) FindByID(ctx context.Context, id int) (*database.User, error) {
     var user database.User
-    err := r.db.First(&user, id).Error
+    err := r.db.WithContext(ctx).First(&user, id).Error
     if err != nil {
         return nil, err
     }
     return &user, nil
+}
+
+func (r *authRepository) IncrementTokenVersion(ctx context.Context, id int) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ?", id).
+        UpdateColumn("token_version", gorm.Expr("token_version + 1")).Error
+}
+
+func (r *authRepository) UpdatePassword(ctx context.Context, id int, hashedPassword string) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ?", id).
+        Update("password", hashedPassword).Error
+}
+
+func (r *authRepository) MarkEmailVerified(ctx context.Context, id int) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ? AND email_verified_at IS NULL", id).
+        Update("email_verified_at", time.Now()).Error
+}
+
+// UpdateProfile saves the self-editable profile columns. EmailVerifiedAt is included
+// so an email change can reset the verification state in the same update.
+func (r *authRepository) UpdateProfile(ctx context.Context, user *database.User) error {
+    err := r.db.WithContext(ctx).Model(user).
+        Select("name", "email", "email_verified_at", "phone", "notify_channel").
+        Updates(user).Error
+    return translateUserError(err)
+}
+
+// translateUserError maps a violation of a unique email index, the case-insensitive one or the
+// exact one gorm creates for User.Email, to database.ErrEmailTaken
+func translateUserError(err error) error {
+    var pgErr *pgconn.PgError
+    if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation &&
+        (pgErr.ConstraintName == database.UserEmailLowerIndex || pgErr.ConstraintName == "idx_users_email") {
+        return database.ErrEmailTaken
+    }
+    return err
 }
\ No newline at end of file
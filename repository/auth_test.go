@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"gorm.io/gorm"
+)
+
+func createTestUser(t *testing.T, db *gorm.DB) *database.User {
+	t.Helper()
+
+	user := &database.User{
+		Name:     "Test Customer",
+		Email:    fmt.Sprintf("customer-%d@example.com", time.Now().UnixNano()),
+		Password: "-",
+		Role:     "customer",
+		IsActive: true,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func TestUserEmailTaken(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplAuthRepository(db)
+	ctx := context.Background()
+
+	user := createTestUser(t, db)
+	other := createTestUser(t, db)
+
+	variant := &database.User{Name: "Variant", Email: strings.ToUpper(user.Email), Password: "-", Role: "customer", IsActive: true}
+	if err := repo.CreateUser(ctx, variant); !errors.Is(err, database.ErrEmailTaken) {
+		t.Errorf("CreateUser with a case variant = %v, want ErrEmailTaken", err)
+	}
+	if err := repo.CreateUser(ctx, &database.User{Name: "Same", Email: user.Email, Password: "-", Role: "customer", IsActive: true}); !errors.Is(err, database.ErrEmailTaken) {
+		t.Errorf("CreateUser with the same email = %v, want ErrEmailTaken", err)
+	}
+
+	other.Email = strings.ToUpper(user.Email)
+	if err := repo.UpdateProfile(ctx, other); !errors.Is(err, database.ErrEmailTaken) {
+		t.Errorf("UpdateProfile to a case variant = %v, want ErrEmailTaken", err)
+	}
+}
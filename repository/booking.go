@@ -1,14 +1,20 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/database"
 	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
+// pgExclusionViolation is the PostgreSQL SQLSTATE for a violated EXCLUDE constraint.
+const pgExclusionViolation = "23P01"
+
 type bookingRepository struct {
     db *gorm.DB
 }
@@ -17,22 +23,22 @@ func ImplBookingRepository(db *gorm.DB) contract.BookingRepository {
     return &bookingRepository{db: db}
 }
 
-func (r *bookingRepository) Create(booking *database.Booking) error {
-    return r.db.Create(booking).Error
+func (r *bookingRepository) Create(ctx context.Context, booking *database.Booking) error {
+    return translateBookingError(r.db.WithContext(ctx).Create(booking).Error)
 }
 
-func (r *bookingRepository) FindByID(id int) (*database.Booking, error) {
+func (r *bookingRepository) FindByID(ctx context.Context, id int) (*database.Booking, error) {
     var booking database.Booking
-    err := r.db.First(&booking, id).Error
+    err := r.db.WithContext(ctx).First(&booking, id).Error
     if err != nil {
         return nil, err
     }
     return &booking, nil
 }
 
-func (r *bookingRepository) FindByIDWithRelations(id int) (*database.Booking, error) {
+func (r *bookingRepository) FindByIDWithRelations(ctx context.Context, id int) (*database.Booking, error) {
     var booking database.Booking
-    err := r.db.Preload("User").
+    err := r.db.WithContext(ctx).Preload("User").
         Preload("Studio").
         First(&booking, id).Error
     if err != nil {
@@ -41,11 +47,11 @@ func (r *bookingRepository) FindByIDWithRelations(id int) (*database.Booking, er
     return &booking, nil
 }
 
-func (r *bookingRepository) FindAll(filter dto.BookingFilterRequest, userID *int) ([]database.Booking, int64, error) {
+func (r *bookingRepository) FindAll(ctx context.Context, filter dto.BookingFilterRequest, userID *int) ([]database.Booking, int64, error) {
     var bookings []database.Booking
     var total int64
 
-    query := r.db.Model(&database.Booking{}).
+    query := r.db.WithContext(ctx).Model(&database.Booking{}).
         Preload("User").
         Preload("Studio")
 
@@ -74,6 +80,10 @@ func (r *bookingRepository) FindAll(filter dto.BookingFilterRequest, userID *int
         query = query.Where("booking_date <= ?", filter.EndDate)
     }
 
+    if filter.Flagged {
+        query = query.Where("closure_id IS NOT NULL")
+    }
+
     // Count total
     if err := query.Count(&total).Error; err != nil {
         return nil, 0, err
@@ -103,28 +113,93 @@ func (r *bookingRepository) FindAll(filter dto.BookingFilterRequest, userID *int
     return bookings, total, err
 }
 
-func (r *bookingRepository) Update(booking *database.Booking) error {
-    return r.db.Save(booking).Error
+func (r *bookingRepository) Update(ctx context.Context, booking *database.Booking) error {
+    return translateBookingError(r.db.WithContext(ctx).Save(booking).Error)
 }
 
-func (r *bookingRepository) FindByUserID(userID int, filter dto.BookingFilterRequest) ([]database.Booking, int64, error) {
-    return r.FindAll(filter, &userID)
+func (r *bookingRepository) FindByUserID(ctx context.Context, userID int, filter dto.BookingFilterRequest) ([]database.Booking, int64, error) {
+    return r.FindAll(ctx, filter, &userID)
 }
 
-func (r *bookingRepository) CountPendingBookings(userID int) (int64, error) {
+func (r *bookingRepository) CountPendingBookings(ctx context.Context, userID int) (int64, error) {
     var count int64
-    err := r.db.Model(&database.Booking{}).
+    err := r.db.WithContext(ctx).Model(&database.Booking{}).
         Where("user_id = ? AND status = ?", userID, "pending").
         Count(&count).Error
     return count, err
 }
 
-func (r *bookingRepository) FindExpiredBookings() ([]database.Booking, error) {
+// FindExpiredBookings returns pending bookings whose payment deadline has passed
+func (r *bookingRepository) FindExpiredBookings(ctx context.Context, now time.Time) ([]database.Booking, error) {
     var bookings []database.Booking
-    now := time.Now()
 
-    err := r.db.Where("status = ? AND dp_deadline < ?", "pending", now).
+    err := r.db.WithContext(ctx).
+        Where("status = ? AND payment_deadline < ?", database.BookingStatusPending, now).
+        Order("payment_deadline ASC").
         Find(&bookings).Error
 
     return bookings, err
+}
+
+// Expire moves a booking to expired if it is still pending and overdue, and logs the change.
+// It reports false when the booking was paid or changed in the meantime.
+func (r *bookingRepository) Expire(ctx context.Context, id int, now time.Time) (bool, error) {
+    expired := false
+
+    err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        result := tx.Model(&database.Booking{}).
+            Where("id = ? AND status = ? AND payment_deadline < ?", id, database.BookingStatusPending, now).
+            Updates(map[string]interface{}{
+                "status":     database.BookingStatusExpired,
+                "updated_at": now,
+            })
+        if result.Error != nil || result.RowsAffected == 0 {
+            return result.Error
+        }
+        expired = true
+
+        return tx.Create(&database.BookingStatusLog{
+            BookingID:  id,
+            FromStatus: database.BookingStatusPending,
+            ToStatus:   database.BookingStatusExpired,
+            Reason:     "payment deadline passed",
+            CreatedAt:  now,
+        }).Error
+    })
+
+    return expired, err
+}
+
+// CompleteFinished moves every confirmed booking whose session ended at or before now, in its
+// studio's timezone, to completed and logs each change. A session ending at or before its start
+// time ends on the next day. Running it again, or concurrently, never completes a booking twice.
+// Returns how many bookings were completed.
+func (r *bookingRepository) CompleteFinished(ctx context.Context, now time.Time) (int64, error) {
+    result := r.db.WithContext(ctx).Exec(`
+        WITH completed AS (
+            UPDATE bookings b SET status = ?, updated_at = ?
+            FROM studios s
+            WHERE s.id = b.studio_id
+                AND b.status = ?
+                AND (CASE WHEN b.end_time <= b.start_time THEN b.booking_date + 1 ELSE b.booking_date END + b.end_time)
+                    AT TIME ZONE s.timezone <= ?
+            RETURNING b.id
+        )
+        INSERT INTO booking_status_logs (booking_id, from_status, to_status, reason, created_at)
+        SELECT id, ?, ?, ?, ? FROM completed`,
+        database.BookingStatusCompleted, now,
+        database.BookingStatusConfirmed,
+        now,
+        database.BookingStatusConfirmed, database.BookingStatusCompleted, "session ended", now,
+    )
+    return result.RowsAffected, result.Error
+}
+
+// translateBookingError maps a violation of the no-overlap constraint to database.ErrBookingOverlap
+func translateBookingError(err error) error {
+    var pgErr *pgconn.PgError
+    if errors.As(err, &pgErr) && pgErr.Code == pgExclusionViolation && pgErr.ConstraintName == database.BookingNoOverlapConstraint {
+        return database.ErrBookingOverlap
+    }
+    return err
 }
\ No newline at end of file
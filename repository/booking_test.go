@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func createTestStudio(t *testing.T, db *gorm.DB, location string, hours database.WeeklyHours, bufferMinutes int) *database.Studio {
+	t.Helper()
+
+	studio := &database.Studio{
+		Name:           "Studio " + location,
+		Location:       location,
+		PricePerHour:   100000,
+		OperatingHours: hours,
+		Timezone:       "Asia/Jakarta",
+		BufferMinutes:  bufferMinutes,
+		IsActive:       true,
+	}
+	if err := db.Create(studio).Error; err != nil {
+		t.Fatalf("create studio: %v", err)
+	}
+	return studio
+}
+
+// clock parses "HH:MM" the way the booking service does.
+func clock(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		t.Fatalf("parse %q: %v", value, err)
+	}
+	return parsed
+}
+
+func date(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatalf("parse %q: %v", value, err)
+	}
+	return parsed
+}
+
+func newTestBooking(t *testing.T, userID, studioID int, day, from, to string, status database.BookingStatus) *database.Booking {
+	return &database.Booking{
+		UserID:        userID,
+		StudioID:      studioID,
+		BookingDate:   date(t, day),
+		StartTime:     clock(t, from),
+		EndTime:       clock(t, to),
+		DurationHours: 1,
+		TotalPrice:    100000,
+		Status:        status,
+	}
+}
+
+func TestBookingNoOverlapConstraint(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplBookingRepository(db)
+	ctx := context.Background()
+
+	user := createTestUser(t, db)
+	studio := createTestStudio(t, db, "Overlap", database.DailyHours("00:00", "00:00"), 0)
+	other := createTestStudio(t, db, "Overlap Other", database.DailyHours("00:00", "00:00"), 0)
+
+	if err := repo.Create(ctx, newTestBooking(t, user.ID, studio.ID, "2030-01-10", "10:00", "12:00", database.BookingStatusPending)); err != nil {
+		t.Fatalf("first booking: %v", err)
+	}
+	if err := repo.Create(ctx, newTestBooking(t, user.ID, studio.ID, "2030-01-10", "23:00", "01:00", database.BookingStatusConfirmed)); err != nil {
+		t.Fatalf("overnight booking: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		booking *database.Booking
+		overlap bool
+	}{
+		{"overlapping", newTestBooking(t, user.ID, studio.ID, "2030-01-10", "11:00", "13:00", database.BookingStatusPending), true},
+		{"inside", newTestBooking(t, user.ID, studio.ID, "2030-01-10", "10:30", "11:30", database.BookingStatusPending), true},
+		{"overlapping an overnight session the next day", newTestBooking(t, user.ID, studio.ID, "2030-01-11", "00:30", "02:00", database.BookingStatusPending), true},
+		{"ending as the other starts", newTestBooking(t, user.ID, studio.ID, "2030-01-10", "08:00", "10:00", database.BookingStatusPending), false},
+		{"starting as the other ends", newTestBooking(t, user.ID, studio.ID, "2030-01-10", "12:00", "14:00", database.BookingStatusPending), false},
+		{"after an overnight session", newTestBooking(t, user.ID, studio.ID, "2030-01-11", "01:00", "03:00", database.BookingStatusPending), false},
+		{"other studio", newTestBooking(t, user.ID, other.ID, "2030-01-10", "10:00", "12:00", database.BookingStatusPending), false},
+		{"cancelled", newTestBooking(t, user.ID, studio.ID, "2030-01-10", "10:00", "12:00", database.BookingStatusCancelled), false},
+		{"expired", newTestBooking(t, user.ID, studio.ID, "2030-01-10", "10:00", "12:00", database.BookingStatusExpired), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.Create(ctx, tt.booking)
+			if tt.overlap && !errors.Is(err, database.ErrBookingOverlap) {
+				t.Fatalf("Create = %v, want ErrBookingOverlap", err)
+			}
+			if !tt.overlap && err != nil {
+				t.Fatalf("Create = %v, want no error", err)
+			}
+		})
+	}
+
+	// The error comes from the exclusion constraint, not from a check in Go
+	err := db.Create(newTestBooking(t, user.ID, studio.ID, "2030-01-10", "11:00", "11:30", database.BookingStatusPending)).Error
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != pgExclusionViolation || pgErr.ConstraintName != database.BookingNoOverlapConstraint {
+		t.Fatalf("raw insert error = %v, want %s on %s", err, pgExclusionViolation, database.BookingNoOverlapConstraint)
+	}
+
+	// Reviving a cancelled booking is checked as well
+	cancelled := tests[7].booking
+	cancelled.Status = database.BookingStatusPending
+	if err := repo.Update(ctx, cancelled); !errors.Is(err, database.ErrBookingOverlap) {
+		t.Fatalf("Update = %v, want ErrBookingOverlap", err)
+	}
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+// closureOccurrencesJoin expands every closure row "c" into its occurrences "o" within [from, to)
+const closureOccurrencesJoin = "CROSS JOIN LATERAL closure_occurrences(c.starts_at, c.ends_at, c.recurrence, c.repeat_until, ?, ?) o"
+
+// closureWallClock is the wall clock time at the instant @now where a studio_closures row applies: in its
+// studio's timezone, or for a closure of every studio, in the timezone furthest behind, where it ends last
+const closureWallClock = `COALESCE(
+    (SELECT timezone(s.timezone, CAST(@now AS timestamptz)) FROM studios s WHERE s.id = studio_closures.studio_id),
+    (SELECT min(timezone(s.timezone, CAST(@now AS timestamptz))) FROM studios s),
+    timezone('UTC', CAST(@now AS timestamptz)))`
+
+type closureRepository struct {
+    db *gorm.DB
+}
+
+func ImplClosureRepository(db *gorm.DB) contract.ClosureRepository {
+    return &closureRepository{db: db}
+}
+
+func (r *closureRepository) Create(ctx context.Context, closure *database.StudioClosure) error {
+    return r.db.WithContext(ctx).Create(closure).Error
+}
+
+func (r *closureRepository) FindByID(ctx context.Context, id int) (*database.StudioClosure, error) {
+    var closure database.StudioClosure
+    err := r.db.WithContext(ctx).Preload("Studio").First(&closure, id).Error
+    if err != nil {
+        return nil, err
+    }
+    return &closure, nil
+}
+
+func (r *closureRepository) FindAll(ctx context.Context, filter dto.ClosureFilterRequest, now time.Time) ([]database.StudioClosure, int64, error) {
+    var closures []database.StudioClosure
+    var total int64
+
+    query := r.db.WithContext(ctx).Model(&database.StudioClosure{}).Preload("Studio")
+
+    // Apply filters
+    if filter.StudioID > 0 {
+        // Closures of every studio apply too
+        query = query.Where("studio_id = ? OR studio_id IS NULL", filter.StudioID)
+    }
+    if filter.Upcoming {
+        // Closure times are wall clock times stored as UTC, so they compare with the wall clock of their studio
+        query = query.Where(
+            "timezone('UTC', ends_at) > "+closureWallClock+
+                " OR (recurrence <> @none AND (repeat_until IS NULL OR repeat_until >= ("+closureWallClock+")::date))",
+            sql.Named("now", now), sql.Named("none", database.RecurrenceNone),
+        )
+    }
+
+    // Count total before pagination
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    query = query.Order("starts_at ASC, id ASC")
+
+    // Apply pagination
+    if filter.Page > 0 && filter.Limit > 0 {
+        offset := (filter.Page - 1) * filter.Limit
+        query = query.Offset(offset).Limit(filter.Limit)
+    }
+
+    err := query.Find(&closures).Error
+    return closures, total, err
+}
+
+func (r *closureRepository) Update(ctx context.Context, closure *database.StudioClosure) error {
+    return r.db.WithContext(ctx).Omit("Studio").Save(closure).Error
+}
+
+// Delete removes the closure. The bookings it flagged are checked against the remaining closures, so
+// a booking inside another closure stays flagged.
+func (r *closureRepository) Delete(ctx context.Context, id int) error {
+    db := r.db.WithContext(ctx)
+
+    var flagged []int
+    if err := db.Model(&database.Booking{}).Where("closure_id = ?", id).Pluck("id", &flagged).Error; err != nil {
+        return err
+    }
+    if err := db.Delete(&database.StudioClosure{}, id).Error; err != nil {
+        return err
+    }
+    return reflagBookings(db, flagged)
+}
+
+// FlagBookings flags the pending and confirmed bookings inside any occurrence of the closure, and
+// checks the bookings it flagged before again. Returns the IDs of the bookings inside the closure.
+//
+// A booking holds one closure_id: the oldest closure covering it. A booking already flagged by an
+// older closure keeps that flag, and one the closure no longer covers goes back to the other closures.
+func (r *closureRepository) FlagBookings(ctx context.Context, closureID int) ([]int, error) {
+    db := r.db.WithContext(ctx)
+
+    var flagged []int
+    if err := db.Model(&database.Booking{}).Where("closure_id = ?", closureID).Pluck("id", &flagged).Error; err != nil {
+        return nil, err
+    }
+
+    // Occurrences are only needed up to the end of the last active booking
+    var ids []int
+    err := db.Raw(`
+        SELECT b.id FROM bookings b
+        JOIN studio_closures c ON c.id = ? AND (c.studio_id IS NULL OR b.studio_id = c.studio_id)
+        CROSS JOIN LATERAL closure_occurrences(
+            c.starts_at, c.ends_at, c.recurrence, c.repeat_until,
+            c.starts_at, (
+                SELECT COALESCE(max(upper(bk.period)), c.ends_at) FROM bookings bk
+                WHERE bk.status IN ? AND (c.studio_id IS NULL OR bk.studio_id = c.studio_id)
+            )
+        ) o
+        WHERE b.status IN ? AND b.period && o.occurrence
+        GROUP BY b.id
+        ORDER BY b.id`,
+        closureID, flaggableStatuses, flaggableStatuses,
+    ).Scan(&ids).Error
+    if err != nil {
+        return nil, err
+    }
+
+    return ids, reflagBookings(db, append(flagged, ids...))
+}
+
+// flaggableStatuses are the statuses of bookings a closure flags
+var flaggableStatuses = []database.BookingStatus{database.BookingStatusPending, database.BookingStatusConfirmed}
+
+// reflagBookings sets closure_id of the bookings to the oldest closure covering them, or NULL when none
+// does or the booking is no longer pending or confirmed
+func reflagBookings(db *gorm.DB, ids []int) error {
+    if len(ids) == 0 {
+        return nil
+    }
+
+    return db.Exec(`
+        UPDATE bookings b SET closure_id = CASE WHEN b.status IN ? THEN (
+            SELECT c.id FROM studio_closures c
+            CROSS JOIN LATERAL closure_occurrences(
+                c.starts_at, c.ends_at, c.recurrence, c.repeat_until, lower(b.period), upper(b.period)
+            ) o
+            WHERE c.studio_id IS NULL OR c.studio_id = b.studio_id
+            ORDER BY c.id
+            LIMIT 1
+        ) END
+        WHERE b.id IN ?`,
+        flaggableStatuses, ids,
+    ).Error
+}
+
+func (r *closureRepository) FindFlaggedBookings(ctx context.Context, closureID int) ([]database.Booking, error) {
+    var bookings []database.Booking
+    err := r.db.WithContext(ctx).
+        Preload("User").
+        Preload("Studio").
+        Where("closure_id = ?", closureID).
+        Order("booking_date ASC, start_time ASC").
+        Find(&bookings).Error
+
+    return bookings, err
+}
+
+// FindOccurrences returns the occurrences of the studio's closures and of global closures that overlap
+// [from, to), earliest first. Times are wall clock times of the studio in UTC, like the bounds.
+func (r *closureRepository) FindOccurrences(ctx context.Context, studioID int, from, to time.Time) ([]database.ClosureOccurrence, error) {
+    var occurrences []database.ClosureOccurrence
+    err := r.db.WithContext(ctx).Table("studio_closures c").
+        Select("c.id AS closure_id, c.studio_id, c.reason, lower(o.occurrence) AS starts_at, upper(o.occurrence) AS ends_at").
+        Joins(closureOccurrencesJoin, from, to).
+        Where("c.studio_id = ? OR c.studio_id IS NULL", studioID).
+        Order("starts_at ASC").
+        Scan(&occurrences).Error
+
+    for i := range occurrences {
+        occurrences[i].StartsAt = occurrences[i].StartsAt.UTC()
+        occurrences[i].EndsAt = occurrences[i].EndsAt.UTC()
+    }
+    return occurrences, err
+}
\ No newline at end of file
@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+// wallTime parses "YYYY-MM-DD HH:MM" as a wall clock time stored as UTC, like closure times.
+func wallTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		t.Fatalf("parse %q: %v", value, err)
+	}
+	return parsed
+}
+
+func createTestClosure(t *testing.T, db *gorm.DB, studioID *int, startsAt, endsAt, recurrence, repeatUntil string) *database.StudioClosure {
+	t.Helper()
+
+	closure := &database.StudioClosure{
+		StudioID:   studioID,
+		Reason:     "Maintenance",
+		StartsAt:   wallTime(t, startsAt),
+		EndsAt:     wallTime(t, endsAt),
+		Recurrence: recurrence,
+	}
+	if repeatUntil != "" {
+		until := date(t, repeatUntil)
+		closure.RepeatUntil = &until
+	}
+	if err := db.Create(closure).Error; err != nil {
+		t.Fatalf("create closure: %v", err)
+	}
+	return closure
+}
+
+func TestClosureOccurrences(t *testing.T) {
+	db := dbtest.Open(t)
+
+	tests := []struct {
+		name             string
+		startsAt, endsAt string
+		recurrence       string
+		repeatUntil      string
+		from, to         string
+		want             []string
+	}{
+		{
+			name:     "once, inside the range",
+			startsAt: "2026-01-05 10:00", endsAt: "2026-01-05 12:00", recurrence: "none",
+			from: "2026-01-01 00:00", to: "2026-02-01 00:00",
+			want: []string{"2026-01-05 10:00-2026-01-05 12:00"},
+		},
+		{
+			name:     "once, before the range",
+			startsAt: "2026-01-05 10:00", endsAt: "2026-01-05 12:00", recurrence: "none",
+			from: "2026-01-05 12:00", to: "2026-02-01 00:00",
+			want: nil,
+		},
+		{
+			name:     "once, overlapping the start of the range",
+			startsAt: "2026-01-05 10:00", endsAt: "2026-01-05 12:00", recurrence: "none",
+			from: "2026-01-05 11:00", to: "2026-01-05 11:30",
+			want: []string{"2026-01-05 10:00-2026-01-05 12:00"},
+		},
+		{
+			name:     "weekly until a date, inclusive",
+			startsAt: "2026-01-05 10:00", endsAt: "2026-01-05 12:00", recurrence: "weekly", repeatUntil: "2026-01-19",
+			from: "2026-01-01 00:00", to: "2026-03-01 00:00",
+			want: []string{
+				"2026-01-05 10:00-2026-01-05 12:00",
+				"2026-01-12 10:00-2026-01-12 12:00",
+				"2026-01-19 10:00-2026-01-19 12:00",
+			},
+		},
+		{
+			name:     "weekly forever, cut to the range",
+			startsAt: "2026-01-05 10:00", endsAt: "2026-01-05 12:00", recurrence: "weekly",
+			from: "2026-01-10 00:00", to: "2026-01-27 00:00",
+			want: []string{
+				"2026-01-12 10:00-2026-01-12 12:00",
+				"2026-01-19 10:00-2026-01-19 12:00",
+				"2026-01-26 10:00-2026-01-26 12:00",
+			},
+		},
+		{
+			name:     "weekly overnight",
+			startsAt: "2026-01-04 22:00", endsAt: "2026-01-05 02:00", recurrence: "weekly",
+			from: "2026-01-05 00:00", to: "2026-01-12 00:00",
+			want: []string{"2026-01-04 22:00-2026-01-05 02:00", "2026-01-11 22:00-2026-01-12 02:00"},
+		},
+		{
+			// The session runs in America/New_York, which moves to summer time on 8 March 2026
+			name:     "weekly across a DST change keeps the wall clock",
+			startsAt: "2026-03-02 10:00", endsAt: "2026-03-02 12:00", recurrence: "weekly",
+			from: "2026-03-01 00:00", to: "2026-03-17 00:00",
+			want: []string{
+				"2026-03-02 10:00-2026-03-02 12:00",
+				"2026-03-09 10:00-2026-03-09 12:00",
+				"2026-03-16 10:00-2026-03-16 12:00",
+			},
+		},
+		{
+			name:     "yearly",
+			startsAt: "2025-12-24 18:00", endsAt: "2025-12-26 00:00", recurrence: "yearly",
+			from: "2026-01-01 00:00", to: "2029-01-01 00:00",
+			want: []string{
+				"2026-12-24 18:00-2026-12-26 00:00",
+				"2027-12-24 18:00-2027-12-26 00:00",
+				"2028-12-24 18:00-2028-12-26 00:00",
+			},
+		},
+		{
+			name:     "yearly, started before the range",
+			startsAt: "2025-12-24 18:00", endsAt: "2025-12-26 00:00", recurrence: "yearly",
+			from: "2026-12-25 12:00", to: "2026-12-25 13:00",
+			want: []string{"2026-12-24 18:00-2026-12-26 00:00"},
+		},
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL TimeZone = 'America/New_York'").Error; err != nil {
+			return err
+		}
+
+		for _, tt := range tests {
+			var repeatUntil *time.Time
+			if tt.repeatUntil != "" {
+				until := date(t, tt.repeatUntil)
+				repeatUntil = &until
+			}
+
+			var rows []struct {
+				Lower time.Time
+				Upper time.Time
+			}
+			err := tx.Raw(
+				"SELECT lower(occurrence) AS lower, upper(occurrence) AS upper FROM closure_occurrences(?, ?, ?, ?, ?, ?) ORDER BY 1",
+				wallTime(t, tt.startsAt), wallTime(t, tt.endsAt), tt.recurrence, repeatUntil,
+				wallTime(t, tt.from), wallTime(t, tt.to),
+			).Scan(&rows).Error
+			if err != nil {
+				return err
+			}
+
+			var got []string
+			for _, row := range rows {
+				got = append(got, row.Lower.UTC().Format("2006-01-02 15:04")+"-"+row.Upper.UTC().Format("2006-01-02 15:04"))
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("%s: occurrences = %v, want %v", tt.name, got, tt.want)
+				continue
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("%s: occurrences = %v, want %v", tt.name, got, tt.want)
+					break
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("closure_occurrences: %v", err)
+	}
+}
+
+func TestClosureFlagBookings(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplClosureRepository(db)
+	ctx := context.Background()
+
+	user := createTestUser(t, db)
+	studio := createTestStudio(t, db, "Flagged", database.DailyHours("00:00", "00:00"), 0)
+	other := createTestStudio(t, db, "Flagged Other", database.DailyHours("00:00", "00:00"), 0)
+
+	bookings := map[string]*database.Booking{
+		"first monday":     newTestBooking(t, user.ID, studio.ID, "2030-01-07", "10:00", "12:00", database.BookingStatusPending),
+		"second monday":    newTestBooking(t, user.ID, studio.ID, "2030-01-14", "10:00", "12:00", database.BookingStatusConfirmed),
+		"after closing":    newTestBooking(t, user.ID, studio.ID, "2030-01-14", "13:00", "14:00", database.BookingStatusConfirmed),
+		"cancelled":        newTestBooking(t, user.ID, studio.ID, "2030-01-21", "10:00", "12:00", database.BookingStatusCancelled),
+		"other studio":     newTestBooking(t, user.ID, other.ID, "2030-01-07", "10:00", "12:00", database.BookingStatusPending),
+		"other studio far": newTestBooking(t, user.ID, other.ID, "2035-01-01", "10:00", "12:00", database.BookingStatusPending),
+	}
+	for name, booking := range bookings {
+		if err := db.Create(booking).Error; err != nil {
+			t.Fatalf("create %s booking: %v", name, err)
+		}
+	}
+	idsOf := func(names ...string) []int {
+		var ids []int
+		for _, name := range names {
+			ids = append(ids, bookings[name].ID)
+		}
+		sort.Ints(ids)
+		return ids
+	}
+
+	tests := []struct {
+		name    string
+		closure *database.StudioClosure
+		want    []int
+	}{
+		{
+			// Repeats forever; only the studio's own bookings bound the occurrences
+			name:    "weekly closure of one studio",
+			closure: createTestClosure(t, db, &studio.ID, "2030-01-07 09:00", "2030-01-07 12:30", database.RecurrenceWeekly, ""),
+			want:    idsOf("first monday", "second monday"),
+		},
+		{
+			name:    "closure of every studio",
+			closure: createTestClosure(t, db, nil, "2030-01-07 11:00", "2030-01-07 11:30", database.RecurrenceNone, ""),
+			want:    idsOf("first monday", "other studio"),
+		},
+		{
+			name:    "weekly closure of every studio",
+			closure: createTestClosure(t, db, nil, "2030-01-07 10:30", "2030-01-07 11:00", database.RecurrenceWeekly, ""),
+			want:    idsOf("first monday", "second monday", "other studio"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := repo.FlagBookings(ctx, tt.closure.ID)
+			if err != nil {
+				t.Fatalf("FlagBookings: %v", err)
+			}
+			sort.Ints(ids)
+			if len(ids) != len(tt.want) {
+				t.Fatalf("flagged %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("flagged %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClosureFlagWithSeveralClosures(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplClosureRepository(db)
+	ctx := context.Background()
+
+	user := createTestUser(t, db)
+	studio := createTestStudio(t, db, "Twice Closed", database.DailyHours("00:00", "00:00"), 0)
+	booking := newTestBooking(t, user.ID, studio.ID, "2030-01-07", "10:00", "12:00", database.BookingStatusConfirmed)
+	if err := db.Create(booking).Error; err != nil {
+		t.Fatalf("create booking: %v", err)
+	}
+
+	wantFlag := func(t *testing.T, closure *database.StudioClosure) {
+		t.Helper()
+
+		var got database.Booking
+		if err := db.First(&got, booking.ID).Error; err != nil {
+			t.Fatalf("load booking: %v", err)
+		}
+		switch {
+		case closure == nil && got.ClosureID != nil:
+			t.Fatalf("booking flagged by closure %d, want no flag", *got.ClosureID)
+		case closure != nil && (got.ClosureID == nil || *got.ClosureID != closure.ID):
+			t.Fatalf("booking flagged by %v, want closure %d", got.ClosureID, closure.ID)
+		}
+	}
+	// flag runs FlagBookings, which reports the booking whenever it is inside the closure
+	flag := func(t *testing.T, closure *database.StudioClosure, inside bool) {
+		t.Helper()
+
+		ids, err := repo.FlagBookings(ctx, closure.ID)
+		if err != nil {
+			t.Fatalf("FlagBookings: %v", err)
+		}
+		if got := len(ids) == 1 && ids[0] == booking.ID; got != inside || len(ids) > 1 {
+			t.Fatalf("FlagBookings = %v, want the booking reported: %v", ids, inside)
+		}
+	}
+
+	studioClosure := createTestClosure(t, db, &studio.ID, "2030-01-07 10:00", "2030-01-07 11:00", database.RecurrenceNone, "")
+	flag(t, studioClosure, true)
+	wantFlag(t, studioClosure)
+
+	// A second closure covering the booking doesn't take over the flag
+	globalClosure := createTestClosure(t, db, nil, "2030-01-07 11:00", "2030-01-07 12:00", database.RecurrenceNone, "")
+	flag(t, globalClosure, true)
+	wantFlag(t, studioClosure)
+
+	// Moved away from the booking, the other closure flags it
+	studioClosure.StartsAt, studioClosure.EndsAt = wallTime(t, "2030-01-08 10:00"), wallTime(t, "2030-01-08 11:00")
+	if err := repo.Update(ctx, studioClosure); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	flag(t, studioClosure, false)
+	wantFlag(t, globalClosure)
+
+	// Moved back, the older closure flags it again
+	studioClosure.StartsAt, studioClosure.EndsAt = wallTime(t, "2030-01-07 10:00"), wallTime(t, "2030-01-07 11:00")
+	if err := repo.Update(ctx, studioClosure); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	flag(t, studioClosure, true)
+	wantFlag(t, studioClosure)
+
+	// Deleting one closure leaves the booking flagged by the other
+	if err := repo.Delete(ctx, studioClosure.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	wantFlag(t, globalClosure)
+
+	if err := repo.Delete(ctx, globalClosure.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	wantFlag(t, nil)
+}
+
+func TestClosureFindAllUpcomingUsesStudioTimezone(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplClosureRepository(db)
+
+	// At noon UTC it is already 02:00 the next day in Kiritimati (UTC+14), but only 01:00 in Pago Pago (UTC-11)
+	now := time.Date(2030, 1, 10, 12, 0, 0, 0, time.UTC)
+	east := createTestStudio(t, db, "East", database.DailyHours("00:00", "00:00"), 0)
+	west := createTestStudio(t, db, "West", database.DailyHours("00:00", "00:00"), 0)
+	if err := db.Model(east).Update("timezone", "Pacific/Kiritimati").Error; err != nil {
+		t.Fatalf("update timezone: %v", err)
+	}
+	if err := db.Model(west).Update("timezone", "Pacific/Pago_Pago").Error; err != nil {
+		t.Fatalf("update timezone: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		closure  *database.StudioClosure
+		upcoming bool
+	}{
+		{"ended in the east", createTestClosure(t, db, &east.ID, "2030-01-10 18:00", "2030-01-10 20:00", database.RecurrenceNone, ""), false},
+		{"still ahead in the west", createTestClosure(t, db, &west.ID, "2030-01-10 18:00", "2030-01-10 20:00", database.RecurrenceNone, ""), true},
+		{"ended in the west", createTestClosure(t, db, &west.ID, "2030-01-09 22:00", "2030-01-10 00:30", database.RecurrenceNone, ""), false},
+		{"every studio, still ahead in the west", createTestClosure(t, db, nil, "2030-01-10 18:00", "2030-01-10 20:00", database.RecurrenceNone, ""), true},
+		{"repeats until yesterday in the east", createTestClosure(t, db, &east.ID, "2030-01-03 08:00", "2030-01-03 09:00", database.RecurrenceWeekly, "2030-01-10"), false},
+		{"repeats until today in the west", createTestClosure(t, db, &west.ID, "2030-01-03 08:00", "2030-01-03 09:00", database.RecurrenceWeekly, "2030-01-10"), true},
+		{"repeats forever", createTestClosure(t, db, &east.ID, "2029-01-03 08:00", "2029-01-03 09:00", database.RecurrenceYearly, ""), true},
+	}
+
+	closures, _, err := repo.FindAll(context.Background(), dto.ClosureFilterRequest{Upcoming: true}, now)
+	if err != nil {
+		t.Fatalf("FindAll: %v", err)
+	}
+	listed := map[int]bool{}
+	for _, closure := range closures {
+		listed[closure.ID] = true
+	}
+
+	for _, tt := range tests {
+		if listed[tt.closure.ID] != tt.upcoming {
+			t.Errorf("%s: listed = %v, want %v", tt.name, listed[tt.closure.ID], tt.upcoming)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRepository struct {
+    db *gorm.DB
+}
+
+func ImplJobRepository(db *gorm.DB) contract.JobRepository {
+    return &jobRepository{db: db}
+}
+
+// Register inserts the job unless it already exists; existing state is kept.
+func (r *jobRepository) Register(ctx context.Context, name string, firstRun time.Time) error {
+    return r.db.WithContext(ctx).
+        Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+        Create(&database.Job{Name: name, NextRunAt: firstRun}).Error
+}
+
+// ClaimDue takes the lease if the job is due and nobody holds an unexpired lease.
+// Returns the number of consecutive failures and whether the lease was taken.
+func (r *jobRepository) ClaimDue(ctx context.Context, name, owner string, now, leaseUntil time.Time) (int, bool, error) {
+    return r.claim(r.db.WithContext(ctx).Where("next_run_at <= ?", now), name, owner, now, leaseUntil)
+}
+
+// Claim takes the lease whether or not the job is due, unless somebody holds an unexpired lease.
+func (r *jobRepository) Claim(ctx context.Context, name, owner string, now, leaseUntil time.Time) (int, bool, error) {
+    return r.claim(r.db.WithContext(ctx), name, owner, now, leaseUntil)
+}
+
+func (r *jobRepository) claim(query *gorm.DB, name, owner string, now, leaseUntil time.Time) (int, bool, error) {
+    var job database.Job
+
+    // A single conditional UPDATE, so two instances can never both get the lease
+    result := query.Model(&job).
+        Clauses(clause.Returning{Columns: []clause.Column{{Name: "failures"}}}).
+        Where("name = ? AND (locked_until IS NULL OR locked_until < ?)", name, now).
+        Updates(map[string]interface{}{
+            "locked_by":       owner,
+            "locked_until":    leaseUntil,
+            "last_started_at": now,
+        })
+    if result.Error != nil {
+        return 0, false, result.Error
+    }
+
+    return job.Failures, result.RowsAffected > 0, nil
+}
+
+// Succeed releases the lease held by owner and schedules the next run
+func (r *jobRepository) Succeed(ctx context.Context, name, owner string, finishedAt, nextRun time.Time) error {
+    return r.db.WithContext(ctx).Model(&database.Job{}).
+        Where("name = ? AND locked_by = ?", name, owner).
+        Updates(map[string]interface{}{
+            "locked_by":        nil,
+            "locked_until":     nil,
+            "failures":         0,
+            "last_error":       "",
+            "last_finished_at": finishedAt,
+            "next_run_at":      nextRun,
+        }).Error
+}
+
+// Fail releases the lease held by owner, counts the failure and schedules the retry
+func (r *jobRepository) Fail(ctx context.Context, name, owner string, finishedAt, retryAt time.Time, errMsg string) error {
+    return r.db.WithContext(ctx).Model(&database.Job{}).
+        Where("name = ? AND locked_by = ?", name, owner).
+        Updates(map[string]interface{}{
+            "locked_by":        nil,
+            "locked_until":     nil,
+            "failures":         gorm.Expr("failures + 1"),
+            "last_error":       errMsg,
+            "last_finished_at": finishedAt,
+            "next_run_at":      retryAt,
+        }).Error
+}
\ No newline at end of file
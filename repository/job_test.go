@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+)
+
+func TestJobLease(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Date(2030, 1, 7, 10, 0, 0, 0, time.UTC)
+	lease := now.Add(time.Minute)
+
+	claimDue := func(owner string, at time.Time) (int, bool) {
+		t.Helper()
+
+		failures, ok, err := repo.ClaimDue(ctx, "report", owner, at, at.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("ClaimDue: %v", err)
+		}
+		return failures, ok
+	}
+
+	if err := repo.Register(ctx, "report", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, ok := claimDue("a", now); ok {
+		t.Fatal("claimed a job that is not due")
+	}
+
+	// Registering again keeps the state
+	if err := repo.Register(ctx, "report", now); err != nil {
+		t.Fatalf("Register again: %v", err)
+	}
+	if _, ok := claimDue("a", now); ok {
+		t.Fatal("registering again moved the next run")
+	}
+
+	if _, ok := claimDue("a", now.Add(time.Hour)); !ok {
+		t.Fatal("did not claim a due job")
+	}
+	if _, ok := claimDue("b", now.Add(time.Hour)); ok {
+		t.Fatal("claimed a job leased by another instance")
+	}
+	if _, ok, err := repo.Claim(ctx, "report", "b", now.Add(time.Hour), lease); err != nil || ok {
+		t.Fatalf("Claim of a leased job = %v, %v", ok, err)
+	}
+
+	// a crashed; once its lease runs out b takes over and records a failure
+	later := now.Add(time.Hour + 2*time.Minute)
+	if _, ok := claimDue("b", later); !ok {
+		t.Fatal("did not claim a job whose lease expired")
+	}
+	if err := repo.Succeed(ctx, "report", "a", later, later.Add(time.Hour)); err != nil {
+		t.Fatalf("Succeed: %v", err)
+	}
+	if err := repo.Fail(ctx, "report", "b", later, later.Add(10*time.Second), "boom"); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+
+	var job database.Job
+	if err := db.First(&job, "name = ?", "report").Error; err != nil {
+		t.Fatalf("load job: %v", err)
+	}
+	// a's late success did not touch b's lease
+	if job.Failures != 1 || job.LastError != "boom" || job.LockedBy != nil || !job.NextRunAt.Equal(later.Add(10*time.Second)) {
+		t.Fatalf("job %+v, want one failure, no lease and a retry in 10s", job)
+	}
+
+	failures, ok := claimDue("a", later.Add(10*time.Second))
+	if !ok || failures != 1 {
+		t.Fatalf("ClaimDue = %d failures, %v, want the retry with 1 failure", failures, ok)
+	}
+}
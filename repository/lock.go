@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"gorm.io/gorm"
+)
+
+// scheduleLockClass is the class of the two-key advisory locks on studio schedules; the second key is
+// the studio ID, and 0 stands for every studio.
+const scheduleLockClass = 7301
+
+type lockRepository struct {
+    db *gorm.DB
+}
+
+func ImplLockRepository(db *gorm.DB) contract.LockRepository {
+    return &lockRepository{db: db}
+}
+
+// TryAdvisoryLock takes a transaction-level PostgreSQL advisory lock keyed by the hash of name.
+// It returns false straight away when another session holds the lock. The lock is released when
+// the transaction ends, so it only means something inside UnitOfWork.WithTx.
+func (r *lockRepository) TryAdvisoryLock(ctx context.Context, name string) (bool, error) {
+    var locked bool
+    err := r.db.WithContext(ctx).Raw("SELECT pg_try_advisory_xact_lock(hashtext(?))", name).Scan(&locked).Error
+    return locked, err
+}
+
+// LockStudioSchedule serializes changes to a studio's schedule: bookings and closures of a studio take it
+// before checking each other, so neither can miss the other committing in between. The lock of one studio
+// is held together with a shared lock of every studio, which a closure of every studio takes exclusively.
+// Like TryAdvisoryLock it only means something inside UnitOfWork.WithTx.
+func (r *lockRepository) LockStudioSchedule(ctx context.Context, studioID *int) error {
+    db := r.db.WithContext(ctx)
+    if studioID == nil {
+        return db.Exec("SELECT pg_advisory_xact_lock(CAST(? AS integer), 0)", scheduleLockClass).Error
+    }
+
+    if err := db.Exec("SELECT pg_advisory_xact_lock_shared(CAST(? AS integer), 0)", scheduleLockClass).Error; err != nil {
+        return err
+    }
+    return db.Exec("SELECT pg_advisory_xact_lock(CAST(? AS integer), CAST(? AS integer))", scheduleLockClass, *studioID).Error
+}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"gorm.io/gorm"
+)
+
+type loginThrottleRepository struct {
+    db *gorm.DB
+}
+
+func ImplLoginThrottleRepository(db *gorm.DB) contract.LoginThrottleRepository {
+    return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) Find(ctx context.Context, scope, subject string) (*database.LoginThrottle, error) {
+    var throttle database.LoginThrottle
+    err := r.db.WithContext(ctx).Where("scope = ? AND subject = ?", scope, subject).
+        First(&throttle).Error
+    if err != nil {
+        return nil, err
+    }
+    return &throttle, nil
+}
+
+// RecordFailure atomically bumps the failure counter, starting over when the
+// previous failure happened before windowStart
+func (r *loginThrottleRepository) RecordFailure(ctx context.Context, scope, subject string, windowStart time.Time) (*database.LoginThrottle, error) {
+    var throttle database.LoginThrottle
+    now := time.Now()
+    err := r.db.WithContext(ctx).Raw(`
+        INSERT INTO login_throttles (scope, subject, failed_count, last_failed_at, created_at, updated_at)
+        VALUES (?, ?, 1, ?, ?, ?)
+        ON CONFLICT (scope, subject) DO UPDATE SET
+            failed_count = CASE
+                WHEN login_throttles.last_failed_at < ? THEN 1
+                ELSE login_throttles.failed_count + 1
+            END,
+            last_failed_at = EXCLUDED.last_failed_at,
+            updated_at = EXCLUDED.updated_at
+        RETURNING *
+    `, scope, subject, now, now, now, windowStart).Scan(&throttle).Error
+    if err != nil {
+        return nil, err
+    }
+    return &throttle, nil
+}
+
+// Lock sets the lockout and resets the counter. It returns false when the
+// subject was already locked, so only one caller sends the notification.
+func (r *loginThrottleRepository) Lock(ctx context.Context, id int, until time.Time) (bool, error) {
+    result := r.db.WithContext(ctx).Model(&database.LoginThrottle{}).
+        Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, time.Now()).
+        Updates(map[string]interface{}{
+            "failed_count": 0,
+            "locked_until": until,
+        })
+    return result.RowsAffected > 0, result.Error
+}
+
+func (r *loginThrottleRepository) Clear(ctx context.Context, scope, subject string) error {
+    return r.db.WithContext(ctx).Where("scope = ? AND subject = ?", scope, subject).
+        Delete(&database.LoginThrottle{}).Error
+}
+
+func (r *loginThrottleRepository) DeleteStale(ctx context.Context, before time.Time) error {
+    return r.db.WithContext(ctx).Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, time.Now()).
+        Delete(&database.LoginThrottle{}).Error
+}
\ No newline at end of file
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+    db *gorm.DB
+}
+
+func ImplOutboxRepository(db *gorm.DB) contract.OutboxRepository {
+    return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, msg *database.OutboxMessage) error {
+    return r.db.WithContext(ctx).Create(msg).Error
+}
+
+func (r *outboxRepository) FindByID(ctx context.Context, id int) (*database.OutboxMessage, error) {
+    var msg database.OutboxMessage
+    err := r.db.WithContext(ctx).First(&msg, id).Error
+    if err != nil {
+        return nil, err
+    }
+    return &msg, nil
+}
+
+func (r *outboxRepository) FindAll(ctx context.Context, filter dto.OutboxFilterRequest) ([]database.OutboxMessage, int64, error) {
+    var messages []database.OutboxMessage
+    var total int64
+
+    query := r.db.WithContext(ctx).Model(&database.OutboxMessage{})
+
+    // Apply filters
+    if filter.Status != "" {
+        query = query.Where("status = ?", filter.Status)
+    }
+    if filter.Kind != "" {
+        query = query.Where("kind = ?", filter.Kind)
+    }
+    if filter.Channel != "" {
+        query = query.Where("channel = ?", filter.Channel)
+    }
+    if filter.Recipient != "" {
+        query = query.Where("recipient ILIKE ?", "%"+filter.Recipient+"%")
+    }
+    if filter.BookingID > 0 {
+        query = query.Where("booking_id = ?", filter.BookingID)
+    }
+
+    // Count total before pagination
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    query = query.Order("created_at DESC")
+
+    // Apply pagination
+    if filter.Page > 0 && filter.Limit > 0 {
+        offset := (filter.Page - 1) * filter.Limit
+        query = query.Offset(offset).Limit(filter.Limit)
+    }
+
+    err := query.Find(&messages).Error
+    return messages, total, err
+}
+
+// FindDue returns up to limit pending messages whose next attempt is due, oldest first
+func (r *outboxRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]database.OutboxMessage, error) {
+    var messages []database.OutboxMessage
+
+    err := r.db.WithContext(ctx).
+        Where("status = ? AND next_attempt_at <= ?", database.OutboxStatusPending, now).
+        Order("next_attempt_at ASC, id ASC").
+        Limit(limit).
+        Find(&messages).Error
+
+    return messages, err
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id int, sentAt time.Time) error {
+    return r.db.WithContext(ctx).Model(&database.OutboxMessage{}).
+        Where("id = ? AND status = ?", id, database.OutboxStatusPending).
+        Updates(map[string]interface{}{
+            "status":     database.OutboxStatusSent,
+            "attempts":   gorm.Expr("attempts + 1"),
+            "last_error": "",
+            "sent_at":    sentAt,
+        }).Error
+}
+
+// MarkFailed counts a failed attempt. The message is retried at nextAttemptAt, or marked dead when it is nil.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int, errMsg string, nextAttemptAt *time.Time) error {
+    updates := map[string]interface{}{
+        "attempts":   gorm.Expr("attempts + 1"),
+        "last_error": errMsg,
+    }
+    if nextAttemptAt != nil {
+        updates["next_attempt_at"] = *nextAttemptAt
+    } else {
+        updates["status"] = database.OutboxStatusDead
+    }
+
+    return r.db.WithContext(ctx).Model(&database.OutboxMessage{}).
+        Where("id = ? AND status = ?", id, database.OutboxStatusPending).
+        Updates(updates).Error
+}
+
+// Requeue gives a dead message a fresh set of attempts, starting now.
+// It reports false if the message is not dead.
+func (r *outboxRepository) Requeue(ctx context.Context, id int, now time.Time) (bool, error) {
+    result := r.db.WithContext(ctx).Model(&database.OutboxMessage{}).
+        Where("id = ? AND status = ?", id, database.OutboxStatusDead).
+        Updates(map[string]interface{}{
+            "status":          database.OutboxStatusPending,
+            "attempts":        0,
+            "next_attempt_at": now,
+        })
+    return result.RowsAffected > 0, result.Error
+}
\ No newline at end of file
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+)
+
+func TestOutboxDeliveryStates(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplOutboxRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	queue := func(to string, nextAttemptAt time.Time) *database.OutboxMessage {
+		t.Helper()
+		msg := &database.OutboxMessage{
+			Kind:          "booking_created",
+			Channel:       "email",
+			Recipient:     to,
+			Subject:       "Subject",
+			Body:          "Body",
+			Status:        database.OutboxStatusPending,
+			NextAttemptAt: nextAttemptAt,
+		}
+		if err := repo.Create(ctx, msg); err != nil {
+			t.Fatalf("create message: %v", err)
+		}
+		return msg
+	}
+	reload := func(id int) *database.OutboxMessage {
+		t.Helper()
+		msg, err := repo.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("find message %d: %v", id, err)
+		}
+		return msg
+	}
+
+	later := queue("later@example.com", now.Add(time.Minute))
+	second := queue("second@example.com", now.Add(-time.Minute))
+	first := queue("first@example.com", now.Add(-time.Hour))
+
+	// Only due messages, the longest waiting first
+	due, err := repo.FindDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("FindDue: %v", err)
+	}
+	if len(due) != 2 || due[0].ID != first.ID || due[1].ID != second.ID {
+		t.Fatalf("FindDue returned %d messages, want first and second", len(due))
+	}
+
+	// A failure with a next attempt keeps the message pending
+	retryAt := now.Add(30 * time.Second)
+	if err := repo.MarkFailed(ctx, first.ID, "connection refused", &retryAt); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+	msg := reload(first.ID)
+	if msg.Status != database.OutboxStatusPending || msg.Attempts != 1 || msg.LastError != "connection refused" || !msg.NextAttemptAt.Equal(retryAt) {
+		t.Fatalf("after retryable failure: status = %s, attempts = %d, error = %q, next = %v", msg.Status, msg.Attempts, msg.LastError, msg.NextAttemptAt)
+	}
+
+	// Sending after a failure counts the attempt and clears the error
+	if err := repo.MarkSent(ctx, first.ID, now); err != nil {
+		t.Fatalf("MarkSent: %v", err)
+	}
+	msg = reload(first.ID)
+	if msg.Status != database.OutboxStatusSent || msg.Attempts != 2 || msg.LastError != "" || msg.SentAt == nil {
+		t.Fatalf("after send: status = %s, attempts = %d, error = %q, sent at = %v", msg.Status, msg.Attempts, msg.LastError, msg.SentAt)
+	}
+
+	// A sent message is final
+	if err := repo.MarkFailed(ctx, first.ID, "late failure", nil); err != nil {
+		t.Fatalf("MarkFailed on sent: %v", err)
+	}
+	if msg := reload(first.ID); msg.Status != database.OutboxStatusSent || msg.Attempts != 2 {
+		t.Errorf("MarkFailed changed a sent message: status = %s, attempts = %d", msg.Status, msg.Attempts)
+	}
+	if ok, err := repo.Requeue(ctx, first.ID, now); err != nil || ok {
+		t.Errorf("Requeue of a sent message = %v, %v, want false", ok, err)
+	}
+
+	// Without a next attempt the message is dead and no longer due
+	if err := repo.MarkFailed(ctx, second.ID, "mailbox full", nil); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+	if msg := reload(second.ID); msg.Status != database.OutboxStatusDead || msg.Attempts != 1 {
+		t.Fatalf("after final failure: status = %s, attempts = %d, want dead after 1", msg.Status, msg.Attempts)
+	}
+	if err := repo.MarkSent(ctx, second.ID, now); err != nil {
+		t.Fatalf("MarkSent on dead: %v", err)
+	}
+	if msg := reload(second.ID); msg.Status != database.OutboxStatusDead {
+		t.Errorf("MarkSent changed a dead message to %s", msg.Status)
+	}
+	if due, _ := repo.FindDue(ctx, now.Add(time.Hour), 10); len(due) != 1 || due[0].ID != later.ID {
+		t.Errorf("FindDue returned %d messages, want only the pending one", len(due))
+	}
+
+	// Requeue gives a dead message a fresh set of attempts, once
+	requeueAt := now.Add(time.Hour)
+	if ok, err := repo.Requeue(ctx, second.ID, requeueAt); err != nil || !ok {
+		t.Fatalf("Requeue = %v, %v, want true", ok, err)
+	}
+	msg = reload(second.ID)
+	if msg.Status != database.OutboxStatusPending || msg.Attempts != 0 || !msg.NextAttemptAt.Equal(requeueAt) {
+		t.Fatalf("after requeue: status = %s, attempts = %d, next = %v", msg.Status, msg.Attempts, msg.NextAttemptAt)
+	}
+	if ok, err := repo.Requeue(ctx, second.ID, requeueAt); err != nil || ok {
+		t.Errorf("second Requeue = %v, %v, want false", ok, err)
+	}
+	if ok, err := repo.Requeue(ctx, later.ID, now); err != nil || ok {
+		t.Errorf("Requeue of a pending message = %v, %v, want false", ok, err)
+	}
+}
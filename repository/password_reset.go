@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"gorm.io/gorm"
+)
+
+type passwordResetRepository struct {
+    db *gorm.DB
+}
+
+func ImplPasswordResetRepository(db *gorm.DB) contract.PasswordResetRepository {
+    return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, token *database.PasswordResetToken) error {
+    return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *passwordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*database.PasswordResetToken, error) {
+    var token database.PasswordResetToken
+    err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+    if err != nil {
+        return nil, err
+    }
+    return &token, nil
+}
+
+// MarkUsed consumes the token. It reports false when the token was already used,
+// so the same link cannot reset the password twice even under concurrent requests.
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id int) (bool, error) {
+    result := r.db.WithContext(ctx).Model(&database.PasswordResetToken{}).
+        Where("id = ? AND used_at IS NULL", id).
+        Update("used_at", time.Now())
+    if result.Error != nil {
+        return false, result.Error
+    }
+    return result.RowsAffected == 1, nil
+}
+
+// InvalidateByUserID consumes every outstanding token of the user, so only the latest link works.
+func (r *passwordResetRepository) InvalidateByUserID(ctx context.Context, userID int) error {
+    return r.db.WithContext(ctx).Model(&database.PasswordResetToken{}).
+        Where("user_id = ? AND used_at IS NULL", userID).
+        Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"gorm.io/gorm"
+)
+
+type refreshTokenRepository struct {
+    db *gorm.DB
+}
+
+func ImplRefreshTokenRepository(db *gorm.DB) contract.RefreshTokenRepository {
+    return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *database.RefreshToken) error {
+    return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*database.RefreshToken, error) {
+    var token database.RefreshToken
+    err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+    if err != nil {
+        return nil, err
+    }
+    return &token, nil
+}
+
+// Revoke marks a single token as used. It reports false when the token was
+// already revoked, so two concurrent refreshes with the same token cannot both win.
+func (r *refreshTokenRepository) Revoke(ctx context.Context, id int) (bool, error) {
+    result := r.db.WithContext(ctx).Model(&database.RefreshToken{}).
+        Where("id = ? AND revoked_at IS NULL", id).
+        Update("revoked_at", time.Now())
+    if result.Error != nil {
+        return false, result.Error
+    }
+    return result.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+    return r.db.WithContext(ctx).Model(&database.RefreshToken{}).
+        Where("family_id = ? AND revoked_at IS NULL", familyID).
+        Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) RevokeAllByUserID(ctx context.Context, userID int) error {
+    return r.db.WithContext(ctx).Model(&database.RefreshToken{}).
+        Where("user_id = ? AND revoked_at IS NULL", userID).
+        Update("revoked_at", time.Now()).Error
+}
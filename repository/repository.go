@@ -10,5 +10,16 @@ func New(db *gorm.DB) *contract.Repository {
 		Auth: ImplAuthRepository(db),
 		Studio: ImplStudioRepository(db),
 		Booking: ImplBookingRepository(db), 
+		RefreshToken: ImplRefreshTokenRepository(db),
+		RevokedToken: ImplRevokedTokenRepository(db),
+		PasswordReset: ImplPasswordResetRepository(db),
+		User: ImplUserRepository(db),
+		LoginThrottle: ImplLoginThrottleRepository(db),
+		TwoFactor: ImplTwoFactorRepository(db),
+		Lock: ImplLockRepository(db),
+		Job: ImplJobRepository(db),
+		Outbox: ImplOutboxRepository(db),
+		Closure: ImplClosureRepository(db),
+		UnitOfWork: ImplUnitOfWork(db),
 	}
 }
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type revokedTokenRepository struct {
+    db *gorm.DB
+}
+
+func ImplRevokedTokenRepository(db *gorm.DB) contract.RevokedTokenRepository {
+    return &revokedTokenRepository{db: db}
+}
+
+func (r *revokedTokenRepository) Create(ctx context.Context, token *database.RevokedToken) error {
+    // Revoking the same token twice is not an error
+    return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r *revokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+    var count int64
+    err := r.db.WithContext(ctx).Model(&database.RevokedToken{}).
+        Where("jti = ?", jti).
+        Count(&count).Error
+    return count > 0, err
+}
+
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context) error {
+    return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).
+        Delete(&database.RevokedToken{}).Error
+}
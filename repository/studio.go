@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
@@ -17,24 +18,27 @@ func ImplStudioRepository(db *gorm.DB) contract.StudioRepository {
     return &studioRepository{db: db}
 }
 
-func (r *studioRepository) Create(studio *database.Studio) error {
-    return r.db.Create(studio).Error
+func (r *studioRepository) Create(ctx context.Context, studio *database.Studio) error {
+    return r.db.WithContext(ctx).Create(studio).Error
 }
 
-func (r *studioRepository) FindByID(id int) (*database.Studio, error) {
+func (r *studioRepository) FindByID(ctx context.Context, id int) (*database.Studio, error) {
     var studio database.Studio
-    err := r.db.First(&studio, id).Error
+    err := r.db.WithContext(ctx).First(&studio, id).Error
     if err != nil {
         return nil, err
     }
     return &studio, nil
 }
 
-func (r *studioRepository) FindAll(filter dto.StudioFilterRequest) ([]database.Studio, int64, error) {
+// FindAll lists studios matching filter. With freeDuring, only active studios that are open for the whole
+// session, where it hasn't started by now, and have no booking (kept buffer time apart) or closure overlapping
+// it, all in one query.
+func (r *studioRepository) FindAll(ctx context.Context, filter dto.StudioFilterRequest, freeDuring *database.SessionRange, now time.Time) ([]database.Studio, int64, error) {
     var studios []database.Studio
     var total int64
 
-    query := r.db.Model(&database.Studio{})
+    query := r.db.WithContext(ctx).Model(&database.Studio{})
 
     // Apply filters
     if filter.Location != "" {
@@ -52,6 +56,21 @@ func (r *studioRepository) FindAll(filter dto.StudioFilterRequest) ([]database.S
     if filter.Search != "" {
         query = query.Where("name ILIKE ?", "%"+filter.Search+"%")
     }
+    if freeDuring != nil {
+        from, to := freeDuring.From, freeDuring.To
+        // Sessions are wall clock times, so whether one has started depends on the studio's timezone
+        query = query.
+            Where("studios.is_active AND studio_is_open(studios.weekly_hours, ?, ?)", from, to).
+            Where("timezone(studios.timezone, CAST(? AS timestamptz)) < timezone('UTC', CAST(? AS timestamptz))", now, from).
+            Where(`NOT EXISTS (
+                SELECT 1 FROM bookings b
+                WHERE b.studio_id = studios.id AND b.status NOT IN (?) AND b.period && tstzrange(
+                    ?::timestamptz - studios.buffer_minutes * INTERVAL '1 minute',
+                    ?::timestamptz + studios.buffer_minutes * INTERVAL '1 minute', '[)')
+            )`, []string{"cancelled", "expired"}, from, to).
+            Where("NOT EXISTS (SELECT 1 FROM studio_closures c "+closureOccurrencesJoin+
+                " WHERE c.studio_id = studios.id OR c.studio_id IS NULL)", from, to)
+    }
 
     // Count total before pagination
     if err := query.Count(&total).Error; err != nil {
@@ -82,17 +101,17 @@ func (r *studioRepository) FindAll(filter dto.StudioFilterRequest) ([]database.S
     return studios, total, err
 }
 
-func (r *studioRepository) Update(studio *database.Studio) error {
-    return r.db.Save(studio).Error
+func (r *studioRepository) Update(ctx context.Context, studio *database.Studio) error {
+    return r.db.WithContext(ctx).Save(studio).Error
 }
 
-func (r *studioRepository) Delete(id int) error {
-    return r.db.Delete(&database.Studio{}, id).Error
+func (r *studioRepository) Delete(ctx context.Context, id int) error {
+    return r.db.WithContext(ctx).Delete(&database.Studio{}, id).Error
 }
 
-func (r *studioRepository) FindBookingsByDateRange(studioID int, date time.Time) ([]database.Booking, error) {
+func (r *studioRepository) FindBookingsByDateRange(ctx context.Context, studioID int, date time.Time) ([]database.Booking, error) {
     var bookings []database.Booking
-    err := r.db.Where("studio_id = ? AND booking_date = ? AND status NOT IN (?)",
+    err := r.db.WithContext(ctx).Where("studio_id = ? AND booking_date = ? AND status NOT IN (?)",
         studioID,
         date.Format("2006-01-02"),
         []string{"cancelled", "expired"},
@@ -101,17 +120,38 @@ func (r *studioRepository) FindBookingsByDateRange(studioID int, date time.Time)
     return bookings, err
 }
 
-func (r *studioRepository) IsStudioAvailable(studioID int, date time.Time, startTime, endTime time.Time) (bool, error) {
-    var count int64
-    err := r.db.Model(&database.Booking{}).Where(
-        "studio_id = ? AND booking_date = ? AND status NOT IN (?) AND ((start_time < ? AND end_time > ?) OR (start_time < ? AND end_time > ?) OR (start_time >= ? AND end_time <= ?))",
+// FindBookingsBetweenDates returns the non-cancelled bookings of a studio from one booking date to another,
+// inclusive, in a single query
+func (r *studioRepository) FindBookingsBetweenDates(ctx context.Context, studioID int, from, to time.Time) ([]database.Booking, error) {
+    var bookings []database.Booking
+    err := r.db.WithContext(ctx).Where("studio_id = ? AND booking_date BETWEEN ? AND ? AND status NOT IN (?)",
         studioID,
-        date.Format("2006-01-02"),
+        from.Format("2006-01-02"),
+        to.Format("2006-01-02"),
         []string{"cancelled", "expired"},
-        endTime, startTime,
-        endTime, endTime,
-        startTime, endTime,
-    ).Count(&count).Error
+    ).Order("booking_date ASC, start_time ASC").Find(&bookings).Error
+
+    return bookings, err
+}
+
+// IsStudioAvailable compares against the generated period column, so sessions that run past midnight
+// also clash with bookings of the next or previous day. Bookings must be the studio's buffer time apart;
+// bookings_no_overlap doesn't know the buffer, so this check only holds against concurrent bookings when
+// made under LockRepository.LockStudioSchedule. Closures are not checked here, see ClosureRepository.FindOccurrences.
+func (r *studioRepository) IsStudioAvailable(ctx context.Context, studioID int, date time.Time, startTime, endTime time.Time) (bool, error) {
+    from, to := database.SessionBounds(date, startTime, endTime)
+
+    var count int64
+    err := r.db.WithContext(ctx).Model(&database.Booking{}).
+        Joins("JOIN studios ON studios.id = bookings.studio_id").
+        Where(
+            `bookings.studio_id = ? AND bookings.status NOT IN (?) AND bookings.period && tstzrange(
+                ?::timestamptz - studios.buffer_minutes * INTERVAL '1 minute',
+                ?::timestamptz + studios.buffer_minutes * INTERVAL '1 minute', '[)')`,
+            studioID,
+            []string{"cancelled", "expired"},
+            from, to,
+        ).Count(&count).Error
 
     return count == 0, err
 }
\ No newline at end of file
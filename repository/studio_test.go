@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+)
+
+func TestStudioIsOpenMatchesCovers(t *testing.T) {
+	db := dbtest.Open(t)
+
+	touching := database.WeeklyHours{
+		"monday":  {Open: "18:00", Close: "00:00"},
+		"tuesday": {Open: "00:00", Close: "06:00"},
+	}
+
+	// 2030-01-07 is a Monday
+	tests := []struct {
+		name       string
+		hours      database.WeeklyHours
+		day        string
+		start, end string
+		want       bool
+	}{
+		{"inside", database.DailyHours("09:00", "22:00"), "2030-01-07", "10:00", "12:00", true},
+		{"whole window", database.DailyHours("09:00", "22:00"), "2030-01-07", "09:00", "22:00", true},
+		{"starts before opening", database.DailyHours("09:00", "22:00"), "2030-01-07", "08:00", "10:00", false},
+		{"ends after closing", database.DailyHours("09:00", "22:00"), "2030-01-07", "21:00", "23:00", false},
+		{"overnight session in overnight window", database.DailyHours("20:00", "02:00"), "2030-01-07", "23:00", "01:00", true},
+		{"after midnight in the window of the day before", database.DailyHours("20:00", "02:00"), "2030-01-07", "01:00", "02:00", true},
+		{"past the overnight close", database.DailyHours("20:00", "02:00"), "2030-01-07", "01:00", "03:00", false},
+		{"between overnight windows", database.DailyHours("20:00", "02:00"), "2030-01-07", "02:00", "03:00", false},
+		{"24 hour session when always open", database.DailyHours("00:00", "00:00"), "2030-01-07", "22:00", "22:00", true},
+		{"24 hours from a time of day", database.WeeklyHours{"monday": {Open: "10:00", Close: "10:00"}}, "2030-01-07", "10:00", "10:00", true},
+		{"across touching periods", touching, "2030-01-07", "22:00", "02:00", true},
+		{"past the joined period", touching, "2030-01-07", "22:00", "07:00", false},
+		{"in the joined period of the day before", touching, "2030-01-08", "01:00", "05:00", true},
+		{"closed day", database.WeeklyHours{"tuesday": {Open: "09:00", Close: "22:00"}}, "2030-01-07", "10:00", "12:00", false},
+		{"spanning a gap between days", database.WeeklyHours{"monday": {Open: "18:00", Close: "23:00"}, "tuesday": {Open: "00:00", Close: "06:00"}}, "2030-01-07", "22:00", "01:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := database.SessionBounds(date(t, tt.day), clock(t, tt.start), clock(t, tt.end))
+
+			var open bool
+			if err := db.Raw("SELECT studio_is_open(CAST(? AS jsonb), ?, ?)", tt.hours, from, to).Scan(&open).Error; err != nil {
+				t.Fatalf("studio_is_open: %v", err)
+			}
+			covers := tt.hours.Covers(date(t, tt.day), clock(t, tt.start), clock(t, tt.end))
+
+			if open != tt.want || covers != tt.want {
+				t.Errorf("studio_is_open = %v, Covers = %v, want %v", open, covers, tt.want)
+			}
+		})
+	}
+}
+
+func TestStudioFindAllFreeDuring(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := ImplStudioRepository(db)
+	ctx := context.Background()
+
+	user := createTestUser(t, db)
+	daytime := database.DailyHours("09:00", "22:00")
+
+	free := createTestStudio(t, db, "Free", daytime, 0)
+	overlapping := createTestStudio(t, db, "Overlapping", daytime, 0)
+	withinBuffer := createTestStudio(t, db, "Within Buffer", daytime, 30)
+	pastBuffer := createTestStudio(t, db, "Past Buffer", daytime, 30)
+	cancelled := createTestStudio(t, db, "Cancelled", daytime, 0)
+	closed := createTestStudio(t, db, "Closure", daytime, 0)
+	closedDay := createTestStudio(t, db, "Closed Day", database.WeeklyHours{"tuesday": {Open: "09:00", Close: "22:00"}}, 0)
+	inactive := createTestStudio(t, db, "Inactive", daytime, 0)
+	kiritimati := createTestStudio(t, db, "Kiritimati", daytime, 0)
+	if err := db.Model(inactive).Update("is_active", false).Error; err != nil {
+		t.Fatalf("deactivate studio: %v", err)
+	}
+	if err := db.Model(kiritimati).Update("timezone", "Pacific/Kiritimati").Error; err != nil {
+		t.Fatalf("update timezone: %v", err)
+	}
+
+	for _, booking := range []*database.Booking{
+		newTestBooking(t, user.ID, overlapping.ID, "2030-01-07", "11:00", "13:00", database.BookingStatusConfirmed),
+		newTestBooking(t, user.ID, withinBuffer.ID, "2030-01-07", "12:15", "13:00", database.BookingStatusPending),
+		newTestBooking(t, user.ID, pastBuffer.ID, "2030-01-07", "12:30", "13:00", database.BookingStatusPending),
+		newTestBooking(t, user.ID, cancelled.ID, "2030-01-07", "10:00", "12:00", database.BookingStatusCancelled),
+	} {
+		if err := db.Create(booking).Error; err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+	}
+	createTestClosure(t, db, &closed.ID, "2030-01-07 11:30", "2030-01-07 12:30", database.RecurrenceNone, "")
+	createTestClosure(t, db, nil, "2030-01-08 00:00", "2030-01-09 00:00", database.RecurrenceNone, "")
+
+	before := wallTime(t, "2030-01-01 00:00")
+
+	// freeStudios runs the search and keeps the studios of this test, seed data may exist too
+	freeStudios := func(t *testing.T, day string, now time.Time) map[int]bool {
+		t.Helper()
+
+		from, to := database.SessionBounds(date(t, day), clock(t, "10:00"), clock(t, "12:00"))
+		studios, _, err := repo.FindAll(ctx, dto.StudioFilterRequest{}, &database.SessionRange{From: from, To: to}, now)
+		if err != nil {
+			t.Fatalf("FindAll: %v", err)
+		}
+		found := map[int]bool{}
+		for _, studio := range studios {
+			found[studio.ID] = true
+		}
+		return found
+	}
+
+	t.Run("bookings, buffers and closures of the studio", func(t *testing.T) {
+		found := freeStudios(t, "2030-01-07", before)
+
+		tests := []struct {
+			studio *database.Studio
+			want   bool
+		}{
+			{free, true},
+			{overlapping, false},
+			{withinBuffer, false},
+			{pastBuffer, true}, // The buffer ends as the booking starts
+			{cancelled, true},
+			{closed, false},
+			{closedDay, false},
+			{inactive, false},
+			{kiritimati, true},
+		}
+		for _, tt := range tests {
+			if found[tt.studio.ID] != tt.want {
+				t.Errorf("%s free = %v, want %v", tt.studio.Name, found[tt.studio.ID], tt.want)
+			}
+		}
+	})
+
+	t.Run("closure of every studio", func(t *testing.T) {
+		found := freeStudios(t, "2030-01-08", before)
+
+		for _, studio := range []*database.Studio{free, pastBuffer, closedDay} {
+			if found[studio.ID] {
+				t.Errorf("%s is free during a closure of every studio", studio.Name)
+			}
+		}
+	})
+
+	t.Run("session started in the studio's timezone", func(t *testing.T) {
+		// Midnight UTC is 07:00 in Jakarta but already 14:00 in Kiritimati (UTC+14)
+		found := freeStudios(t, "2030-01-07", wallTime(t, "2030-01-07 00:00"))
+
+		if !found[free.ID] {
+			t.Errorf("%s is not free before the session starts", free.Name)
+		}
+		if found[kiritimati.ID] {
+			t.Errorf("%s is free for a session that has started there", kiritimati.Name)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"gorm.io/gorm"
+)
+
+type twoFactorRepository struct {
+    db *gorm.DB
+}
+
+func ImplTwoFactorRepository(db *gorm.DB) contract.TwoFactorRepository {
+    return &twoFactorRepository{db: db}
+}
+
+// SetPendingSecret stores a new secret for a user who has not confirmed 2FA yet
+func (r *twoFactorRepository) SetPendingSecret(ctx context.Context, userID int, secret string) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ? AND totp_enabled_at IS NULL", userID).
+        Update("totp_secret", secret).Error
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID int, counter int64) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ?", userID).
+        Updates(map[string]interface{}{
+            "totp_enabled_at":   time.Now(),
+            "totp_last_counter": counter,
+        }).Error
+}
+
+// Disable turns 2FA off and drops the secret together with every recovery code
+func (r *twoFactorRepository) Disable(ctx context.Context, userID int) error {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Model(&database.User{}).
+            Where("id = ?", userID).
+            Updates(map[string]interface{}{
+                "totp_secret":       "",
+                "totp_enabled_at":   nil,
+                "totp_last_counter": 0,
+            }).Error; err != nil {
+            return err
+        }
+        return tx.Where("user_id = ?", userID).Delete(&database.RecoveryCode{}).Error
+    })
+}
+
+// AcceptCounter records a used time step. It returns false when a step at or
+// after it was already accepted, i.e. the code is being replayed.
+func (r *twoFactorRepository) AcceptCounter(ctx context.Context, userID int, counter int64) (bool, error) {
+    result := r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ? AND totp_last_counter < ?", userID, counter).
+        Update("totp_last_counter", counter)
+    return result.RowsAffected > 0, result.Error
+}
+
+// ReplaceRecoveryCodes invalidates all existing recovery codes and stores the new set
+func (r *twoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+    return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        if err := tx.Where("user_id = ?", userID).Delete(&database.RecoveryCode{}).Error; err != nil {
+            return err
+        }
+
+        codes := make([]database.RecoveryCode, len(codeHashes))
+        for i, hash := range codeHashes {
+            codes[i] = database.RecoveryCode{UserID: userID, CodeHash: hash}
+        }
+        return tx.Create(&codes).Error
+    })
+}
+
+// UseRecoveryCode marks a matching unused code as used, false if there was none
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+    result := r.db.WithContext(ctx).Model(&database.RecoveryCode{}).
+        Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+        Update("used_at", time.Now())
+    return result.RowsAffected > 0, result.Error
+}
+
+func (r *twoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID int) (int64, error) {
+    var count int64
+    err := r.db.WithContext(ctx).Model(&database.RecoveryCode{}).
+        Where("user_id = ? AND used_at IS NULL", userID).
+        Count(&count).Error
+    return count, err
+}
\ No newline at end of file
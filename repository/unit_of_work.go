@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"gorm.io/gorm"
+)
+
+type unitOfWork struct {
+    db *gorm.DB
+}
+
+func ImplUnitOfWork(db *gorm.DB) contract.UnitOfWork {
+    return &unitOfWork{db: db}
+}
+
+// WithTx runs fn with repositories bound to one transaction.
+// Calling WithTx again on those repositories opens a savepoint instead of a new transaction.
+func (u *unitOfWork) WithTx(ctx context.Context, fn func(repos *contract.Repository) error) error {
+    return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+        return fn(New(tx))
+    })
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+    db *gorm.DB
+}
+
+func ImplUserRepository(db *gorm.DB) contract.UserRepository {
+    return &userRepository{db: db}
+}
+
+func (r *userRepository) FindAll(ctx context.Context, filter dto.UserFilterRequest, createdFrom, createdTo *time.Time) ([]database.User, int64, error) {
+    var users []database.User
+    var total int64
+
+    query := r.db.WithContext(ctx).Model(&database.User{})
+
+    // Apply filters
+    if filter.Role != "" {
+        query = query.Where("role = ?", filter.Role)
+    }
+    if filter.Email != "" {
+        query = query.Where("email ILIKE ?", "%"+filter.Email+"%")
+    }
+    if filter.Search != "" {
+        query = query.Where("(name ILIKE ? OR email ILIKE ?)", "%"+filter.Search+"%", "%"+filter.Search+"%")
+    }
+    if filter.IsActive != nil {
+        query = query.Where("is_active = ?", *filter.IsActive)
+    }
+    if createdFrom != nil {
+        query = query.Where("created_at >= ?", *createdFrom)
+    }
+    if createdTo != nil {
+        query = query.Where("created_at < ?", *createdTo)
+    }
+
+    // Count total before pagination
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    // Apply sorting
+    switch filter.SortBy {
+    case "created_asc":
+        query = query.Order("created_at ASC")
+    case "name_asc":
+        query = query.Order("name ASC")
+    case "name_desc":
+        query = query.Order("name DESC")
+    default:
+        query = query.Order("created_at DESC")
+    }
+
+    // Apply pagination
+    if filter.Page > 0 && filter.Limit > 0 {
+        offset := (filter.Page - 1) * filter.Limit
+        query = query.Offset(offset).Limit(filter.Limit)
+    }
+
+    err := query.Find(&users).Error
+    return users, total, err
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id int) (*database.User, error) {
+    var user database.User
+    err := r.db.WithContext(ctx).First(&user, id).Error
+    if err != nil {
+        return nil, err
+    }
+    return &user, nil
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, id int, role string) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ?", id).
+        Update("role", role).Error
+}
+
+func (r *userRepository) Suspend(ctx context.Context, id int, reason string) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ?", id).
+        Updates(map[string]interface{}{
+            "is_active":      false,
+            "suspended_at":   time.Now(),
+            "suspend_reason": reason,
+        }).Error
+}
+
+func (r *userRepository) Unsuspend(ctx context.Context, id int) error {
+    return r.db.WithContext(ctx).Model(&database.User{}).
+        Where("id = ?", id).
+        Updates(map[string]interface{}{
+            "is_active":      true,
+            "suspended_at":   nil,
+            "suspend_reason": "",
+        }).Error
+}
+
+func (r *userRepository) CountBookingsByStatus(ctx context.Context, userID int) ([]dto.BookingStatusCount, error) {
+    var counts []dto.BookingStatusCount
+    err := r.db.WithContext(ctx).Model(&database.Booking{}).
+        Select("status, COUNT(*) AS count, COALESCE(SUM(total_price), 0) AS total_price").
+        Where("user_id = ?", userID).
+        Group("status").
+        Scan(&counts).Error
+    return counts, err
+}
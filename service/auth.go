@@ -1,29 +1,64 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
 	"time"
 
 	"github.com/RaFYWStud/BackendBookingStudio/config"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/notify"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/token"
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/database"
 	"github.com/RaFYWStud/BackendBookingStudio/dto"
-	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type authService struct {
-    authRepo contract.AuthRepository
+    authRepo          contract.AuthRepository
+    refreshTokenRepo  contract.RefreshTokenRepository
+    revokedTokenRepo  contract.RevokedTokenRepository
+    passwordResetRepo contract.PasswordResetRepository
+    loginThrottleRepo contract.LoginThrottleRepository
+    twoFactorRepo     contract.TwoFactorRepository
+    unitOfWork        contract.UnitOfWork
+    emailService      contract.EmailService
 }
 
-func ImplAuthService(authRepo contract.AuthRepository) contract.AuthService {
-    return &authService{authRepo: authRepo}
+func ImplAuthService(
+    authRepo contract.AuthRepository,
+    refreshTokenRepo contract.RefreshTokenRepository,
+    revokedTokenRepo contract.RevokedTokenRepository,
+    passwordResetRepo contract.PasswordResetRepository,
+    loginThrottleRepo contract.LoginThrottleRepository,
+    twoFactorRepo contract.TwoFactorRepository,
+    unitOfWork contract.UnitOfWork,
+    emailService contract.EmailService,
+) contract.AuthService {
+    return &authService{
+        authRepo:          authRepo,
+        refreshTokenRepo:  refreshTokenRepo,
+        revokedTokenRepo:  revokedTokenRepo,
+        passwordResetRepo: passwordResetRepo,
+        loginThrottleRepo: loginThrottleRepo,
+        twoFactorRepo:     twoFactorRepo,
+        unitOfWork:        unitOfWork,
+        emailService:      emailService,
+    }
 }
 
-func (s *authService) Register(req dto.RegisterRequest) (*dto.RegisterResponse, error) {
+func (s *authService) Register(ctx context.Context, req dto.RegisterRequest) (*dto.RegisterResponse, error) {
+    email := normalizeEmail(req.Email)
+
     // Check if email already exists
-    _, err := s.authRepo.FindByEmail(req.Email)
+    _, err := s.authRepo.FindByEmail(ctx, email)
     if err == nil {
         // Email found, user already exists
         return nil, errs.BadRequest("email already registered")
@@ -42,31 +77,41 @@ func (s *authService) Register(req dto.RegisterRequest) (*dto.RegisterResponse,
 
     user := &database.User{
         Name:     req.Name,
-        Email:    req.Email,
+        Email:    email,
         Password: string(hashedPassword),
-        Role:     "customer",
+        Role:     string(permission.RoleCustomer),
+        IsActive: true,
     }
 
-    if err := s.authRepo.CreateUser(user); err != nil {
+    if err := s.authRepo.CreateUser(ctx, user); err != nil {
+        // Registered by a concurrent request since the check above
+        if errors.Is(err, database.ErrEmailTaken) {
+            return nil, errs.BadRequest("email already registered")
+        }
         return nil, errs.InternalServerError("failed to create user account")
     }
 
+    s.sendVerificationEmail(ctx, user)
+
     return &dto.RegisterResponse{
         Success: true,
-        Message: "Registration successful",
-        Data: dto.UserData{
-            ID:    user.ID,
-            Name:  user.Name,
-            Email: user.Email,
-            Role:  user.Role,
-        },
+        Message: "Registration successful. Please check your email to verify your account.",
+        Data:    mapUserToDTO(user),
     }, nil
 }
 
-func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
-    user, err := s.authRepo.FindByEmail(req.Email)
+func (s *authService) Login(ctx context.Context, req dto.LoginRequest, clientIP string) (*dto.LoginResponse, error) {
+    email := normalizeEmail(req.Email)
+    if err := s.checkLoginAllowed(ctx, email, clientIP); err != nil {
+        return nil, err
+    }
+
+    user, err := s.authRepo.FindByEmail(ctx, email)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
+            if lockErr := s.recordLoginFailure(ctx, email, clientIP, nil); lockErr != nil {
+                return nil, lockErr
+            }
             return nil, errs.Unauthorized("invalid email or password")
         }
         return nil, errs.InternalServerError("failed to authenticate user")
@@ -74,11 +119,43 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
 
     // Verify password
     if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+        if lockErr := s.recordLoginFailure(ctx, email, clientIP, user); lockErr != nil {
+            return nil, lockErr
+        }
         return nil, errs.Unauthorized("invalid email or password")
     }
 
-    // Generate JWT token
-    token, err := s.generateToken(user)
+    if !user.IsActive {
+        return nil, errs.Forbidden("your account has been suspended")
+    }
+
+    // Password is correct but a second factor is needed; failures are only
+    // cleared once the code is verified, so the lockout also covers the code step
+    if user.TOTPEnabledAt != nil {
+        challengeToken, err := token.GenerateTwoFactorChallengeToken(user.ID)
+        if err != nil {
+            return nil, errs.InternalServerError("failed to generate authentication token")
+        }
+
+        return &dto.LoginResponse{
+            Success:           true,
+            Message:           "Two-factor authentication required",
+            TwoFactorRequired: true,
+            Challenge: &dto.TwoFactorChallenge{
+                ChallengeToken: challengeToken,
+                ExpiresIn:      int(token.TwoFactorLifeTime().Seconds()),
+            },
+        }, nil
+    }
+    s.clearLoginFailures(ctx, email)
+
+    // Start a new refresh token family for this login
+    familyID, err := generateRandomToken(16)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to generate authentication token")
+    }
+
+    loginData, err := s.issueTokens(ctx, user, familyID)
     if err != nil {
         return nil, errs.InternalServerError("failed to generate authentication token")
     }
@@ -86,20 +163,76 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.LoginResponse, error) {
     return &dto.LoginResponse{
         Success: true,
         Message: "Login successful",
-        Data: dto.LoginData{
-            Token: token,
-            User: dto.UserData{
-                ID:    user.ID,
-                Name:  user.Name,
-                Email: user.Email,
-                Role:  user.Role,
-            },
-        },
+        Data:    loginData,
     }, nil
 }
 
-func (s *authService) GetProfile(userID int) (*dto.ProfileResponse, error) {
-    user, err := s.authRepo.FindByID(userID)
+// Refresh - Rotate a refresh token and issue a new access token.
+// Presenting a token that was already rotated revokes its whole family.
+func (s *authService) Refresh(ctx context.Context, req dto.RefreshTokenRequest) (*dto.RefreshTokenResponse, error) {
+    userID, err := token.ValidateRefreshToken(req.RefreshToken)
+    if err != nil {
+        return nil, errs.Unauthorized("invalid or expired refresh token")
+    }
+
+    stored, err := s.refreshTokenRepo.FindByHash(ctx, hashToken(req.RefreshToken))
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.Unauthorized("invalid or expired refresh token")
+        }
+        return nil, errs.InternalServerError("failed to verify refresh token")
+    }
+
+    if stored.UserID != userID || time.Now().After(stored.ExpiresAt) {
+        return nil, errs.Unauthorized("invalid or expired refresh token")
+    }
+
+    // Token already rotated: someone is replaying it, kill the whole family
+    if stored.RevokedAt != nil {
+        if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+            log.Printf("⚠️  Failed to revoke refresh token family %s: %v", stored.FamilyID, err)
+        }
+        return nil, errs.Unauthorized("refresh token has already been used, please login again")
+    }
+
+    revoked, err := s.refreshTokenRepo.Revoke(ctx, stored.ID)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to rotate refresh token")
+    }
+    if !revoked {
+        // Lost the race against a concurrent refresh with the same token
+        if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+            log.Printf("⚠️  Failed to revoke refresh token family %s: %v", stored.FamilyID, err)
+        }
+        return nil, errs.Unauthorized("refresh token has already been used, please login again")
+    }
+
+    user, err := s.authRepo.FindByID(ctx, userID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.Unauthorized("user no longer exists")
+        }
+        return nil, errs.InternalServerError("failed to fetch user")
+    }
+
+    if !user.IsActive {
+        return nil, errs.Forbidden("your account has been suspended")
+    }
+
+    loginData, err := s.issueTokens(ctx, user, stored.FamilyID)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to generate authentication token")
+    }
+
+    return &dto.RefreshTokenResponse{
+        Success: true,
+        Message: "Token refreshed successfully",
+        Data:    *loginData,
+    }, nil
+}
+
+func (s *authService) GetProfile(ctx context.Context, userID int) (*dto.ProfileResponse, error) {
+    user, err := s.authRepo.FindByID(ctx, userID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("user not found")
@@ -109,38 +242,466 @@ func (s *authService) GetProfile(userID int) (*dto.ProfileResponse, error) {
 
     return &dto.ProfileResponse{
         Success: true,
-        Data: dto.UserData{
-            ID:    user.ID,
-            Name:  user.Name,
-            Email: user.Email,
-            Role:  user.Role,
-        },
+        Data:    mapUserToDTO(user),
     }, nil
 }
 
-func (s *authService) generateToken(user *database.User) (string, error) {
-    cfg := config.Get()
+// Logout - Revoke the current access token and, if given, the refresh token family it belongs to
+func (s *authService) Logout(ctx context.Context, session *token.UserAuthToken, req dto.LogoutRequest) (*dto.LogoutResponse, error) {
+    if err := s.revokedTokenRepo.Create(ctx, &database.RevokedToken{
+        JTI:       session.TokenID,
+        UserID:    session.ID,
+        ExpiresAt: session.ExpiresAt,
+    }); err != nil {
+        return nil, errs.InternalServerError("failed to revoke token")
+    }
+
+    if req.RefreshToken != "" {
+        stored, err := s.refreshTokenRepo.FindByHash(ctx, hashToken(req.RefreshToken))
+        if err != nil && err != gorm.ErrRecordNotFound {
+            return nil, errs.InternalServerError("failed to revoke refresh token")
+        }
+        if err == nil && stored.UserID == session.ID {
+            if err := s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+                return nil, errs.InternalServerError("failed to revoke refresh token")
+            }
+        }
+    }
+
+    // Denylist entries are only needed until the token would have expired anyway
+    if err := s.revokedTokenRepo.DeleteExpired(ctx); err != nil {
+        log.Printf("⚠️  Failed to purge expired revoked tokens: %v", err)
+    }
+
+    return &dto.LogoutResponse{
+        Success: true,
+        Message: "Logout successful",
+    }, nil
+}
+
+// LogoutAll - Invalidate every access and refresh token ever issued to the user
+func (s *authService) LogoutAll(ctx context.Context, userID int) (*dto.LogoutResponse, error) {
+    if err := s.revokeAllSessions(ctx, userID); err != nil {
+        return nil, errs.InternalServerError("failed to revoke sessions")
+    }
 
-    // Token expiration menggunakan ACCESS_TOKEN_LIFETIME dari .env (dalam detik)
-    expirationTime := time.Now().Add(time.Duration(cfg.AccessTokenLifeTime) * time.Second)
+    return &dto.LogoutResponse{
+        Success: true,
+        Message: "Logged out from all devices",
+    }, nil
+}
 
-    claims := jwt.MapClaims{
-        "user_id": user.ID,
-        "email":   user.Email,
-        "role":    user.Role,
-        "exp":     expirationTime.Unix(),
+// ForgotPassword - Email a single-use reset link.
+// Always answers with the same message so the endpoint cannot be used to discover registered emails.
+func (s *authService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) (*dto.ForgotPasswordResponse, error) {
+    response := &dto.ForgotPasswordResponse{
+        Success: true,
+        Message: "If the email is registered, a password reset link has been sent",
     }
 
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+    user, err := s.authRepo.FindByEmail(ctx, normalizeEmail(req.Email))
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return response, nil
+        }
+        return nil, errs.InternalServerError("failed to process password reset request")
+    }
 
-    // TODO: Use RSA private key from config
-    // For now, use simple secret (should be replaced with proper RSA key)
-    secretKey := []byte("temporary-secret-key-replace-with-rsa")
+    // Only the most recent link stays valid
+    if err := s.passwordResetRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+        return nil, errs.InternalServerError("failed to process password reset request")
+    }
 
-    signedToken, err := token.SignedString(secretKey)
+    rawToken, err := generateRandomToken(32)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to generate reset token")
+    }
+
+    lifetime := time.Duration(config.Get().PasswordResetLifeTime) * time.Second
+    if err := s.passwordResetRepo.Create(ctx, &database.PasswordResetToken{
+        UserID:    user.ID,
+        TokenHash: hashToken(rawToken),
+        ExpiresAt: time.Now().Add(lifetime),
+    }); err != nil {
+        return nil, errs.InternalServerError("failed to process password reset request")
+    }
+
+    go func() {
+        if err := s.emailService.SendPasswordReset(context.WithoutCancel(ctx), user, rawToken, lifetime); err != nil {
+            log.Printf("❌ [Email] Failed to send password reset email: %v", err)
+        } else {
+            log.Printf("✅ [Email] Password reset email sent for User #%d", user.ID)
+        }
+    }()
+
+    return response, nil
+}
+
+// ResetPassword - Set a new password using a reset token, then sign the user out everywhere
+func (s *authService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) (*dto.ResetPasswordResponse, error) {
+    resetToken, err := s.passwordResetRepo.FindByHash(ctx, hashToken(req.Token))
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.BadRequest("invalid or expired reset token")
+        }
+        return nil, errs.InternalServerError("failed to verify reset token")
+    }
+
+    if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+        return nil, errs.BadRequest("invalid or expired reset token")
+    }
+
+    hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
     if err != nil {
+        return nil, errs.InternalServerError("failed to process password")
+    }
+
+    // Consume the token, change the password and end every session together,
+    // so a failure part way leaves the token usable for another try
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        used, err := repos.PasswordReset.MarkUsed(ctx, resetToken.ID)
+        if err != nil {
+            return errs.InternalServerError("failed to verify reset token")
+        }
+        if !used {
+            return errs.BadRequest("invalid or expired reset token")
+        }
+
+        if err := repos.Auth.UpdatePassword(ctx, resetToken.UserID, string(hashedPassword)); err != nil {
+            return errs.InternalServerError("failed to reset password")
+        }
+
+        if err := repos.Auth.IncrementTokenVersion(ctx, resetToken.UserID); err != nil {
+            return errs.InternalServerError("failed to reset password")
+        }
+        if err := repos.RefreshToken.RevokeAllByUserID(ctx, resetToken.UserID); err != nil {
+            return errs.InternalServerError("failed to reset password")
+        }
+
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.ResetPasswordResponse{
+        Success: true,
+        Message: "Password has been reset successfully. Please login with your new password.",
+    }, nil
+}
+
+// VerifyEmail - Mark the user's email as verified using the signed link from the verification email
+func (s *authService) VerifyEmail(ctx context.Context, verificationToken string) (*dto.VerifyEmailResponse, error) {
+    userID, email, err := token.ValidateEmailVerificationToken(verificationToken)
+    if err != nil {
+        return nil, errs.BadRequest("invalid or expired verification link")
+    }
+
+    user, err := s.authRepo.FindByID(ctx, userID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.BadRequest("invalid or expired verification link")
+        }
+        return nil, errs.InternalServerError("failed to verify email")
+    }
+
+    // Link was issued for an address the user no longer uses
+    if user.Email != email {
+        return nil, errs.BadRequest("invalid or expired verification link")
+    }
+
+    if user.EmailVerifiedAt != nil {
+        return &dto.VerifyEmailResponse{
+            Success: true,
+            Message: "Email is already verified",
+        }, nil
+    }
+
+    if err := s.authRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+        return nil, errs.InternalServerError("failed to verify email")
+    }
+
+    return &dto.VerifyEmailResponse{
+        Success: true,
+        Message: "Email verified successfully",
+    }, nil
+}
+
+// ResendVerification - Send a fresh verification link to the logged in user
+func (s *authService) ResendVerification(ctx context.Context, userID int) (*dto.ResendVerificationResponse, error) {
+    user, err := s.authRepo.FindByID(ctx, userID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("user not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch user")
+    }
+
+    if user.EmailVerifiedAt != nil {
+        return nil, errs.BadRequest("email is already verified")
+    }
+
+    s.sendVerificationEmail(ctx, user)
+
+    return &dto.ResendVerificationResponse{
+        Success: true,
+        Message: "Verification email has been sent",
+    }, nil
+}
+
+// ValidateSession - Check a parsed access token against server-side state.
+// The returned data carries the user's current role, so a demotion applies on the next request.
+func (s *authService) ValidateSession(ctx context.Context, claims *token.UserAuthToken) (*token.UserAuthToken, error) {
+    if claims.TokenID == "" {
+        return nil, errs.Unauthorized("invalid token")
+    }
+
+    revoked, err := s.revokedTokenRepo.IsRevoked(ctx, claims.TokenID)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to verify session")
+    }
+    if revoked {
+        return nil, errs.Unauthorized("token has been revoked")
+    }
+
+    user, err := s.authRepo.FindByID(ctx, claims.ID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.Unauthorized("user no longer exists")
+        }
+        return nil, errs.InternalServerError("failed to verify session")
+    }
+
+    if !user.IsActive {
+        return nil, errs.Forbidden("your account has been suspended")
+    }
+
+    if user.TokenVersion != claims.TokenVersion {
+        return nil, errs.Unauthorized("session has been revoked, please login again")
+    }
+
+    return &token.UserAuthToken{
+        ID:           user.ID,
+        Email:        user.Email,
+        Role:         user.Role,
+        TokenVersion: user.TokenVersion,
+        TokenID:      claims.TokenID,
+        ExpiresAt:    claims.ExpiresAt,
+        TwoFactor:    user.TOTPEnabledAt != nil,
+    }, nil
+}
+
+// revokeAllSessions - Bump the token version and revoke every refresh token of the user
+func (s *authService) revokeAllSessions(ctx context.Context, userID int) error {
+    if err := s.authRepo.IncrementTokenVersion(ctx, userID); err != nil {
+        return err
+    }
+    return s.refreshTokenRepo.RevokeAllByUserID(ctx, userID)
+}
+
+// UpdateProfile - Update name and/or email of the logged in user.
+// Changing the email resets verification and sends a new verification link.
+func (s *authService) UpdateProfile(ctx context.Context, userID int, req dto.UpdateProfileRequest) (*dto.UpdateProfileResponse, error) {
+    user, err := s.authRepo.FindByID(ctx, userID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("user not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch user profile")
+    }
+
+    if req.Name != nil {
+        user.Name = *req.Name
+    }
+
+    // Emails are compared and stored in normalized form, so a change in case or
+    // surrounding spaces is not a new address
+    emailChanged := req.Email != nil && normalizeEmail(*req.Email) != normalizeEmail(user.Email)
+    if emailChanged {
+        email := normalizeEmail(*req.Email)
+        _, err := s.authRepo.FindByEmail(ctx, email)
+        if err == nil {
+            return nil, errs.BadRequest("email already registered")
+        }
+        if err != gorm.ErrRecordNotFound {
+            return nil, errs.InternalServerError("failed to check email availability")
+        }
+
+        user.Email = email
+        user.EmailVerifiedAt = nil
+    }
+
+    if req.Phone != nil {
+        user.Phone = *req.Phone
+    }
+    if req.NotificationChannel != nil {
+        user.NotifyChannel = *req.NotificationChannel
+    }
+    // WhatsApp and SMS go to the phone number; without one they would silently fall back to email
+    if user.NotifyChannel != "" && user.NotifyChannel != string(notify.ChannelEmail) && user.Phone == "" {
+        return nil, errs.BadRequest("a phone number is required for " + user.NotifyChannel + " notifications")
+    }
+
+    if err := s.authRepo.UpdateProfile(ctx, user); err != nil {
+        if errors.Is(err, database.ErrEmailTaken) {
+            return nil, errs.BadRequest("email already registered")
+        }
+        return nil, errs.InternalServerError("failed to update profile")
+    }
+
+    message := "Profile updated successfully"
+    if emailChanged {
+        s.sendVerificationEmail(ctx, user)
+        message = "Profile updated successfully. Please check your new email to verify it."
+    }
+
+    return &dto.UpdateProfileResponse{
+        Success: true,
+        Message: message,
+        Data:    mapUserToDTO(user),
+    }, nil
+}
+
+// ChangePassword - Change password after checking the current one, then sign out every session
+func (s *authService) ChangePassword(ctx context.Context, userID int, req dto.ChangePasswordRequest) (*dto.ChangePasswordResponse, error) {
+    user, err := s.authRepo.FindByID(ctx, userID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("user not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch user")
+    }
+
+    if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+        return nil, errs.BadRequest("current password is incorrect")
+    }
+
+    if req.NewPassword == req.CurrentPassword {
+        return nil, errs.BadRequest("new password must be different from the current password")
+    }
+
+    hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to process password")
+    }
+
+    // Change the password and end every session together, so the old sessions
+    // can never outlive the old password
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        if err := repos.Auth.UpdatePassword(ctx, user.ID, string(hashedPassword)); err != nil {
+            return errs.InternalServerError("failed to change password")
+        }
+
+        if err := repos.Auth.IncrementTokenVersion(ctx, user.ID); err != nil {
+            return errs.InternalServerError("failed to change password")
+        }
+        if err := repos.RefreshToken.RevokeAllByUserID(ctx, user.ID); err != nil {
+            return errs.InternalServerError("failed to change password")
+        }
+
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.ChangePasswordResponse{
+        Success: true,
+        Message: "Password changed successfully. Please login again.",
+    }, nil
+}
+
+// issueTokens - Generate an access token and a refresh token in the given family
+func (s *authService) issueTokens(ctx context.Context, user *database.User, familyID string) (*dto.LoginData, error) {
+    accessToken, err := s.generateToken(user)
+    if err != nil {
+        return nil, err
+    }
+
+    refreshToken, err := token.GenerateRefreshToken(user.ID)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := s.refreshTokenRepo.Create(ctx, &database.RefreshToken{
+        UserID:    user.ID,
+        FamilyID:  familyID,
+        TokenHash: hashToken(refreshToken),
+        ExpiresAt: time.Now().Add(token.RefreshTokenLifeTime()),
+    }); err != nil {
+        return nil, err
+    }
+
+    return &dto.LoginData{
+        Token:                  accessToken,
+        RefreshToken:           refreshToken,
+        ExpiresIn:              int(token.AccessTokenLifeTime().Seconds()),
+        TwoFactorSetupRequired: isTwoFactorMandatory(user.Role) && user.TOTPEnabledAt == nil,
+        User:                   mapUserToDTO(user),
+    }, nil
+}
+
+func (s *authService) generateToken(user *database.User) (string, error) {
+    // Token is signed with the RSA private key (PRIVATE_KEY) and expires
+    // after ACCESS_TOKEN_LIFE_TIME seconds
+    return token.GenerateToken(&token.UserAuthToken{
+        ID:           user.ID,
+        Email:        user.Email,
+        Role:         user.Role,
+        TokenVersion: user.TokenVersion,
+    })
+}
+
+// sendVerificationEmail - Generate a signed verification link and email it in the background
+func (s *authService) sendVerificationEmail(ctx context.Context, user *database.User) {
+    verificationToken, err := token.GenerateEmailVerificationToken(user.ID, user.Email)
+    if err != nil {
+        log.Printf("❌ [Email] Failed to generate verification token for User #%d: %v", user.ID, err)
+        return
+    }
+
+    go func() {
+        if err := s.emailService.SendEmailVerification(context.WithoutCancel(ctx), user, verificationToken, token.EmailVerifyLifeTime()); err != nil {
+            log.Printf("❌ [Email] Failed to send verification email: %v", err)
+        } else {
+            log.Printf("✅ [Email] Verification email sent for User #%d", user.ID)
+        }
+    }()
+}
+
+// mapUserToDTO - Map user model to the public user data
+func mapUserToDTO(user *database.User) dto.UserData {
+    return dto.UserData{
+        ID:               user.ID,
+        Name:             user.Name,
+        Email:            user.Email,
+        Role:             user.Role,
+        EmailVerified:    user.EmailVerifiedAt != nil,
+        TwoFactorEnabled: user.TOTPEnabledAt != nil,
+        Phone:            user.Phone,
+        NotifyChannel:    notifyChannelOf(user),
+    }
+}
+
+// notifyChannelOf - The user's notification channel; email unless they picked another one
+func notifyChannelOf(user *database.User) string {
+    if user.NotifyChannel == "" {
+        return string(notify.ChannelEmail)
+    }
+    return user.NotifyChannel
+}
+
+// generateRandomToken - Return n cryptographically random bytes, hex encoded
+func generateRandomToken(n int) (string, error) {
+    b := make([]byte, n)
+    if _, err := rand.Read(b); err != nil {
         return "", err
     }
+    return hex.EncodeToString(b), nil
+}
 
-    return signedToken, nil
-}
\ No newline at end of file
+// hashToken - SHA-256 of a secret token, so raw tokens are never stored
+func hashToken(raw string) string {
+    sum := sha256.Sum256([]byte(raw))
+    return hex.EncodeToString(sum[:])
+}
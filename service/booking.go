@@ -1,11 +1,14 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"time"
 
+	"github.com/RaFYWStud/BackendBookingStudio/config"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/database"
@@ -16,25 +19,46 @@ import (
 type bookingService struct {
     bookingRepo  contract.BookingRepository
     studioRepo   contract.StudioRepository
-    emailService contract.EmailService
+    authRepo     contract.AuthRepository
+    unitOfWork   contract.UnitOfWork
+    notifier     contract.NotificationService
 }
 
 func ImplBookingService(
     bookingRepo contract.BookingRepository,
     studioRepo contract.StudioRepository,
-    emailService contract.EmailService,
+    authRepo contract.AuthRepository,
+    unitOfWork contract.UnitOfWork,
+    notificationService contract.NotificationService,
 ) contract.BookingService {
     return &bookingService{
         bookingRepo:  bookingRepo,
         studioRepo:   studioRepo,
-        emailService: emailService,
+        authRepo:     authRepo,
+        unitOfWork:   unitOfWork,
+        notifier:     notificationService,
     }
 }
 
 // CreateBooking - Customer create new booking (with auto-calculate duration)
-func (s *bookingService) CreateBooking(userID int, req dto.CreateBookingRequest) (*dto.CreateBookingResponse, error) {
+func (s *bookingService) CreateBooking(ctx context.Context, userID int, req dto.CreateBookingRequest) (*dto.CreateBookingResponse, error) {
+    // 0. Optionally require a verified email, so booking emails reach a real inbox
+    if config.Get().RequireVerifiedEmail {
+        user, err := s.authRepo.FindByID(ctx, userID)
+        if err != nil {
+            if err == gorm.ErrRecordNotFound {
+                return nil, errs.Unauthorized("user not found")
+            }
+            return nil, errs.InternalServerError("failed to verify user")
+        }
+
+        if user.EmailVerifiedAt == nil {
+            return nil, errs.Forbidden("please verify your email address before making a booking")
+        }
+    }
+
     // 1. Verify studio exists and active
-    studio, err := s.studioRepo.FindByID(req.StudioID)
+    studio, err := s.studioRepo.FindByID(ctx, req.StudioID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("studio not found")
@@ -52,10 +76,6 @@ func (s *bookingService) CreateBooking(userID int, req dto.CreateBookingRequest)
         return nil, errs.BadRequest("invalid booking date format, use YYYY-MM-DD")
     }
 
-    if bookingDate.Before(time.Now().Truncate(24 * time.Hour)) {
-        return nil, errs.BadRequest("cannot book studio in the past")
-    }
-
     startTime, err := time.Parse("15:04", req.StartTime)
     if err != nil {
         return nil, errs.BadRequest("invalid start_time format, use HH:MM")
@@ -66,12 +86,24 @@ func (s *bookingService) CreateBooking(userID int, req dto.CreateBookingRequest)
         return nil, errs.BadRequest("invalid end_time format, use HH:MM")
     }
 
-    if endTime.Before(startTime) || endTime.Equal(startTime) {
-        return nil, errs.BadRequest("end_time must be after start_time")
+    // An end_time before start_time ends the next day, for studios open past midnight
+    if endTime.Equal(startTime) {
+        return nil, errs.BadRequest("end_time must differ from start_time")
+    }
+
+    // 2a. The session must not have started yet by the studio's clock
+    if !sessionStartIn(bookingDate, startTime, studio.TimeLocation()).After(time.Now()) {
+        return nil, errs.BadRequest("cannot book studio in the past")
+    }
+
+    // 2b. The whole session must fall within the studio's operating hours
+    if !studio.OperatingHours.Covers(bookingDate, startTime, endTime) {
+        return nil, errs.BadRequest(closedMessage(studio, bookingDate))
     }
 
     // 3. AUTO-CALCULATE duration_hours
-    duration := endTime.Sub(startTime)
+    startMinute, endMinute := database.SessionMinutes(startTime, endTime)
+    duration := time.Duration(endMinute-startMinute) * time.Minute
     durationHours := int(math.Ceil(duration.Hours()))
 
     // Jika user kirim duration_hours, validasi apakah sesuai
@@ -80,24 +112,13 @@ func (s *bookingService) CreateBooking(userID int, req dto.CreateBookingRequest)
         // Tetap gunakan calculated value untuk konsistensi
     }
 
-    if durationHours < 1 {
-        return nil, errs.BadRequest("minimum booking duration is 1 hour")
-    }
-
-    // 4. Check studio availability
-    isAvailable, err := s.studioRepo.IsStudioAvailable(req.StudioID, bookingDate, startTime, endTime)
-    if err != nil {
-        return nil, errs.InternalServerError("failed to check availability")
-    }
-
-    if !isAvailable {
-        return nil, errs.BadRequest("studio is not available for the selected time slot")
+    if minDuration := config.Get().BookingMinDuration; duration < minDuration {
+        return nil, errs.BadRequest("minimum booking duration is " + formatDuration(minDuration))
     }
 
-    // 5. Calculate total price (using auto-calculated duration)
+    // 4. Calculate total price (using auto-calculated duration)
     totalPrice := durationHours * studio.PricePerHour
 
-    // 6. Create booking - status: pending (menunggu pembayaran manual via WhatsApp)
     booking := &database.Booking{
         UserID:        userID,
         StudioID:      req.StudioID,
@@ -108,36 +129,55 @@ func (s *bookingService) CreateBooking(userID int, req dto.CreateBookingRequest)
         TotalPrice:    totalPrice,
         Status:        database.BookingStatusPending,
     }
+    deadline := paymentDeadline(time.Now(), bookingDate, startTime, studio.TimeLocation())
+    booking.PaymentDeadline = &deadline
 
-    if err := s.bookingRepo.Create(booking); err != nil {
-        return nil, errs.InternalServerError("failed to create booking")
-    }
+    // 5-8. Check closures and availability, create and reload the booking and queue its notification in one transaction
+    var bookingWithRelations *database.Booking
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        // 5. Not inside a closure and free of other bookings; the lock keeps closures and other bookings
+        // of the studio from being committed between the check and the insert
+        if err := repos.Lock.LockStudioSchedule(ctx, &studio.ID); err != nil {
+            return errs.InternalServerError("failed to lock studio schedule")
+        }
+        if err := checkSlotFree(ctx, repos, studio, bookingDate, startTime, endTime); err != nil {
+            return err
+        }
 
-    // 7. Load booking with relations for email
-    bookingWithRelations, err := s.bookingRepo.FindByIDWithRelations(booking.ID)
-    if err != nil {
-        log.Printf("⚠️  Failed to load booking relations for email: %v", err)
-        bookingWithRelations = booking
-        bookingWithRelations.Studio = studio
-    }
+        // 6. Create booking - status: pending (menunggu pembayaran manual via WhatsApp)
+        // The check above is only a fast path: a concurrent request may take the slot in between,
+        // in which case the database constraint rejects this insert
+        if err := repos.Booking.Create(ctx, booking); err != nil {
+            if errors.Is(err, database.ErrBookingOverlap) {
+                return errs.Conflict("studio is not available for the selected time slot")
+            }
+            return errs.InternalServerError("failed to create booking")
+        }
 
-    // 8. Send email notification
-    go func() {
-        if err := s.emailService.SendBookingCreated(bookingWithRelations); err != nil {
-            log.Printf("❌ [Email] Failed to send booking created email: %v", err)
-        } else {
-            log.Printf("✅ [Email] Booking created email sent for Booking #%d", booking.ID)
+        // 7. Load booking with relations for the notification
+        bookingWithRelations, err = repos.Booking.FindByIDWithRelations(ctx, booking.ID)
+        if err != nil {
+            return errs.InternalServerError("failed to load booking")
         }
-    }()
+
+        // 8. Queue the notification, delivered by the outbox dispatcher
+        msg, err := s.notifier.ComposeBookingCreated(bookingWithRelations)
+        return queueNotification(ctx, repos, msg, err)
+    })
+    if err != nil {
+        return nil, err
+    }
 
     adminWhatsApp := getEnv("ADMIN_WHATSAPP_DISPLAY", "0895-7060-8111")
 
     message := fmt.Sprintf(
         "Booking berhasil dibuat. Total pembayaran: Rp %s.\n\n"+
             "📱 Silakan hubungi admin untuk pembayaran:\n"+
-            "WhatsApp: %s",
+            "WhatsApp: %s\n\n"+
+            "⏳ Selesaikan pembayaran sebelum %s, setelah itu booking otomatis dibatalkan.",
         formatRupiah(totalPrice),
         adminWhatsApp,
+        deadline.In(studio.TimeLocation()).Format("02 Jan 2006 15:04"),
     )
 
     return &dto.CreateBookingResponse{
@@ -148,7 +188,7 @@ func (s *bookingService) CreateBooking(userID int, req dto.CreateBookingRequest)
 }
 
 // GetMyBookings - Customer get their bookings
-func (s *bookingService) GetMyBookings(userID int, filter dto.BookingFilterRequest) (*dto.BookingListResponse, error) {
+func (s *bookingService) GetMyBookings(ctx context.Context, userID int, filter dto.BookingFilterRequest) (*dto.BookingListResponse, error) {
     // Set default pagination
     if filter.Page < 1 {
         filter.Page = 1
@@ -157,7 +197,7 @@ func (s *bookingService) GetMyBookings(userID int, filter dto.BookingFilterReque
         filter.Limit = 10
     }
 
-    bookings, total, err := s.bookingRepo.FindByUserID(userID, filter)
+    bookings, total, err := s.bookingRepo.FindByUserID(ctx, userID, filter)
     if err != nil {
         return nil, errs.InternalServerError("failed to fetch bookings")
     }
@@ -182,8 +222,8 @@ func (s *bookingService) GetMyBookings(userID int, filter dto.BookingFilterReque
 }
 
 // GetBookingDetail - Get booking detail with full relations
-func (s *bookingService) GetBookingDetail(bookingID int, userID int, isAdmin bool) (*dto.BookingResponse, error) {
-    booking, err := s.bookingRepo.FindByIDWithRelations(bookingID)
+func (s *bookingService) GetBookingDetail(ctx context.Context, bookingID int, userID int, canViewAll bool) (*dto.BookingResponse, error) {
+    booking, err := s.bookingRepo.FindByIDWithRelations(ctx, bookingID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("booking not found")
@@ -192,7 +232,7 @@ func (s *bookingService) GetBookingDetail(bookingID int, userID int, isAdmin boo
     }
 
     // Authorization check
-    if !isAdmin && booking.UserID != userID {
+    if !canViewAll && booking.UserID != userID {
         return nil, errs.Forbidden("you don't have access to this booking")
     }
 
@@ -203,7 +243,7 @@ func (s *bookingService) GetBookingDetail(bookingID int, userID int, isAdmin boo
 }
 
 // GetAllBookings - Admin get all bookings
-func (s *bookingService) GetAllBookings(filter dto.BookingFilterRequest) (*dto.BookingListResponse, error) {
+func (s *bookingService) GetAllBookings(ctx context.Context, filter dto.BookingFilterRequest) (*dto.BookingListResponse, error) {
     // Set default pagination
     if filter.Page < 1 {
         filter.Page = 1
@@ -212,7 +252,7 @@ func (s *bookingService) GetAllBookings(filter dto.BookingFilterRequest) (*dto.B
         filter.Limit = 10
     }
 
-    bookings, total, err := s.bookingRepo.FindAll(filter, nil)
+    bookings, total, err := s.bookingRepo.FindAll(ctx, filter, nil)
     if err != nil {
         return nil, errs.InternalServerError("failed to fetch bookings")
     }
@@ -237,8 +277,8 @@ func (s *bookingService) GetAllBookings(filter dto.BookingFilterRequest) (*dto.B
 }
 
 // UpdateBookingStatus - Admin update booking status
-func (s *bookingService) UpdateBookingStatus(bookingID int, req dto.UpdateBookingStatusRequest) (*dto.UpdateBookingStatusResponse, error) {
-    booking, err := s.bookingRepo.FindByID(bookingID)
+func (s *bookingService) UpdateBookingStatus(ctx context.Context, bookingID int, req dto.UpdateBookingStatusRequest) (*dto.UpdateBookingStatusResponse, error) {
+    booking, err := s.bookingRepo.FindByID(ctx, bookingID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("booking not found")
@@ -262,44 +302,91 @@ func (s *bookingService) UpdateBookingStatus(bookingID int, req dto.UpdateBookin
         return nil, errs.BadRequest("can only reopen cancelled booking to pending status")
     }
 
+    if oldStatus == database.BookingStatusExpired && newStatus != database.BookingStatusPending {
+        return nil, errs.BadRequest("can only reopen expired booking to pending status")
+    }
+
     // Update booking
     booking.Status = newStatus
     if req.AdminNotes != "" {
         booking.AdminNotes = req.AdminNotes
     }
 
-    if err := s.bookingRepo.Update(booking); err != nil {
-        return nil, errs.InternalServerError("failed to update booking status")
-    }
+    // A cancelled or expired booking no longer holds its slot, so reopening it is checked like a new booking
+    reopening := oldStatus == database.BookingStatusCancelled || oldStatus == database.BookingStatusExpired
 
-    // Reload with relations
-    bookingWithRelations, err := s.bookingRepo.FindByIDWithRelations(bookingID)
-    if err != nil {
-        log.Printf("⚠️  Failed to reload booking: %v", err)
+    // A reopened booking gets a fresh payment window
+    var studio *database.Studio
+    if newStatus == database.BookingStatusPending {
+        studio, err = s.studioRepo.FindByID(ctx, booking.StudioID)
+        if err != nil {
+            return nil, errs.InternalServerError("failed to fetch studio")
+        }
+
+        if !sessionStartIn(booking.BookingDate, booking.StartTime, studio.TimeLocation()).After(time.Now()) {
+            return nil, errs.BadRequest("the session of this booking has already started")
+        }
+        if reopening && !studio.OperatingHours.Covers(booking.BookingDate, booking.StartTime, booking.EndTime) {
+            return nil, errs.BadRequest(closedMessage(studio, booking.BookingDate))
+        }
+
+        deadline := paymentDeadline(time.Now(), booking.BookingDate, booking.StartTime, studio.TimeLocation())
+        booking.PaymentDeadline = &deadline
     }
 
-    // Send email notification based on status
-    go func() {
-        var emailErr error
+    // Update, reload and queue the customer's notification in one transaction
+    var bookingWithRelations *database.Booking
+    notified := false
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        if reopening {
+            if err := repos.Lock.LockStudioSchedule(ctx, &studio.ID); err != nil {
+                return errs.InternalServerError("failed to lock studio schedule")
+            }
+            if err := checkSlotFree(ctx, repos, studio, booking.BookingDate, booking.StartTime, booking.EndTime); err != nil {
+                return err
+            }
+        }
+
+        if err := repos.Booking.Update(ctx, booking); err != nil {
+            // Reopening a cancelled or expired booking fails if its slot has been booked again
+            if errors.Is(err, database.ErrBookingOverlap) {
+                return errs.Conflict("the time slot of this booking has been taken by another booking")
+            }
+            return errs.InternalServerError("failed to update booking status")
+        }
+
+        // Reload with relations
+        bookingWithRelations, err = repos.Booking.FindByIDWithRelations(ctx, bookingID)
+        if err != nil {
+            return errs.InternalServerError("failed to load booking")
+        }
+
+        // Email notification based on status
+        var msg *database.OutboxMessage
         switch newStatus {
         case database.BookingStatusConfirmed:
-            emailErr = s.emailService.SendBookingConfirmed(bookingWithRelations)
+            msg, err = s.notifier.ComposeBookingConfirmed(bookingWithRelations)
         case database.BookingStatusCancelled:
             reason := req.AdminNotes
             if reason == "" {
                 reason = "Cancelled by admin"
             }
-            emailErr = s.emailService.SendBookingCancelled(bookingWithRelations, reason)
+            msg, err = s.notifier.ComposeBookingCancelled(bookingWithRelations, reason)
+        default:
+            return nil
         }
 
-        if emailErr != nil {
-            log.Printf("❌ [Email] Failed to send status update email: %v", emailErr)
-        } else {
-            log.Printf("✅ [Email] Status update email sent for Booking #%d (status: %s)", bookingID, newStatus)
-        }
-    }()
+        notified = true
+        return queueNotification(ctx, repos, msg, err)
+    })
+    if err != nil {
+        return nil, err
+    }
 
-    message := fmt.Sprintf("Booking status updated to %s. Customer has been notified via email.", newStatus)
+    message := fmt.Sprintf("Booking status updated to %s.", newStatus)
+    if notified {
+        message += " Customer will be notified."
+    }
 
     return &dto.UpdateBookingStatusResponse{
         Success: true,
@@ -309,8 +396,8 @@ func (s *bookingService) UpdateBookingStatus(bookingID int, req dto.UpdateBookin
 }
 
 // CancelBooking - Customer cancel their booking
-func (s *bookingService) CancelBooking(bookingID int, userID int, req dto.CancelBookingRequest) (*dto.CancelBookingResponse, error) {
-    booking, err := s.bookingRepo.FindByID(bookingID)
+func (s *bookingService) CancelBooking(ctx context.Context, bookingID int, userID int, req dto.CancelBookingRequest) (*dto.CancelBookingResponse, error) {
+    booking, err := s.bookingRepo.FindByID(ctx, bookingID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("booking not found")
@@ -332,37 +419,177 @@ func (s *bookingService) CancelBooking(bookingID int, userID int, req dto.Cancel
         return nil, errs.BadRequest("cannot cancel completed booking")
     }
 
+    if booking.Status == database.BookingStatusExpired {
+        return nil, errs.BadRequest("booking has already expired")
+    }
+
     // Update status
     booking.Status = database.BookingStatusCancelled
     booking.AdminNotes = fmt.Sprintf("Cancelled by customer. Reason: %s", req.Reason)
 
-    if err := s.bookingRepo.Update(booking); err != nil {
-        return nil, errs.InternalServerError("failed to cancel booking")
-    }
+    // Update, reload and queue the cancellation notification in one transaction
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        if err := repos.Booking.Update(ctx, booking); err != nil {
+            return errs.InternalServerError("failed to cancel booking")
+        }
 
-    // Reload with relations
-    bookingWithRelations, err := s.bookingRepo.FindByIDWithRelations(bookingID)
+        bookingWithRelations, err := repos.Booking.FindByIDWithRelations(ctx, bookingID)
+        if err != nil {
+            return errs.InternalServerError("failed to load booking")
+        }
+
+        msg, err := s.notifier.ComposeBookingCancelled(bookingWithRelations, req.Reason)
+        return queueNotification(ctx, repos, msg, err)
+    })
     if err != nil {
-        log.Printf("⚠️  Failed to reload booking: %v", err)
+        return nil, err
     }
 
-    // Send email
-    go func() {
-        if err := s.emailService.SendBookingCancelled(bookingWithRelations, req.Reason); err != nil {
-            log.Printf("❌ [Email] Failed to send cancellation email: %v", err)
-        } else {
-            log.Printf("✅ [Email] Cancellation email sent for Booking #%d", bookingID)
-        }
-    }()
-
     return &dto.CancelBookingResponse{
         Success: true,
         Message: "Booking cancelled successfully. Admin has been notified.",
     }, nil
 }
 
+// ExpireOverdueBookings - Expire pending bookings past their payment deadline and notify the customers.
+// Returns how many bookings were expired.
+func (s *bookingService) ExpireOverdueBookings(ctx context.Context) (int, error) {
+    now := time.Now()
+
+    bookings, err := s.bookingRepo.FindExpiredBookings(ctx, now)
+    if err != nil {
+        return 0, err
+    }
+
+    expired := 0
+    for _, booking := range bookings {
+        // Expire and queue the notification in one transaction
+        ok := false
+        err := s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+            // Skip bookings confirmed or cancelled since they were fetched
+            changed, err := repos.Booking.Expire(ctx, booking.ID, now)
+            if err != nil || !changed {
+                return err
+            }
+
+            bookingWithRelations, err := repos.Booking.FindByIDWithRelations(ctx, booking.ID)
+            if err != nil {
+                return err
+            }
+
+            msg, err := s.notifier.ComposeBookingExpired(bookingWithRelations)
+            if err := queueNotification(ctx, repos, msg, err); err != nil {
+                return err
+            }
+
+            ok = true
+            return nil
+        })
+        if err != nil {
+            return expired, err
+        }
+        if ok {
+            expired++
+        }
+    }
+
+    return expired, nil
+}
+
+// bookingCompletionLock - Advisory lock held while completing bookings, so only one server instance runs the job at a time
+const bookingCompletionLock = "booking_completion"
+
+// CompleteFinishedBookings - Mark confirmed bookings as completed once their session has ended in the studio's timezone.
+// Every change is recorded in booking_status_logs. Returns how many bookings were completed,
+// zero when another server instance is already running the job.
+func (s *bookingService) CompleteFinishedBookings(ctx context.Context) (int, error) {
+    var completed int64
+
+    err := s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        locked, err := repos.Lock.TryAdvisoryLock(ctx, bookingCompletionLock)
+        if err != nil || !locked {
+            return err
+        }
+
+        completed, err = repos.Booking.CompleteFinished(ctx, time.Now())
+        return err
+    })
+    if err != nil {
+        return 0, err
+    }
+
+    return int(completed), nil
+}
+
 // ============= HELPER FUNCTIONS =============
 
+// queueNotification - Store a composed notification in the outbox. Call it inside the transaction of the change it reports,
+// so the notification is only sent if the change is committed, and is never lost once it is.
+func queueNotification(ctx context.Context, repos *contract.Repository, msg *database.OutboxMessage, err error) error {
+    if err != nil {
+        return errs.InternalServerError("failed to prepare notification")
+    }
+    if err := repos.Outbox.Create(ctx, msg); err != nil {
+        return errs.InternalServerError("failed to queue notification")
+    }
+    return nil
+}
+
+// closedMessage - Why a session on date is refused, with the studio's hours that day
+func closedMessage(studio *database.Studio, date time.Time) string {
+    hours, ok := studio.OperatingHours.Hours(date.Weekday())
+    if !ok {
+        return fmt.Sprintf("studio is closed on %s", date.Weekday())
+    }
+    return fmt.Sprintf("the selected time is outside operating hours, on %s the studio is open %s-%s", date.Weekday(), hours.Open, hours.Close)
+}
+
+// checkSlotFree - The session is not inside a closure of the studio (maintenance, holiday, private event)
+// and no other pending or confirmed booking is within the studio's buffer time of it. Call it holding the
+// studio's schedule lock.
+func checkSlotFree(ctx context.Context, repos *contract.Repository, studio *database.Studio, date, startTime, endTime time.Time) error {
+    sessionStart, sessionEnd := database.SessionBounds(date, startTime, endTime)
+    closures, err := repos.Closure.FindOccurrences(ctx, studio.ID, sessionStart, sessionEnd)
+    if err != nil {
+        return errs.InternalServerError("failed to check studio closures")
+    }
+    if len(closures) > 0 {
+        return errs.Conflict("studio is closed at the selected time: " + closures[0].Reason)
+    }
+
+    isAvailable, err := repos.Studio.IsStudioAvailable(ctx, studio.ID, date, startTime, endTime)
+    if err != nil {
+        return errs.InternalServerError("failed to check availability")
+    }
+    if !isAvailable {
+        return errs.Conflict("studio is not available for the selected time slot")
+    }
+    return nil
+}
+
+// sessionStartIn - The instant a session starts, reading its wall clock time in loc
+func sessionStartIn(bookingDate, startTime time.Time, loc *time.Location) time.Time {
+    return time.Date(
+        bookingDate.Year(), bookingDate.Month(), bookingDate.Day(),
+        startTime.Hour(), startTime.Minute(), 0, 0, loc,
+    )
+}
+
+// paymentDeadline - A pending booking holds its slot for BookingPaymentHold, but never past the session start
+// (wall clock time of the studio, in loc) and never before now
+func paymentDeadline(now, bookingDate, startTime time.Time, loc *time.Location) time.Time {
+    deadline := now.Add(config.Get().BookingPaymentHold)
+
+    if sessionStart := sessionStartIn(bookingDate, startTime, loc); sessionStart.Before(deadline) {
+        deadline = sessionStart
+    }
+    if deadline.Before(now) {
+        deadline = now
+    }
+
+    return deadline
+}
+
 // mapBookingToDTO - Basic mapping (untuk list)
 func (s *bookingService) mapBookingToDTO(booking *database.Booking) dto.BookingData {
     data := dto.BookingData{
@@ -376,10 +603,19 @@ func (s *bookingService) mapBookingToDTO(booking *database.Booking) dto.BookingD
         TotalPrice:    booking.TotalPrice,
         Status:        string(booking.Status),
         AdminNotes:    booking.AdminNotes,
+        ClosureID:     booking.ClosureID,
         CreatedAt:     booking.CreatedAt.Format("2006-01-02 15:04:05"),
         UpdatedAt:     booking.UpdatedAt.Format("2006-01-02 15:04:05"),
     }
 
+    if booking.PaymentDeadline != nil {
+        deadline := *booking.PaymentDeadline
+        if booking.Studio != nil {
+            deadline = deadline.In(booking.Studio.TimeLocation())
+        }
+        data.PaymentDeadline = deadline.Format("2006-01-02 15:04:05")
+    }
+
     // Include studio if loaded
     if booking.Studio != nil {
         data.Studio = &dto.StudioData{
@@ -389,7 +625,7 @@ func (s *bookingService) mapBookingToDTO(booking *database.Booking) dto.BookingD
             PricePerHour:   booking.Studio.PricePerHour,
             ImageURL:       booking.Studio.ImageURL,
             Facilities:     booking.Studio.Facilities,
-            OperatingHours: booking.Studio.OperatingHours,
+            OperatingHours: mapOperatingHoursToDTO(booking.Studio.OperatingHours),
         }
     }
 
@@ -402,12 +638,8 @@ func (s *bookingService) mapBookingToDTOWithRelations(booking *database.Booking)
 
     // Include user info (for admin view)
     if booking.User != nil {
-        data.User = &dto.UserData{
-            ID:    booking.User.ID,
-            Name:  booking.User.Name,
-            Email: booking.User.Email,
-            Role:  booking.User.Role,
-        }
+        userData := mapUserToDTO(booking.User)
+        data.User = &userData
     }
 
     return data
@@ -432,3 +664,16 @@ func formatRupiah(amount int) string {
 
     return result
 }
+
+// formatDuration - "1 hour", "2 hours" or "90 minutes"
+func formatDuration(d time.Duration) string {
+    minutes := int(d / time.Minute)
+    switch {
+    case minutes%60 != 0:
+        return fmt.Sprintf("%d minutes", minutes)
+    case minutes == 60:
+        return "1 hour"
+    default:
+        return fmt.Sprintf("%d hours", minutes/60)
+    }
+}
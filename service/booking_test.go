@@ -0,0 +1,493 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/notify"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"github.com/RaFYWStud/BackendBookingStudio/repository"
+	"gorm.io/gorm"
+)
+
+// alwaysAvailable hides bookings from the availability check, leaving the exclusion constraint
+// as the only thing between two bookings of one slot.
+type alwaysAvailable struct {
+	contract.StudioRepository
+}
+
+func (alwaysAvailable) IsStudioAvailable(context.Context, int, time.Time, time.Time, time.Time) (bool, error) {
+	return true, nil
+}
+
+// skipAvailabilityCheck is a UnitOfWork whose transactions use alwaysAvailable.
+type skipAvailabilityCheck struct {
+	contract.UnitOfWork
+}
+
+func (u skipAvailabilityCheck) WithTx(ctx context.Context, fn func(repos *contract.Repository) error) error {
+	return u.UnitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+		repos.Studio = alwaysAvailable{repos.Studio}
+		return fn(repos)
+	})
+}
+
+func newTestBookingService(repos *contract.Repository, unitOfWork contract.UnitOfWork) contract.BookingService {
+	notifiers := map[notify.Channel]notify.Notifier{notify.ChannelEmail: notify.NewFake(notify.ChannelEmail)}
+	notifications := ImplNotificationService(ImplEmailService(notifiers[notify.ChannelEmail]), notifiers)
+	return ImplBookingService(repos.Booking, repos.Studio, repos.Auth, unitOfWork, notifications)
+}
+
+func createTestUser(t *testing.T, db *gorm.DB, email string) *database.User {
+	t.Helper()
+
+	user := &database.User{Name: "Test Customer", Email: email, Password: "-", Role: "customer", IsActive: true}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func createTestStudio(t *testing.T, db *gorm.DB, hours database.WeeklyHours, bufferMinutes int) *database.Studio {
+	t.Helper()
+
+	studio := &database.Studio{
+		Name:           "Test Studio",
+		Location:       "Test Location",
+		PricePerHour:   100000,
+		OperatingHours: hours,
+		Timezone:       "Asia/Jakarta",
+		BufferMinutes:  bufferMinutes,
+		IsActive:       true,
+	}
+	if err := db.Create(studio).Error; err != nil {
+		t.Fatalf("create studio: %v", err)
+	}
+	return studio
+}
+
+func TestCreateBookingConcurrentSameSlot(t *testing.T) {
+	db := dbtest.Open(t)
+	repos := repository.New(db)
+
+	tests := []struct {
+		name       string
+		unitOfWork contract.UnitOfWork
+	}{
+		{"with availability check", repos.UnitOfWork},
+		// Every request passes the check, so only bookings_no_overlap can reject them
+		{"constraint only", skipAvailabilityCheck{repos.UnitOfWork}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const n = 8
+			studio := createTestStudio(t, db, database.DailyHours("00:00", "00:00"), 0)
+			svc := newTestBookingService(repos, tt.unitOfWork)
+
+			users := make([]*database.User, n)
+			for j := range users {
+				users[j] = createTestUser(t, db, fmt.Sprintf("concurrent-%d-%d@example.com", i, j))
+			}
+
+			req := dto.CreateBookingRequest{
+				StudioID:    studio.ID,
+				BookingDate: time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+				StartTime:   "10:00",
+				EndTime:     "12:00",
+			}
+
+			start := make(chan struct{})
+			results := make([]error, n)
+			var wg sync.WaitGroup
+			for j := 0; j < n; j++ {
+				wg.Add(1)
+				go func(j int) {
+					defer wg.Done()
+					<-start
+					_, results[j] = svc.CreateBooking(context.Background(), users[j].ID, req)
+				}(j)
+			}
+			close(start)
+			wg.Wait()
+
+			succeeded := 0
+			for j, err := range results {
+				if err == nil {
+					succeeded++
+					continue
+				}
+				var msgErr errs.MessageError
+				if !errors.As(err, &msgErr) || msgErr.Status() != http.StatusConflict {
+					t.Errorf("request %d: %v, want 409 Conflict", j, err)
+				}
+			}
+			if succeeded != 1 {
+				t.Fatalf("%d of %d concurrent bookings succeeded, want exactly 1", succeeded, n)
+			}
+
+			var stored int64
+			if err := db.Model(&database.Booking{}).Where("studio_id = ?", studio.ID).Count(&stored).Error; err != nil {
+				t.Fatalf("count bookings: %v", err)
+			}
+			if stored != 1 {
+				t.Fatalf("%d bookings stored for the slot, want 1", stored)
+			}
+		})
+	}
+}
+
+func TestCreateBookingConcurrentWithinBuffer(t *testing.T) {
+	db := dbtest.Open(t)
+	repos := repository.New(db)
+	svc := newTestBookingService(repos, repos.UnitOfWork)
+
+	// The slots don't overlap, so bookings_no_overlap lets both through; only the buffer keeps them apart
+	const n = 8
+	studio := createTestStudio(t, db, database.DailyHours("00:00", "00:00"), 30)
+	day := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	slots := []dto.CreateBookingRequest{
+		{StudioID: studio.ID, BookingDate: day, StartTime: "10:00", EndTime: "12:00"},
+		{StudioID: studio.ID, BookingDate: day, StartTime: "12:15", EndTime: "14:00"},
+	}
+
+	users := make([]*database.User, n)
+	for j := range users {
+		users[j] = createTestUser(t, db, fmt.Sprintf("buffer-%d@example.com", j))
+	}
+
+	start := make(chan struct{})
+	results := make([]error, n)
+	var wg sync.WaitGroup
+	for j := 0; j < n; j++ {
+		wg.Add(1)
+		go func(j int) {
+			defer wg.Done()
+			<-start
+			_, results[j] = svc.CreateBooking(context.Background(), users[j].ID, slots[j%len(slots)])
+		}(j)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for j, err := range results {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		var msgErr errs.MessageError
+		if !errors.As(err, &msgErr) || msgErr.Status() != http.StatusConflict {
+			t.Errorf("request %d: %v, want 409 Conflict", j, err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d of %d concurrent bookings within the buffer succeeded, want exactly 1", succeeded, n)
+	}
+}
+
+func TestCreateBookingDeadlineInStudioTimezone(t *testing.T) {
+	db := dbtest.Open(t)
+	repos := repository.New(db)
+
+	// Jayapura is UTC+9, so the server's local time would show a different hour
+	studio := createTestStudio(t, db, database.DailyHours("00:00", "00:00"), 0)
+	if err := db.Model(studio).Update("timezone", "Asia/Jayapura").Error; err != nil {
+		t.Fatalf("update timezone: %v", err)
+	}
+	user := createTestUser(t, db, "deadline@example.com")
+
+	svc := newTestBookingService(repos, repos.UnitOfWork)
+	resp, err := svc.CreateBooking(context.Background(), user.ID, dto.CreateBookingRequest{
+		StudioID:    studio.ID,
+		BookingDate: time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		StartTime:   "10:00",
+		EndTime:     "12:00",
+	})
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+
+	var booking database.Booking
+	if err := db.First(&booking, resp.Data.ID).Error; err != nil {
+		t.Fatalf("load booking: %v", err)
+	}
+	jayapura, err := time.LoadLocation("Asia/Jayapura")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	want := booking.PaymentDeadline.In(jayapura).Format("02 Jan 2006 15:04")
+	if !strings.Contains(resp.Message, want) {
+		t.Errorf("message %q does not show the deadline %s in the studio's timezone", resp.Message, want)
+	}
+	if got := booking.PaymentDeadline.In(jayapura).Format("2006-01-02 15:04:05"); resp.Data.PaymentDeadline != got {
+		t.Errorf("payment_deadline = %s, want %s", resp.Data.PaymentDeadline, got)
+	}
+}
+
+func TestCreateBookingInsideClosure(t *testing.T) {
+	db := dbtest.Open(t)
+	repos := repository.New(db)
+	svc := newTestBookingService(repos, repos.UnitOfWork)
+
+	studio := createTestStudio(t, db, database.DailyHours("00:00", "00:00"), 0)
+	user := createTestUser(t, db, "closure@example.com")
+	day := time.Now().AddDate(0, 0, 7)
+	closure := &database.StudioClosure{
+		StudioID:   &studio.ID,
+		Reason:     "Soundproofing repairs",
+		StartsAt:   time.Date(day.Year(), day.Month(), day.Day(), 11, 0, 0, 0, time.UTC),
+		EndsAt:     time.Date(day.Year(), day.Month(), day.Day(), 13, 0, 0, 0, time.UTC),
+		Recurrence: database.RecurrenceNone,
+	}
+	if err := db.Create(closure).Error; err != nil {
+		t.Fatalf("create closure: %v", err)
+	}
+
+	_, err := svc.CreateBooking(context.Background(), user.ID, dto.CreateBookingRequest{
+		StudioID:    studio.ID,
+		BookingDate: day.Format("2006-01-02"),
+		StartTime:   "10:00",
+		EndTime:     "12:00",
+	})
+	var msgErr errs.MessageError
+	if !errors.As(err, &msgErr) || msgErr.Status() != http.StatusConflict || !strings.Contains(msgErr.Message(), closure.Reason) {
+		t.Fatalf("CreateBooking = %v, want 409 naming the closure", err)
+	}
+
+	// Right after the closure the studio is bookable again
+	if _, err := svc.CreateBooking(context.Background(), user.ID, dto.CreateBookingRequest{
+		StudioID:    studio.ID,
+		BookingDate: day.Format("2006-01-02"),
+		StartTime:   "13:00",
+		EndTime:     "14:00",
+	}); err != nil {
+		t.Fatalf("CreateBooking after the closure: %v", err)
+	}
+}
+
+func TestPaymentDeadline(t *testing.T) {
+	dbtest.LoadConfig()
+	hold := config.Get().BookingPaymentHold
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	now := time.Date(2030, 1, 7, 8, 0, 0, 0, jakarta)
+	day := time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		date  time.Time
+		start string
+		want  time.Time
+	}{
+		{"session after the hold", day.AddDate(0, 0, 7), "10:00", now.Add(hold)},
+		{"session sooner than the hold", day, "10:00", time.Date(2030, 1, 7, 10, 0, 0, 0, jakarta)},
+		{"session already started", day, "07:00", now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := time.Parse("15:04", tt.start)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.start, err)
+			}
+			if got := paymentDeadline(now, tt.date, start, jakarta); !got.Equal(tt.want) {
+				t.Errorf("paymentDeadline = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateBookingSessionStarted(t *testing.T) {
+	db := dbtest.Open(t)
+	repos := repository.New(db)
+	svc := newTestBookingService(repos, repos.UnitOfWork)
+
+	studio := createTestStudio(t, db, database.DailyHours("00:00", "00:00"), 0)
+	user := createTestUser(t, db, "started@example.com")
+
+	// Started an hour ago by the studio's clock, on the studio's today
+	started := time.Now().In(studio.TimeLocation()).Add(-time.Hour)
+	_, err := svc.CreateBooking(context.Background(), user.ID, dto.CreateBookingRequest{
+		StudioID:    studio.ID,
+		BookingDate: started.Format("2006-01-02"),
+		StartTime:   started.Format("15:04"),
+		EndTime:     started.Add(2 * time.Hour).Format("15:04"),
+	})
+	var msgErr errs.MessageError
+	if !errors.As(err, &msgErr) || msgErr.Status() != http.StatusBadRequest {
+		t.Fatalf("CreateBooking = %v, want 400 for a session that has started", err)
+	}
+}
+
+func TestReopenBooking(t *testing.T) {
+	db := dbtest.Open(t)
+	repos := repository.New(db)
+	svc := newTestBookingService(repos, repos.UnitOfWork)
+	ctx := context.Background()
+
+	always := database.DailyHours("00:00", "00:00")
+	studio := createTestStudio(t, db, always, 30)
+	user := createTestUser(t, db, "reopen@example.com")
+	day := time.Now().AddDate(0, 0, 7)
+
+	createBooking := func(date time.Time, from, to string, status database.BookingStatus) *database.Booking {
+		t.Helper()
+
+		start, err := time.Parse("15:04", from)
+		if err != nil {
+			t.Fatalf("parse %q: %v", from, err)
+		}
+		end, err := time.Parse("15:04", to)
+		if err != nil {
+			t.Fatalf("parse %q: %v", to, err)
+		}
+		booking := &database.Booking{
+			UserID:        user.ID,
+			StudioID:      studio.ID,
+			BookingDate:   time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+			StartTime:     start,
+			EndTime:       end,
+			DurationHours: 2,
+			TotalPrice:    200000,
+			Status:        status,
+		}
+		if err := db.Create(booking).Error; err != nil {
+			t.Fatalf("create booking: %v", err)
+		}
+		return booking
+	}
+	reopen := func(booking *database.Booking) error {
+		_, err := svc.UpdateBookingStatus(ctx, booking.ID, dto.UpdateBookingStatusRequest{Status: string(database.BookingStatusPending)})
+		return err
+	}
+	wantStatus := func(t *testing.T, err error, status int, contains string) {
+		t.Helper()
+
+		var msgErr errs.MessageError
+		if !errors.As(err, &msgErr) || msgErr.Status() != status || !strings.Contains(msgErr.Message(), contains) {
+			t.Fatalf("reopen = %v, want %d mentioning %q", err, status, contains)
+		}
+	}
+
+	cancelled := createBooking(day, "10:00", "12:00", database.BookingStatusCancelled)
+
+	// Another booking within the studio's buffer
+	other := createBooking(day, "12:15", "13:00", database.BookingStatusPending)
+	wantStatus(t, reopen(cancelled), http.StatusConflict, "not available")
+	if err := db.Model(other).Update("status", database.BookingStatusCancelled).Error; err != nil {
+		t.Fatalf("cancel booking: %v", err)
+	}
+
+	// A closure
+	closure := &database.StudioClosure{
+		StudioID:   &studio.ID,
+		Reason:     "Flooded",
+		StartsAt:   time.Date(day.Year(), day.Month(), day.Day(), 11, 0, 0, 0, time.UTC),
+		EndsAt:     time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.UTC),
+		Recurrence: database.RecurrenceNone,
+	}
+	if err := db.Create(closure).Error; err != nil {
+		t.Fatalf("create closure: %v", err)
+	}
+	wantStatus(t, reopen(cancelled), http.StatusConflict, closure.Reason)
+	if err := db.Delete(closure).Error; err != nil {
+		t.Fatalf("delete closure: %v", err)
+	}
+
+	// Closed that day
+	otherDay := database.WeekdayKey((day.Weekday() + 1) % 7)
+	if err := db.Model(studio).Update("weekly_hours", database.WeeklyHours{otherDay: {Open: "09:00", Close: "22:00"}}).Error; err != nil {
+		t.Fatalf("update hours: %v", err)
+	}
+	wantStatus(t, reopen(cancelled), http.StatusBadRequest, "closed")
+	if err := db.Model(studio).Update("weekly_hours", always).Error; err != nil {
+		t.Fatalf("update hours: %v", err)
+	}
+
+	if err := reopen(cancelled); err != nil {
+		t.Fatalf("reopen a free slot: %v", err)
+	}
+	var reopened database.Booking
+	if err := db.First(&reopened, cancelled.ID).Error; err != nil {
+		t.Fatalf("load booking: %v", err)
+	}
+	if reopened.Status != database.BookingStatusPending || reopened.PaymentDeadline == nil || !reopened.PaymentDeadline.After(time.Now()) {
+		t.Errorf("reopened booking is %s with deadline %v, want pending with a deadline ahead", reopened.Status, reopened.PaymentDeadline)
+	}
+
+	// The session of yesterday has started
+	expired := createBooking(time.Now().AddDate(0, 0, -1), "10:00", "12:00", database.BookingStatusExpired)
+	wantStatus(t, reopen(expired), http.StatusBadRequest, "already started")
+}
+
+func TestCreateBookingConcurrentWithClosure(t *testing.T) {
+	db := dbtest.Open(t)
+	repos := repository.New(db)
+	bookings := newTestBookingService(repos, repos.UnitOfWork)
+	closures := ImplClosureService(repos.Closure, repos.Studio, repos.UnitOfWork)
+
+	studio := createTestStudio(t, db, database.DailyHours("00:00", "00:00"), 0)
+	user := createTestUser(t, db, "race@example.com")
+
+	// Whichever commits first, the booking is either refused or flagged by the closure
+	for i := 0; i < 10; i++ {
+		day := time.Now().AddDate(0, 0, 7+i).Format("2006-01-02")
+
+		var bookingErr, closureErr error
+		var closure *dto.ClosureResponse
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, bookingErr = bookings.CreateBooking(context.Background(), user.ID, dto.CreateBookingRequest{
+				StudioID: studio.ID, BookingDate: day, StartTime: "10:00", EndTime: "12:00",
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			closure, closureErr = closures.CreateClosure(context.Background(), dto.CreateClosureRequest{
+				StudioID: &studio.ID, Reason: "Power cut", StartsAt: day + " 11:00", EndsAt: day + " 13:00",
+			})
+		}()
+		close(start)
+		wg.Wait()
+
+		if closureErr != nil {
+			t.Fatalf("CreateClosure: %v", closureErr)
+		}
+		if bookingErr != nil {
+			var msgErr errs.MessageError
+			if !errors.As(bookingErr, &msgErr) || msgErr.Status() != http.StatusConflict {
+				t.Fatalf("CreateBooking = %v, want 409 Conflict", bookingErr)
+			}
+			continue
+		}
+
+		var booking database.Booking
+		if err := db.Where("studio_id = ? AND booking_date = ?", studio.ID, day).First(&booking).Error; err != nil {
+			t.Fatalf("load booking: %v", err)
+		}
+		if booking.ClosureID == nil || *booking.ClosureID != closure.Data.ID {
+			t.Fatalf("booking on %s made before the closure is not flagged by it", day)
+		}
+	}
+}
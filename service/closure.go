@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+// closureTimeLayout - Wall clock time of the studio, as used by starts_at and ends_at
+const closureTimeLayout = "2006-01-02 15:04"
+
+type closureService struct {
+    closureRepo contract.ClosureRepository
+    studioRepo  contract.StudioRepository
+    unitOfWork  contract.UnitOfWork
+}
+
+func ImplClosureService(
+    closureRepo contract.ClosureRepository,
+    studioRepo contract.StudioRepository,
+    unitOfWork contract.UnitOfWork,
+) contract.ClosureService {
+    return &closureService{
+        closureRepo: closureRepo,
+        studioRepo:  studioRepo,
+        unitOfWork:  unitOfWork,
+    }
+}
+
+// GetClosures - Admin list closures with filters and pagination
+func (s *closureService) GetClosures(ctx context.Context, filter dto.ClosureFilterRequest) (*dto.ClosureListResponse, error) {
+    // Set default pagination
+    if filter.Page < 1 {
+        filter.Page = 1
+    }
+    if filter.Limit < 1 {
+        filter.Limit = 10
+    }
+    if filter.Limit > 100 {
+        filter.Limit = 100 // Max limit
+    }
+
+    closures, total, err := s.closureRepo.FindAll(ctx, filter, time.Now())
+    if err != nil {
+        return nil, errs.InternalServerError("failed to fetch closures")
+    }
+
+    closureDTOs := make([]dto.ClosureData, len(closures))
+    for i, closure := range closures {
+        closureDTOs[i] = mapClosureToDTO(&closure)
+    }
+
+    totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
+
+    return &dto.ClosureListResponse{
+        Success: true,
+        Data:    closureDTOs,
+        Meta: dto.PaginationMeta{
+            CurrentPage: filter.Page,
+            PerPage:     filter.Limit,
+            Total:       total,
+            TotalPages:  totalPages,
+        },
+    }, nil
+}
+
+// GetClosure - Admin get one closure with the bookings it flagged
+func (s *closureService) GetClosure(ctx context.Context, id int) (*dto.ClosureResponse, error) {
+    data, err := s.closureDetail(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.ClosureResponse{
+        Success: true,
+        Data:    *data,
+    }, nil
+}
+
+// CreateClosure - Admin block a studio, or every studio, and flag the bookings already inside the closure
+func (s *closureService) CreateClosure(ctx context.Context, req dto.CreateClosureRequest) (*dto.ClosureResponse, error) {
+    if req.StudioID != nil {
+        if err := s.ensureStudioExists(ctx, *req.StudioID); err != nil {
+            return nil, err
+        }
+    }
+
+    closure := &database.StudioClosure{
+        StudioID:   req.StudioID,
+        Reason:     req.Reason,
+        Recurrence: req.Recurrence,
+    }
+    if closure.Recurrence == "" {
+        closure.Recurrence = database.RecurrenceNone
+    }
+    if err := setClosureTimes(closure, &req.StartsAt, &req.EndsAt, &req.RepeatUntil); err != nil {
+        return nil, err
+    }
+    if err := validateClosure(closure); err != nil {
+        return nil, err
+    }
+
+    var flagged []int
+    err := s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        // Bookings being made for the studio see the closure, or it sees them, see CreateBooking
+        if err := repos.Lock.LockStudioSchedule(ctx, closure.StudioID); err != nil {
+            return errs.InternalServerError("failed to lock studio schedule")
+        }
+        if err := repos.Closure.Create(ctx, closure); err != nil {
+            return errs.InternalServerError("failed to create closure")
+        }
+
+        var err error
+        flagged, err = repos.Closure.FlagBookings(ctx, closure.ID)
+        if err != nil {
+            return errs.InternalServerError("failed to check existing bookings")
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    data, err := s.closureDetail(ctx, closure.ID)
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.ClosureResponse{
+        Success: true,
+        Message: "Closure created successfully." + flaggedMessage(len(flagged)),
+        Data:    *data,
+    }, nil
+}
+
+// UpdateClosure - Admin change a closure; the bookings inside it are flagged again
+func (s *closureService) UpdateClosure(ctx context.Context, id int, req dto.UpdateClosureRequest) (*dto.ClosureResponse, error) {
+    closure, err := s.closureRepo.FindByID(ctx, id)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("closure not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch closure")
+    }
+
+    if req.Reason != nil {
+        closure.Reason = *req.Reason
+    }
+    if req.Recurrence != nil {
+        closure.Recurrence = *req.Recurrence
+        // A closure that no longer repeats has nothing to repeat until
+        if closure.Recurrence == database.RecurrenceNone && req.RepeatUntil == nil {
+            closure.RepeatUntil = nil
+        }
+    }
+    if err := setClosureTimes(closure, req.StartsAt, req.EndsAt, req.RepeatUntil); err != nil {
+        return nil, err
+    }
+    if err := validateClosure(closure); err != nil {
+        return nil, err
+    }
+
+    var flagged []int
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        if err := repos.Lock.LockStudioSchedule(ctx, closure.StudioID); err != nil {
+            return errs.InternalServerError("failed to lock studio schedule")
+        }
+        if err := repos.Closure.Update(ctx, closure); err != nil {
+            return errs.InternalServerError("failed to update closure")
+        }
+
+        var err error
+        flagged, err = repos.Closure.FlagBookings(ctx, closure.ID)
+        if err != nil {
+            return errs.InternalServerError("failed to check existing bookings")
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    data, err := s.closureDetail(ctx, closure.ID)
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.ClosureResponse{
+        Success: true,
+        Message: "Closure updated successfully." + flaggedMessage(len(flagged)),
+        Data:    *data,
+    }, nil
+}
+
+// DeleteClosure - Admin remove a closure; the bookings it flagged stay flagged only if another closure covers them
+func (s *closureService) DeleteClosure(ctx context.Context, id int) (*dto.DeleteClosureResponse, error) {
+    closure, err := s.closureRepo.FindByID(ctx, id)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("closure not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch closure")
+    }
+
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        if err := repos.Lock.LockStudioSchedule(ctx, closure.StudioID); err != nil {
+            return errs.InternalServerError("failed to lock studio schedule")
+        }
+        if err := repos.Closure.Delete(ctx, id); err != nil {
+            return errs.InternalServerError("failed to delete closure")
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.DeleteClosureResponse{
+        Success: true,
+        Message: fmt.Sprintf("Closure with ID %d has been deleted successfully", id),
+    }, nil
+}
+
+// ============= HELPER FUNCTIONS =============
+
+// closureDetail - Closure with the bookings it flagged
+func (s *closureService) closureDetail(ctx context.Context, id int) (*dto.ClosureData, error) {
+    closure, err := s.closureRepo.FindByID(ctx, id)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("closure not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch closure")
+    }
+
+    bookings, err := s.closureRepo.FindFlaggedBookings(ctx, id)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to fetch flagged bookings")
+    }
+
+    data := mapClosureToDTO(closure)
+    data.FlaggedBookings = make([]dto.FlaggedBookingData, len(bookings))
+    for i, booking := range bookings {
+        data.FlaggedBookings[i] = mapFlaggedBookingToDTO(&booking)
+    }
+    return &data, nil
+}
+
+func (s *closureService) ensureStudioExists(ctx context.Context, studioID int) error {
+    if _, err := s.studioRepo.FindByID(ctx, studioID); err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return errs.NotFound("studio not found")
+        }
+        return errs.InternalServerError("failed to verify studio")
+    }
+    return nil
+}
+
+// setClosureTimes - Parse the given times into closure; nil leaves a field unchanged and an empty
+// repeat_until repeats forever
+func setClosureTimes(closure *database.StudioClosure, startsAt, endsAt, repeatUntil *string) error {
+    if startsAt != nil {
+        t, err := time.Parse(closureTimeLayout, *startsAt)
+        if err != nil {
+            return errs.BadRequest("invalid starts_at format, use YYYY-MM-DD HH:MM")
+        }
+        closure.StartsAt = t
+    }
+    if endsAt != nil {
+        t, err := time.Parse(closureTimeLayout, *endsAt)
+        if err != nil {
+            return errs.BadRequest("invalid ends_at format, use YYYY-MM-DD HH:MM")
+        }
+        closure.EndsAt = t
+    }
+    if repeatUntil != nil {
+        closure.RepeatUntil = nil
+        if *repeatUntil != "" {
+            t, err := time.Parse("2006-01-02", *repeatUntil)
+            if err != nil {
+                return errs.BadRequest("invalid repeat_until format, use YYYY-MM-DD")
+            }
+            closure.RepeatUntil = &t
+        }
+    }
+    return nil
+}
+
+// validateClosure - Occurrences of a repeating closure must not overlap each other
+func validateClosure(closure *database.StudioClosure) error {
+    if !closure.EndsAt.After(closure.StartsAt) {
+        return errs.BadRequest("ends_at must be after starts_at")
+    }
+
+    length := closure.EndsAt.Sub(closure.StartsAt)
+    switch closure.Recurrence {
+    case database.RecurrenceWeekly:
+        if length > 7*24*time.Hour {
+            return errs.BadRequest("a weekly closure can last at most 7 days")
+        }
+    case database.RecurrenceYearly:
+        if closure.EndsAt.After(closure.StartsAt.AddDate(1, 0, 0)) {
+            return errs.BadRequest("a yearly closure can last at most a year")
+        }
+        if closure.StartsAt.Month() == time.February && closure.StartsAt.Day() == 29 {
+            return errs.BadRequest("a yearly closure can't start on 29 February")
+        }
+    }
+
+    if closure.RepeatUntil != nil {
+        if closure.Recurrence == database.RecurrenceNone {
+            return errs.BadRequest("repeat_until needs a weekly or yearly recurrence")
+        }
+        if closure.RepeatUntil.Before(closure.StartsAt.Truncate(24 * time.Hour)) {
+            return errs.BadRequest("repeat_until must not be before starts_at")
+        }
+    }
+    return nil
+}
+
+// flaggedMessage - Tell the admin how many existing bookings need a look
+func flaggedMessage(count int) string {
+    if count == 0 {
+        return ""
+    }
+    return fmt.Sprintf(" %d existing booking(s) fall inside it and were flagged for review.", count)
+}
+
+func mapClosureToDTO(closure *database.StudioClosure) dto.ClosureData {
+    data := dto.ClosureData{
+        ID:         closure.ID,
+        StudioID:   closure.StudioID,
+        Reason:     closure.Reason,
+        StartsAt:   closure.StartsAt.UTC().Format(closureTimeLayout),
+        EndsAt:     closure.EndsAt.UTC().Format(closureTimeLayout),
+        Recurrence: closure.Recurrence,
+        CreatedAt:  closure.CreatedAt.Format("2006-01-02 15:04:05"),
+        UpdatedAt:  closure.UpdatedAt.Format("2006-01-02 15:04:05"),
+    }
+
+    if closure.Studio != nil {
+        data.StudioName = closure.Studio.Name
+    }
+    if closure.RepeatUntil != nil {
+        data.RepeatUntil = closure.RepeatUntil.Format("2006-01-02")
+    }
+    return data
+}
+
+func mapFlaggedBookingToDTO(booking *database.Booking) dto.FlaggedBookingData {
+    data := dto.FlaggedBookingData{
+        ID:          booking.ID,
+        StudioID:    booking.StudioID,
+        BookingDate: booking.BookingDate.Format("2006-01-02"),
+        StartTime:   booking.StartTime.Format("15:04"),
+        EndTime:     booking.EndTime.Format("15:04"),
+        Status:      string(booking.Status),
+    }
+
+    if booking.Studio != nil {
+        data.StudioName = booking.Studio.Name
+    }
+    if booking.User != nil {
+        data.CustomerName = booking.User.Name
+        data.CustomerEmail = booking.User.Email
+    }
+    return data
+}
\ No newline at end of file
@@ -2,41 +2,38 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
-	"log"
-	"net/smtp"
 	"net/url"
 	"os"
 	"time"
 
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/notify"
 	"github.com/RaFYWStud/BackendBookingStudio/database"
 )
 
+// emailService - The email channel: renders the HTML templates and sends them through the email notifier
 type emailService struct {
-    smtpHost string
-    smtpPort string
-    from     string
-    password string
+    notifier notify.Notifier
     appName  string
     appURL   string
+    baseURL  string
 }
 
-func ImplEmailService() *emailService {
+func ImplEmailService(notifier notify.Notifier) *emailService {
     return &emailService{
-        smtpHost: getEnv("SMTP_HOST", "smtp.gmail.com"),
-        smtpPort: getEnv("SMTP_PORT", "587"),
-        from:     getEnv("SMTP_FROM", "noreply@studiobooking.com"),
-        password: getEnv("SMTP_PASSWORD", ""),
+        notifier: notifier,
         appName:  getEnv("APP_NAME", "Studio Booking System"),
         appURL:   getEnv("APP_URL", "http://localhost:3000"),
+        baseURL:  getEnv("BASE_URL", "http://localhost:8080"),
     }
 }
 
-// SendBookingCreated - Notify customer booking created (pending payment via WhatsApp)
-func (s *emailService) SendBookingCreated(booking *database.Booking) error {
+// ComposeBookingCreated - Email notifying the customer that the booking was created (pending payment via WhatsApp)
+func (s *emailService) ComposeBookingCreated(booking *database.Booking) (*database.OutboxMessage, error) {
     if booking.User == nil || booking.Studio == nil {
-        return fmt.Errorf("booking missing user or studio relation")
+        return nil, fmt.Errorf("booking missing user or studio relation")
     }
 
     subject := "Booking Created - Please Contact Admin for Payment"
@@ -91,16 +88,16 @@ func (s *emailService) SendBookingCreated(booking *database.Booking) error {
 
     body, err := s.renderTemplate("booking_created", data)
     if err != nil {
-        return err
+        return nil, err
     }
 
-    return s.sendEmail(booking.User.Email, subject, body)
+    return newOutboxMessage("booking_created", notify.ChannelEmail, booking.User.Email, subject, body, &booking.ID), nil
 }
 
-// SendBookingConfirmed - Notify customer booking confirmed by admin
-func (s *emailService) SendBookingConfirmed(booking *database.Booking) error {
+// ComposeBookingConfirmed - Email notifying the customer that admin confirmed the booking
+func (s *emailService) ComposeBookingConfirmed(booking *database.Booking) (*database.OutboxMessage, error) {
     if booking.User == nil || booking.Studio == nil {
-        return fmt.Errorf("booking missing user or studio relation")
+        return nil, fmt.Errorf("booking missing user or studio relation")
     }
 
     subject := "Booking Confirmed! ✅"
@@ -126,16 +123,16 @@ func (s *emailService) SendBookingConfirmed(booking *database.Booking) error {
 
     body, err := s.renderTemplate("booking_confirmed", data)
     if err != nil {
-        return err
+        return nil, err
     }
 
-    return s.sendEmail(booking.User.Email, subject, body)
+    return newOutboxMessage("booking_confirmed", notify.ChannelEmail, booking.User.Email, subject, body, &booking.ID), nil
 }
 
-// SendBookingCancelled - Notify customer booking cancelled
-func (s *emailService) SendBookingCancelled(booking *database.Booking, reason string) error {
+// ComposeBookingCancelled - Email notifying the customer that the booking was cancelled
+func (s *emailService) ComposeBookingCancelled(booking *database.Booking, reason string) (*database.OutboxMessage, error) {
     if booking.User == nil || booking.Studio == nil {
-        return fmt.Errorf("booking missing user or studio relation")
+        return nil, fmt.Errorf("booking missing user or studio relation")
     }
 
     subject := "Booking Cancelled"
@@ -159,48 +156,126 @@ func (s *emailService) SendBookingCancelled(booking *database.Booking, reason st
 
     body, err := s.renderTemplate("booking_cancelled", data)
     if err != nil {
-        return err
+        return nil, err
     }
 
-    return s.sendEmail(booking.User.Email, subject, body)
+    return newOutboxMessage("booking_cancelled", notify.ChannelEmail, booking.User.Email, subject, body, &booking.ID), nil
 }
 
-// sendEmail - Send email via SMTP
-func (s *emailService) sendEmail(to, subject, body string) error {
-    if s.smtpHost == "" || s.smtpPort == "" || s.from == "" {
-        log.Printf("⚠️  SMTP not configured, skipping email to %s", to)
-        return nil
+// ComposeBookingExpired - Email notifying the customer that an unpaid booking released its slot
+func (s *emailService) ComposeBookingExpired(booking *database.Booking) (*database.OutboxMessage, error) {
+    if booking.User == nil || booking.Studio == nil {
+        return nil, fmt.Errorf("booking missing user or studio relation")
     }
 
-    if s.password == "" {
-        log.Printf("⚠️  SMTP password not set, skipping email to %s", to)
-        return nil
+    subject := "Booking Expired - Payment Not Received"
+
+    data := map[string]interface{}{
+        "CustomerName": booking.User.Name,
+        "BookingID":    booking.ID,
+        "StudioName":   booking.Studio.Name,
+        "BookingDate":  booking.BookingDate.Format("Monday, 02 January 2006"),
+        "StartTime":    booking.StartTime.Format("15:04"),
+        "EndTime":      booking.EndTime.Format("15:04"),
+        "TotalPrice":   formatNumber(booking.TotalPrice),
+        "AppName":      s.appName,
+        "AppURL":       s.appURL,
+        "Year":         time.Now().Year(),
     }
 
-    auth := smtp.PlainAuth("", s.from, s.password, s.smtpHost)
+    body, err := s.renderTemplate("booking_expired", data)
+    if err != nil {
+        return nil, err
+    }
+
+    return newOutboxMessage("booking_expired", notify.ChannelEmail, booking.User.Email, subject, body, &booking.ID), nil
+}
 
-    headers := make(map[string]string)
-    headers["From"] = s.from
-    headers["To"] = to
-    headers["Subject"] = subject
-    headers["MIME-Version"] = "1.0"
-    headers["Content-Type"] = "text/html; charset=UTF-8"
+// SendPasswordReset - Send password reset link to user
+func (s *emailService) SendPasswordReset(ctx context.Context, user *database.User, token string, expiresIn time.Duration) error {
+    subject := "Reset Your Password"
+
+    resetLink := fmt.Sprintf("%s/reset-password?token=%s", s.appURL, url.QueryEscape(token))
+
+    data := map[string]interface{}{
+        "CustomerName":  user.Name,
+        "ResetLink":     resetLink,
+        "ExpiryMinutes": int(expiresIn.Minutes()),
+        "AppName":       s.appName,
+        "AppURL":        s.appURL,
+        "Year":          time.Now().Year(),
+    }
 
-    message := ""
-    for k, v := range headers {
-        message += fmt.Sprintf("%s: %s\r\n", k, v)
+    body, err := s.renderTemplate("password_reset", data)
+    if err != nil {
+        return err
     }
-    message += "\r\n" + body
 
-    addr := fmt.Sprintf("%s:%s", s.smtpHost, s.smtpPort)
-    err := smtp.SendMail(addr, auth, s.from, []string{to}, []byte(message))
+    return s.sendEmail(ctx, user.Email, subject, body)
+}
 
+// SendEmailVerification - Send email address verification link to user
+func (s *emailService) SendEmailVerification(ctx context.Context, user *database.User, token string, expiresIn time.Duration) error {
+    subject := "Verify Your Email Address"
+
+    // The link hits the API directly, so it is built from BASE_URL rather than APP_URL
+    verifyLink := fmt.Sprintf("%s/auth/verify-email?token=%s", s.baseURL, url.QueryEscape(token))
+
+    data := map[string]interface{}{
+        "CustomerName": user.Name,
+        "VerifyLink":   verifyLink,
+        "ExpiryHours":  int(expiresIn.Hours()),
+        "AppName":      s.appName,
+        "AppURL":       s.appURL,
+        "Year":         time.Now().Year(),
+    }
+
+    body, err := s.renderTemplate("email_verification", data)
+    if err != nil {
+        return err
+    }
+
+    return s.sendEmail(ctx, user.Email, subject, body)
+}
+
+// SendAccountLocked - Notify user that login was locked after repeated failed attempts
+func (s *emailService) SendAccountLocked(ctx context.Context, user *database.User, lockedUntil time.Time) error {
+    subject := "Your Account Has Been Temporarily Locked"
+
+    data := map[string]interface{}{
+        "CustomerName": user.Name,
+        "LockedUntil":  lockedUntil.Format("02 Jan 2006, 15:04 MST"),
+        "ResetLink":    fmt.Sprintf("%s/forgot-password", s.appURL),
+        "AppName":      s.appName,
+        "AppURL":       s.appURL,
+        "Year":         time.Now().Year(),
+    }
+
+    body, err := s.renderTemplate("account_locked", data)
     if err != nil {
-        return fmt.Errorf("failed to send email to %s: %w", to, err)
+        return err
+    }
+
+    return s.sendEmail(ctx, user.Email, subject, body)
+}
+
+// newOutboxMessage - Pending outbox message, due immediately
+func newOutboxMessage(kind string, channel notify.Channel, to, subject, body string, bookingID *int) *database.OutboxMessage {
+    return &database.OutboxMessage{
+        Kind:          kind,
+        Channel:       string(channel),
+        Recipient:     to,
+        Subject:       subject,
+        Body:          body,
+        BookingID:     bookingID,
+        Status:        database.OutboxStatusPending,
+        NextAttemptAt: time.Now(),
     }
+}
 
-    log.Printf("✅ Email sent to %s: %s", to, subject)
-    return nil
+// sendEmail - Send email right away, without the outbox
+func (s *emailService) sendEmail(ctx context.Context, to, subject, body string) error {
+    return s.notifier.Send(ctx, notify.Message{To: to, Subject: subject, Body: body})
 }
 
 // renderTemplate - Render HTML email template
@@ -488,6 +563,218 @@ func getEmailTemplate(name string) string {
         </div>
     </div>
 </body>
+</html>`,
+
+        "booking_expired": `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; background: #f4f4f4; }
+        .container { max-width: 600px; margin: 20px auto; background: white; border-radius: 10px; overflow: hidden; box-shadow: 0 2px 10px rgba(0,0,0,0.1); }
+        .header { background: linear-gradient(135deg, #f59e0b 0%, #d97706 100%); color: white; padding: 30px; text-align: center; }
+        .header h1 { margin: 0; font-size: 28px; }
+        .content { padding: 30px; }
+        .booking-card { background: #f8f9fa; border-left: 4px solid #f59e0b; padding: 20px; margin: 20px 0; border-radius: 5px; }
+        .detail-row { display: flex; justify-content: space-between; padding: 12px 0; border-bottom: 1px solid #e9ecef; }
+        .detail-row:last-child { border-bottom: none; }
+        .label { font-weight: 600; color: #495057; }
+        .value { color: #212529; }
+        .btn { display: inline-block; background: #667eea; color: white; padding: 14px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; font-weight: 600; }
+        .footer { background: #f8f9fa; padding: 20px; text-align: center; color: #6c757d; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>⌛ Booking Expired</h1>
+            <p style="margin: 10px 0 0 0; opacity: 0.9;">We did not receive your payment in time</p>
+        </div>
+        
+        <div class="content">
+            <p>Hi <strong>{{.CustomerName}}</strong>,</p>
+            <p>The payment deadline for your booking has passed, so the time slot has been released for other customers.</p>
+            
+            <div class="booking-card">
+                <h3 style="margin-top: 0; color: #d97706;">📋 Expired Booking</h3>
+                <div class="detail-row">
+                    <span class="label">Booking ID</span>
+                    <span class="value"><strong>#{{.BookingID}}</strong></span>
+                </div>
+                <div class="detail-row">
+                    <span class="label">Studio</span>
+                    <span class="value">{{.StudioName}}</span>
+                </div>
+                <div class="detail-row">
+                    <span class="label">Date</span>
+                    <span class="value">{{.BookingDate}}</span>
+                </div>
+                <div class="detail-row">
+                    <span class="label">Time</span>
+                    <span class="value">{{.StartTime}} - {{.EndTime}}</span>
+                </div>
+                <div class="detail-row">
+                    <span class="label">Total Price</span>
+                    <span class="value">Rp {{.TotalPrice}}</span>
+                </div>
+            </div>
+
+            <p>If you already paid, please contact the admin with your payment proof and we will restore your booking if the slot is still free.</p>
+
+            <center>
+                <a href="{{.AppURL}}/studios" class="btn">Book Again</a>
+            </center>
+        </div>
+        
+        <div class="footer">
+            <p>&copy; {{.Year}} {{.AppName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`,
+
+        "password_reset": `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; background: #f4f4f4; }
+        .container { max-width: 600px; margin: 20px auto; background: white; border-radius: 10px; overflow: hidden; box-shadow: 0 2px 10px rgba(0,0,0,0.1); }
+        .header { background: linear-gradient(135deg, #667eea 0%, #764ba2 100%); color: white; padding: 30px; text-align: center; }
+        .header h1 { margin: 0; font-size: 28px; }
+        .content { padding: 30px; }
+        .btn { display: inline-block; background: #667eea; color: white; padding: 14px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; font-weight: 600; }
+        .link-box { background: #f8f9fa; border-left: 4px solid #667eea; padding: 15px; margin: 20px 0; border-radius: 5px; word-break: break-all; font-size: 13px; }
+        .alert-box { background: #fff3cd; border-left: 4px solid #ffc107; padding: 15px; margin: 20px 0; border-radius: 5px; }
+        .footer { background: #f8f9fa; padding: 20px; text-align: center; color: #6c757d; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔑 Reset Password</h1>
+            <p style="margin: 10px 0 0 0; opacity: 0.9;">We received a request to reset your password</p>
+        </div>
+        
+        <div class="content">
+            <p>Hi <strong>{{.CustomerName}}</strong>,</p>
+            <p>Click the button below to choose a new password for your account.</p>
+
+            <center>
+                <a href="{{.ResetLink}}" class="btn">Reset Password</a>
+            </center>
+
+            <p>If the button doesn't work, copy and paste this link into your browser:</p>
+            <div class="link-box">{{.ResetLink}}</div>
+
+            <div class="alert-box">
+                <strong>⚠️ Important:</strong><br>
+                This link expires in <strong>{{.ExpiryMinutes}} minutes</strong> and can only be used once.
+                If you didn't request a password reset, you can safely ignore this email.
+            </div>
+        </div>
+        
+        <div class="footer">
+            <p>&copy; {{.Year}} {{.AppName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`,
+
+        "email_verification": `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; background: #f4f4f4; }
+        .container { max-width: 600px; margin: 20px auto; background: white; border-radius: 10px; overflow: hidden; box-shadow: 0 2px 10px rgba(0,0,0,0.1); }
+        .header { background: linear-gradient(135deg, #10b981 0%, #059669 100%); color: white; padding: 30px; text-align: center; }
+        .header h1 { margin: 0; font-size: 28px; }
+        .content { padding: 30px; }
+        .btn { display: inline-block; background: #10b981; color: white; padding: 14px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; font-weight: 600; }
+        .link-box { background: #f8f9fa; border-left: 4px solid #10b981; padding: 15px; margin: 20px 0; border-radius: 5px; word-break: break-all; font-size: 13px; }
+        .info-box { background: #dbeafe; border-left: 4px solid #3b82f6; padding: 15px; margin: 20px 0; border-radius: 5px; }
+        .footer { background: #f8f9fa; padding: 20px; text-align: center; color: #6c757d; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📧 Verify Your Email</h1>
+            <p style="margin: 10px 0 0 0; opacity: 0.9;">One more step to activate your account</p>
+        </div>
+        
+        <div class="content">
+            <p>Hi <strong>{{.CustomerName}}</strong>,</p>
+            <p>Thanks for signing up with <strong>{{.AppName}}</strong>! Please confirm that this is your email address.</p>
+
+            <center>
+                <a href="{{.VerifyLink}}" class="btn">Verify Email</a>
+            </center>
+
+            <p>If the button doesn't work, copy and paste this link into your browser:</p>
+            <div class="link-box">{{.VerifyLink}}</div>
+
+            <div class="info-box">
+                This link expires in <strong>{{.ExpiryHours}} hours</strong>.
+                If you didn't create an account, you can safely ignore this email.
+            </div>
+        </div>
+        
+        <div class="footer">
+            <p>&copy; {{.Year}} {{.AppName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>`,
+
+        "account_locked": `
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; background: #f4f4f4; }
+        .container { max-width: 600px; margin: 20px auto; background: white; border-radius: 10px; overflow: hidden; box-shadow: 0 2px 10px rgba(0,0,0,0.1); }
+        .header { background: linear-gradient(135deg, #ef4444 0%, #b91c1c 100%); color: white; padding: 30px; text-align: center; }
+        .header h1 { margin: 0; font-size: 28px; }
+        .content { padding: 30px; }
+        .btn { display: inline-block; background: #ef4444; color: white; padding: 14px 30px; text-decoration: none; border-radius: 5px; margin: 20px 0; font-weight: 600; }
+        .alert-box { background: #fff3cd; border-left: 4px solid #ffc107; padding: 15px; margin: 20px 0; border-radius: 5px; }
+        .footer { background: #f8f9fa; padding: 20px; text-align: center; color: #6c757d; font-size: 14px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🔒 Account Temporarily Locked</h1>
+            <p style="margin: 10px 0 0 0; opacity: 0.9;">Too many failed login attempts</p>
+        </div>
+        
+        <div class="content">
+            <p>Hi <strong>{{.CustomerName}}</strong>,</p>
+            <p>We noticed several failed attempts to log in to your account, so we have temporarily locked it to keep it safe.</p>
+
+            <div class="alert-box">
+                <strong>⏳ Locked until:</strong> {{.LockedUntil}}<br>
+                You can try logging in again after this time.
+            </div>
+
+            <p>If these attempts weren't you, we recommend resetting your password:</p>
+
+            <center>
+                <a href="{{.ResetLink}}" class="btn">Reset Password</a>
+            </center>
+        </div>
+        
+        <div class="footer">
+            <p>&copy; {{.Year}} {{.AppName}}. All rights reserved.</p>
+        </div>
+    </div>
+</body>
 </html>`,
     }
 
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"gorm.io/gorm"
+)
+
+// Error codes returned in dto.ErrorResponse when a login is throttled
+const (
+    codeLoginThrottled = "LOGIN_THROTTLED"
+    codeAccountLocked  = "ACCOUNT_LOCKED"
+    codeIPLocked       = "IP_LOCKED"
+)
+
+// checkLoginAllowed - Reject the attempt while the email or IP is locked,
+// or while the progressive delay after the last failure has not passed
+func (s *authService) checkLoginAllowed(ctx context.Context, email, clientIP string) error {
+    cfg := config.Get()
+    now := time.Now()
+
+    if clientIP != "" {
+        throttle, err := s.findLoginThrottle(ctx, database.LoginThrottleScopeIP, clientIP)
+        if err != nil {
+            return err
+        }
+        if throttle != nil && throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+            return errs.TooManyRequests("too many failed login attempts from this address, try again later", codeIPLocked, throttle.LockedUntil.Sub(now))
+        }
+    }
+
+    throttle, err := s.findLoginThrottle(ctx, database.LoginThrottleScopeEmail, email)
+    if err != nil || throttle == nil {
+        return err
+    }
+
+    if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+        return errs.TooManyRequests("account temporarily locked after too many failed login attempts", codeAccountLocked, throttle.LockedUntil.Sub(now))
+    }
+
+    windowStart := now.Add(-time.Duration(cfg.LoginAttemptWindow) * time.Second)
+    if throttle.FailedCount > 0 && throttle.LastFailedAt.After(windowStart) {
+        nextAttempt := throttle.LastFailedAt.Add(loginDelay(throttle.FailedCount))
+        if now.Before(nextAttempt) {
+            return errs.TooManyRequests("too many failed login attempts, please wait before trying again", codeLoginThrottled, nextAttempt.Sub(now))
+        }
+    }
+
+    return nil
+}
+
+// recordLoginFailure - Count a failed attempt against the email and IP and lock
+// whichever crossed its limit. Returns the lockout error if this attempt caused one.
+// user is nil when the email is not registered; unknown emails are throttled the
+// same way so responses don't reveal which addresses exist.
+func (s *authService) recordLoginFailure(ctx context.Context, email, clientIP string, user *database.User) error {
+    cfg := config.Get()
+    now := time.Now()
+    windowStart := now.Add(-time.Duration(cfg.LoginAttemptWindow) * time.Second)
+    lockout := time.Duration(cfg.LoginLockoutDuration) * time.Second
+
+    var lockErr error
+
+    if clientIP != "" {
+        throttle, err := s.loginThrottleRepo.RecordFailure(ctx, database.LoginThrottleScopeIP, clientIP, windowStart)
+        if err != nil {
+            log.Printf("⚠️  Failed to record login failure for IP %s: %v", clientIP, err)
+        } else if throttle.FailedCount >= cfg.LoginMaxAttemptsPerIP {
+            if _, err := s.loginThrottleRepo.Lock(ctx, throttle.ID, now.Add(lockout)); err != nil {
+                log.Printf("⚠️  Failed to lock IP %s: %v", clientIP, err)
+            } else {
+                lockErr = errs.TooManyRequests("too many failed login attempts from this address, try again later", codeIPLocked, lockout)
+            }
+        }
+    }
+
+    throttle, err := s.loginThrottleRepo.RecordFailure(ctx, database.LoginThrottleScopeEmail, email, windowStart)
+    if err != nil {
+        log.Printf("⚠️  Failed to record login failure for %s: %v", email, err)
+        return lockErr
+    }
+    if throttle.FailedCount < cfg.LoginMaxAttempts {
+        return lockErr
+    }
+
+    lockedUntil := now.Add(lockout)
+    locked, err := s.loginThrottleRepo.Lock(ctx, throttle.ID, lockedUntil)
+    if err != nil {
+        log.Printf("⚠️  Failed to lock login for %s: %v", email, err)
+        return lockErr
+    }
+
+    // Only the request that actually set the lock sends the notification
+    if locked && user != nil {
+        go func() {
+            if err := s.emailService.SendAccountLocked(context.WithoutCancel(ctx), user, lockedUntil); err != nil {
+                log.Printf("⚠️  Failed to send account locked email to %s: %v", user.Email, err)
+            }
+        }()
+    }
+
+    return errs.TooManyRequests("account temporarily locked after too many failed login attempts", codeAccountLocked, lockout)
+}
+
+// clearLoginFailures - Forget failed attempts for an email after a successful login
+func (s *authService) clearLoginFailures(ctx context.Context, email string) {
+    if err := s.loginThrottleRepo.Clear(ctx, database.LoginThrottleScopeEmail, email); err != nil {
+        log.Printf("⚠️  Failed to clear login failures for %s: %v", email, err)
+    }
+
+    // Housekeeping: drop counters that can no longer affect anyone
+    cfg := config.Get()
+    retention := time.Duration(max(cfg.LoginAttemptWindow, cfg.LoginLockoutDuration)) * time.Second
+    if err := s.loginThrottleRepo.DeleteStale(ctx, time.Now().Add(-retention)); err != nil {
+        log.Printf("⚠️  Failed to clean up login throttles: %v", err)
+    }
+}
+
+// findLoginThrottle - Find throttle state, nil if the subject has no failures on record
+func (s *authService) findLoginThrottle(ctx context.Context, scope, subject string) (*database.LoginThrottle, error) {
+    throttle, err := s.loginThrottleRepo.Find(ctx, scope, subject)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, nil
+        }
+        return nil, errs.InternalServerError("failed to authenticate user")
+    }
+    return throttle, nil
+}
+
+// loginDelay - Wait required after the given number of consecutive failures:
+// base, 2*base, 4*base ... capped at the configured max
+func loginDelay(failedCount int) time.Duration {
+    cfg := config.Get()
+    if failedCount < 1 || cfg.LoginDelayBase == 0 {
+        return 0
+    }
+
+    delay := time.Duration(cfg.LoginDelayBase) * time.Second
+    maxDelay := time.Duration(cfg.LoginDelayMax) * time.Second
+    for i := 1; i < failedCount && delay < maxDelay; i++ {
+        delay *= 2
+    }
+    if delay > maxDelay {
+        delay = maxDelay
+    }
+    return delay
+}
+
+// normalizeEmail - Canonical form of an email address used as throttle key
+func normalizeEmail(email string) string {
+    return strings.ToLower(strings.TrimSpace(email))
+}
\ No newline at end of file
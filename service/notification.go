@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/notify"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+)
+
+// notificationService - Composes booking notifications on the channel each customer prefers
+// and delivers queued messages through the notifier of their channel
+type notificationService struct {
+    emailService contract.EmailService
+    notifiers    map[notify.Channel]notify.Notifier
+    appName      string
+}
+
+func ImplNotificationService(emailService contract.EmailService, notifiers map[notify.Channel]notify.Notifier) *notificationService {
+    return &notificationService{
+        emailService: emailService,
+        notifiers:    notifiers,
+        appName:      getEnv("APP_NAME", "Studio Booking System"),
+    }
+}
+
+// newNotifiers - The notifier of every configured channel. Email is always available,
+// WhatsApp and SMS only once their credentials are set. The fake driver logs instead of sending.
+func newNotifiers(cfg *config.AppConfigurationMap) map[notify.Channel]notify.Notifier {
+    if cfg.NotifierDriver == config.NotifierDriverFake {
+        return map[notify.Channel]notify.Notifier{
+            notify.ChannelEmail:    notify.NewFake(notify.ChannelEmail),
+            notify.ChannelWhatsApp: notify.NewFake(notify.ChannelWhatsApp),
+            notify.ChannelSMS:      notify.NewFake(notify.ChannelSMS),
+        }
+    }
+
+    smtpNotifier := notify.NewSMTPNotifier(
+        getEnv("SMTP_HOST", "smtp.gmail.com"),
+        getEnv("SMTP_PORT", "587"),
+        getEnv("SMTP_FROM", "noreply@studiobooking.com"),
+        getEnv("SMTP_PASSWORD", ""),
+    )
+    if !smtpNotifier.Configured() {
+        log.Println("⚠️  SMTP_PASSWORD not set, every email will fail until it is (use NOTIFIER_DRIVER=fake for local development)")
+    }
+
+    notifiers := map[notify.Channel]notify.Notifier{
+        notify.ChannelEmail: smtpNotifier,
+    }
+    if cfg.WhatsAppPhoneNumberID != "" && cfg.WhatsAppAccessToken != "" {
+        notifiers[notify.ChannelWhatsApp] = notify.NewWhatsAppNotifier(cfg.WhatsAppAPIURL, cfg.WhatsAppPhoneNumberID, cfg.WhatsAppAccessToken)
+    }
+    if cfg.SMSGatewayURL != "" {
+        notifiers[notify.ChannelSMS] = notify.NewSMSNotifier(cfg.SMSGatewayURL, cfg.SMSGatewayToken, cfg.SMSSender)
+    }
+    return notifiers
+}
+
+// ComposeBookingCreated - Tell the customer the booking was created and is waiting for payment
+func (s *notificationService) ComposeBookingCreated(booking *database.Booking) (*database.OutboxMessage, error) {
+    channel := s.channelFor(booking.User)
+    if channel == notify.ChannelEmail {
+        return s.emailService.ComposeBookingCreated(booking)
+    }
+    if booking.User == nil || booking.Studio == nil {
+        return nil, fmt.Errorf("booking missing user or studio relation")
+    }
+
+    text := fmt.Sprintf(
+        "%s: booking #%d for %s is waiting for payment of %s. Please contact admin on WhatsApp %s to pay",
+        s.appName, booking.ID, describeSession(booking), formatCurrency(booking.TotalPrice),
+        getEnv("ADMIN_WHATSAPP_DISPLAY", "0895-7060-8111"),
+    )
+    if booking.PaymentDeadline != nil {
+        text += fmt.Sprintf(" before %s, otherwise it is cancelled automatically",
+            booking.PaymentDeadline.In(booking.Studio.TimeLocation()).Format("02 Jan 15:04"))
+    }
+
+    return s.textMessage("booking_created", channel, booking, text+"."), nil
+}
+
+// ComposeBookingConfirmed - Tell the customer admin confirmed the booking
+func (s *notificationService) ComposeBookingConfirmed(booking *database.Booking) (*database.OutboxMessage, error) {
+    channel := s.channelFor(booking.User)
+    if channel == notify.ChannelEmail {
+        return s.emailService.ComposeBookingConfirmed(booking)
+    }
+    if booking.User == nil || booking.Studio == nil {
+        return nil, fmt.Errorf("booking missing user or studio relation")
+    }
+
+    text := fmt.Sprintf("%s: booking #%d for %s is confirmed. See you at the studio!",
+        s.appName, booking.ID, describeSession(booking))
+
+    return s.textMessage("booking_confirmed", channel, booking, text), nil
+}
+
+// ComposeBookingCancelled - Tell the customer the booking was cancelled
+func (s *notificationService) ComposeBookingCancelled(booking *database.Booking, reason string) (*database.OutboxMessage, error) {
+    channel := s.channelFor(booking.User)
+    if channel == notify.ChannelEmail {
+        return s.emailService.ComposeBookingCancelled(booking, reason)
+    }
+    if booking.User == nil || booking.Studio == nil {
+        return nil, fmt.Errorf("booking missing user or studio relation")
+    }
+
+    text := fmt.Sprintf("%s: booking #%d for %s has been cancelled.",
+        s.appName, booking.ID, describeSession(booking))
+    if reason != "" {
+        text += " Reason: " + reason
+    }
+
+    return s.textMessage("booking_cancelled", channel, booking, text), nil
+}
+
+// ComposeBookingExpired - Tell the customer the booking expired because it was not paid in time
+func (s *notificationService) ComposeBookingExpired(booking *database.Booking) (*database.OutboxMessage, error) {
+    channel := s.channelFor(booking.User)
+    if channel == notify.ChannelEmail {
+        return s.emailService.ComposeBookingExpired(booking)
+    }
+    if booking.User == nil || booking.Studio == nil {
+        return nil, fmt.Errorf("booking missing user or studio relation")
+    }
+
+    text := fmt.Sprintf("%s: booking #%d for %s expired because payment was not received in time. The slot has been released.",
+        s.appName, booking.ID, describeSession(booking))
+
+    return s.textMessage("booking_expired", channel, booking, text), nil
+}
+
+// Deliver - Send a message from the outbox through the notifier of its channel
+func (s *notificationService) Deliver(ctx context.Context, msg *database.OutboxMessage) error {
+    channel := notify.Channel(msg.Channel)
+    if channel == "" {
+        channel = notify.ChannelEmail
+    }
+
+    notifier, ok := s.notifiers[channel]
+    if !ok {
+        return fmt.Errorf("notification channel %q is not configured", channel)
+    }
+
+    return notifier.Send(ctx, notify.Message{
+        To:      msg.Recipient,
+        Subject: msg.Subject,
+        Body:    msg.Body,
+    })
+}
+
+// ============= HELPER FUNCTIONS =============
+
+// channelFor - The channel the user asked for, or email when they have no phone number
+// or the channel is not configured on this server
+func (s *notificationService) channelFor(user *database.User) notify.Channel {
+    if user == nil || user.Phone == "" {
+        return notify.ChannelEmail
+    }
+
+    channel := notify.Channel(user.NotifyChannel)
+    if _, ok := s.notifiers[channel]; !ok {
+        return notify.ChannelEmail
+    }
+    return channel
+}
+
+// textMessage - Plain text message for the phone number of the booking's customer
+func (s *notificationService) textMessage(kind string, channel notify.Channel, booking *database.Booking, text string) *database.OutboxMessage {
+    return newOutboxMessage(kind, channel, booking.User.Phone, "", text, &booking.ID)
+}
+
+// describeSession - "Studio A on Mon 02 Jan 2006, 10:00-12:00"
+func describeSession(booking *database.Booking) string {
+    return fmt.Sprintf("%s on %s, %s-%s",
+        booking.Studio.Name,
+        booking.BookingDate.Format("Mon 02 Jan 2006"),
+        booking.StartTime.Format("15:04"),
+        booking.EndTime.Format("15:04"),
+    )
+}
\ No newline at end of file
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+const (
+    outboxBatchSize  = 50               // Messages delivered per dispatcher run
+    outboxRetryDelay = 30 * time.Second // Wait after the first failed attempt, doubled after every further one
+    outboxMaxDelay   = 6 * time.Hour
+)
+
+type outboxService struct {
+    outboxRepo contract.OutboxRepository
+    notifier   contract.NotificationService
+}
+
+func ImplOutboxService(outboxRepo contract.OutboxRepository, notificationService contract.NotificationService) contract.OutboxService {
+    return &outboxService{
+        outboxRepo: outboxRepo,
+        notifier:   notificationService,
+    }
+}
+
+// DispatchPending - Deliver due outbox messages. A failed message is retried with exponential backoff
+// and marked dead after OUTBOX_MAX_ATTEMPTS. Returns how many messages were sent.
+func (s *outboxService) DispatchPending(ctx context.Context) (int, error) {
+    messages, err := s.outboxRepo.FindDue(ctx, time.Now(), outboxBatchSize)
+    if err != nil {
+        return 0, err
+    }
+
+    sent := 0
+    for _, msg := range messages {
+        // Leave the rest for the next run when the job runs out of time
+        if err := ctx.Err(); err != nil {
+            return sent, err
+        }
+
+        if err := s.notifier.Deliver(ctx, &msg); err != nil {
+            if err := s.outboxRepo.MarkFailed(ctx, msg.ID, err.Error(), nextOutboxAttempt(msg.Attempts+1)); err != nil {
+                return sent, err
+            }
+            continue
+        }
+
+        if err := s.outboxRepo.MarkSent(ctx, msg.ID, time.Now()); err != nil {
+            return sent, err
+        }
+        sent++
+    }
+
+    return sent, nil
+}
+
+// GetMessages - Admin list outbox messages with filters and pagination
+func (s *outboxService) GetMessages(ctx context.Context, filter dto.OutboxFilterRequest) (*dto.OutboxListResponse, error) {
+    // Set default pagination
+    if filter.Page < 1 {
+        filter.Page = 1
+    }
+    if filter.Limit < 1 {
+        filter.Limit = 10
+    }
+    if filter.Limit > 100 {
+        filter.Limit = 100 // Max limit
+    }
+
+    messages, total, err := s.outboxRepo.FindAll(ctx, filter)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to fetch outbox messages")
+    }
+
+    messageDTOs := make([]dto.OutboxMessageData, len(messages))
+    for i, msg := range messages {
+        messageDTOs[i] = mapOutboxMessageToDTO(&msg)
+    }
+
+    totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
+
+    return &dto.OutboxListResponse{
+        Success: true,
+        Data:    messageDTOs,
+        Meta: dto.PaginationMeta{
+            CurrentPage: filter.Page,
+            PerPage:     filter.Limit,
+            Total:       total,
+            TotalPages:  totalPages,
+        },
+    }, nil
+}
+
+// GetMessage - Admin get one outbox message
+func (s *outboxService) GetMessage(ctx context.Context, id int) (*dto.OutboxMessageResponse, error) {
+    msg, err := s.findMessage(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.OutboxMessageResponse{
+        Success: true,
+        Data:    mapOutboxMessageToDTO(msg),
+    }, nil
+}
+
+// Resend - Admin queue a dead message for delivery again, with a fresh set of attempts
+func (s *outboxService) Resend(ctx context.Context, id int) (*dto.OutboxMessageResponse, error) {
+    msg, err := s.findMessage(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+
+    switch msg.Status {
+    case database.OutboxStatusSent:
+        return nil, errs.BadRequest("message has already been sent")
+    case database.OutboxStatusPending:
+        return nil, errs.BadRequest("message is already queued for delivery")
+    }
+
+    ok, err := s.outboxRepo.Requeue(ctx, id, time.Now())
+    if err != nil {
+        return nil, errs.InternalServerError("failed to queue message")
+    }
+    if !ok {
+        return nil, errs.Conflict("message status changed, please reload")
+    }
+
+    msg, err = s.findMessage(ctx, id)
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.OutboxMessageResponse{
+        Success: true,
+        Message: "Message queued for delivery",
+        Data:    mapOutboxMessageToDTO(msg),
+    }, nil
+}
+
+// ============= HELPER FUNCTIONS =============
+
+// findMessage - Find outbox message or return 404
+func (s *outboxService) findMessage(ctx context.Context, id int) (*database.OutboxMessage, error) {
+    msg, err := s.outboxRepo.FindByID(ctx, id)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("outbox message not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch outbox message")
+    }
+    return msg, nil
+}
+
+// nextOutboxAttempt - When to retry after the given number of failed attempts, nil once they are used up
+func nextOutboxAttempt(attempts int) *time.Time {
+    if attempts >= config.Get().OutboxMaxAttempts {
+        return nil
+    }
+
+    next := time.Now().Add(outboxBackoff(attempts))
+    return &next
+}
+
+// outboxBackoff - Wait after the given number of failed attempts: 30s, 1m, 2m, ... up to outboxMaxDelay
+func outboxBackoff(attempts int) time.Duration {
+    return min(outboxRetryDelay<<min(attempts-1, 16), outboxMaxDelay)
+}
+
+func mapOutboxMessageToDTO(msg *database.OutboxMessage) dto.OutboxMessageData {
+    data := dto.OutboxMessageData{
+        ID:        msg.ID,
+        Kind:      msg.Kind,
+        Channel:   msg.Channel,
+        Recipient: msg.Recipient,
+        Subject:   msg.Subject,
+        BookingID: msg.BookingID,
+        Status:    msg.Status,
+        Attempts:  msg.Attempts,
+        LastError: msg.LastError,
+        CreatedAt: msg.CreatedAt.Format("2006-01-02 15:04:05"),
+        UpdatedAt: msg.UpdatedAt.Format("2006-01-02 15:04:05"),
+    }
+
+    if msg.Status == database.OutboxStatusPending {
+        data.NextAttemptAt = msg.NextAttemptAt.Format("2006-01-02 15:04:05")
+    }
+    if msg.SentAt != nil {
+        data.SentAt = msg.SentAt.Format("2006-01-02 15:04:05")
+    }
+
+    return data
+}
\ No newline at end of file
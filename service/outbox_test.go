@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/notify"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+// memOutbox is an in-memory OutboxRepository with the same state transitions as the real one:
+// only pending messages can be sent or failed, and only dead ones requeued.
+type memOutbox struct {
+	mu         sync.Mutex
+	messages   map[int]*database.OutboxMessage
+	nextID     int
+	lastFilter dto.OutboxFilterRequest
+}
+
+func newMemOutbox() *memOutbox {
+	return &memOutbox{messages: map[int]*database.OutboxMessage{}}
+}
+
+func (r *memOutbox) Create(_ context.Context, msg *database.OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	msg.ID = r.nextID
+	stored := *msg
+	r.messages[msg.ID] = &stored
+	return nil
+}
+
+func (r *memOutbox) FindByID(_ context.Context, id int) (*database.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg, ok := r.messages[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *msg
+	return &found, nil
+}
+
+func (r *memOutbox) FindAll(_ context.Context, filter dto.OutboxFilterRequest) ([]database.OutboxMessage, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastFilter = filter
+	messages := make([]database.OutboxMessage, 0, len(r.messages))
+	for _, msg := range r.messages {
+		messages = append(messages, *msg)
+	}
+	return messages, int64(len(messages)), nil
+}
+
+func (r *memOutbox) FindDue(_ context.Context, now time.Time, limit int) ([]database.OutboxMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []database.OutboxMessage
+	for _, msg := range r.messages {
+		if msg.Status == database.OutboxStatusPending && !msg.NextAttemptAt.After(now) {
+			due = append(due, *msg)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (r *memOutbox) MarkSent(_ context.Context, id int, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if msg, ok := r.messages[id]; ok && msg.Status == database.OutboxStatusPending {
+		msg.Status = database.OutboxStatusSent
+		msg.Attempts++
+		msg.LastError = ""
+		msg.SentAt = &sentAt
+	}
+	return nil
+}
+
+func (r *memOutbox) MarkFailed(_ context.Context, id int, errMsg string, nextAttemptAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if msg, ok := r.messages[id]; ok && msg.Status == database.OutboxStatusPending {
+		msg.Attempts++
+		msg.LastError = errMsg
+		if nextAttemptAt != nil {
+			msg.NextAttemptAt = *nextAttemptAt
+		} else {
+			msg.Status = database.OutboxStatusDead
+		}
+	}
+	return nil
+}
+
+func (r *memOutbox) Requeue(_ context.Context, id int, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg, ok := r.messages[id]
+	if !ok || msg.Status != database.OutboxStatusDead {
+		return false, nil
+	}
+	msg.Status = database.OutboxStatusPending
+	msg.Attempts = 0
+	msg.NextAttemptAt = now
+	return true, nil
+}
+
+// makeDue moves every pending message's next attempt into the past, as if its backoff had passed.
+func (r *memOutbox) makeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range r.messages {
+		if msg.Status == database.OutboxStatusPending {
+			msg.NextAttemptAt = time.Now().Add(-time.Second)
+		}
+	}
+}
+
+func (r *memOutbox) get(t *testing.T, id int) database.OutboxMessage {
+	t.Helper()
+
+	msg, err := r.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("message %d: %v", id, err)
+	}
+	return *msg
+}
+
+// newTestOutbox returns an outbox service delivering through a Fake for every channel.
+func newTestOutbox(t *testing.T) (*outboxService, *memOutbox, map[notify.Channel]*notify.Fake) {
+	t.Helper()
+	dbtest.LoadConfig()
+
+	fakes := map[notify.Channel]*notify.Fake{}
+	notifiers := map[notify.Channel]notify.Notifier{}
+	for _, channel := range []notify.Channel{notify.ChannelEmail, notify.ChannelWhatsApp, notify.ChannelSMS} {
+		fakes[channel] = notify.NewFake(channel)
+		notifiers[channel] = fakes[channel]
+	}
+
+	repo := newMemOutbox()
+	notifications := ImplNotificationService(ImplEmailService(notifiers[notify.ChannelEmail]), notifiers)
+	return ImplOutboxService(repo, notifications).(*outboxService), repo, fakes
+}
+
+func queueTestMessage(t *testing.T, repo *memOutbox, channel notify.Channel, to string) int {
+	t.Helper()
+
+	msg := newOutboxMessage("booking_created", channel, to, "Subject", "Body", nil)
+	if err := repo.Create(context.Background(), msg); err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+	return msg.ID
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour}, // 512 minutes, capped
+		{100, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestNextOutboxAttempt(t *testing.T) {
+	dbtest.LoadConfig()
+	maxAttempts := config.Get().OutboxMaxAttempts
+
+	for attempts := 1; attempts < maxAttempts; attempts++ {
+		before := time.Now()
+		next := nextOutboxAttempt(attempts)
+		if next == nil {
+			t.Fatalf("nextOutboxAttempt(%d) = nil, want a retry (max %d attempts)", attempts, maxAttempts)
+		}
+		if delay := next.Sub(before); delay < outboxBackoff(attempts) || delay > outboxBackoff(attempts)+time.Second {
+			t.Errorf("nextOutboxAttempt(%d) is %v away, want %v", attempts, delay, outboxBackoff(attempts))
+		}
+	}
+
+	if next := nextOutboxAttempt(maxAttempts); next != nil {
+		t.Errorf("nextOutboxAttempt(%d) = %v, want nil once the attempts are used up", maxAttempts, next)
+	}
+}
+
+func TestDispatchPendingRetriesUntilDead(t *testing.T) {
+	svc, repo, fakes := newTestOutbox(t)
+	ctx := context.Background()
+	maxAttempts := config.Get().OutboxMaxAttempts
+
+	id := queueTestMessage(t, repo, notify.ChannelEmail, "customer@example.com")
+	fakes[notify.ChannelEmail].FailWith(errors.New("smtp: connection refused"))
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		before := time.Now()
+		sent, err := svc.DispatchPending(ctx)
+		if err != nil || sent != 0 {
+			t.Fatalf("attempt %d: DispatchPending = %d, %v, want 0, nil", attempt, sent, err)
+		}
+
+		msg := repo.get(t, id)
+		if msg.Attempts != attempt || msg.LastError != "smtp: connection refused" {
+			t.Fatalf("attempt %d: attempts = %d, last error = %q", attempt, msg.Attempts, msg.LastError)
+		}
+		if attempt == maxAttempts {
+			if msg.Status != database.OutboxStatusDead {
+				t.Fatalf("status after %d attempts = %s, want dead", attempt, msg.Status)
+			}
+			break
+		}
+
+		if msg.Status != database.OutboxStatusPending {
+			t.Fatalf("attempt %d: status = %s, want pending", attempt, msg.Status)
+		}
+		if delay := msg.NextAttemptAt.Sub(before); delay < outboxBackoff(attempt) || delay > outboxBackoff(attempt)+time.Second {
+			t.Errorf("attempt %d: retried after %v, want %v", attempt, delay, outboxBackoff(attempt))
+		}
+
+		// Not due again until the backoff has passed
+		if sent, _ := svc.DispatchPending(ctx); sent != 0 || repo.get(t, id).Attempts != attempt {
+			t.Fatalf("attempt %d: message retried before its backoff passed", attempt)
+		}
+		repo.makeDue()
+	}
+
+	// A dead message is left alone even when the channel works again
+	fakes[notify.ChannelEmail].FailWith(nil)
+	if sent, err := svc.DispatchPending(ctx); err != nil || sent != 0 {
+		t.Fatalf("DispatchPending after dead = %d, %v, want 0, nil", sent, err)
+	}
+	if len(fakes[notify.ChannelEmail].Sent()) != 0 {
+		t.Errorf("dead message was delivered")
+	}
+}
+
+func TestDispatchPendingPartialFailure(t *testing.T) {
+	svc, repo, fakes := newTestOutbox(t)
+	ctx := context.Background()
+
+	first := queueTestMessage(t, repo, notify.ChannelEmail, "first@example.com")
+	failing := queueTestMessage(t, repo, notify.ChannelWhatsApp, "+628111111111")
+	last := queueTestMessage(t, repo, notify.ChannelEmail, "last@example.com")
+	fakes[notify.ChannelWhatsApp].FailWith(errors.New("whatsapp: rate limited"))
+
+	// One failure does not stop the rest of the batch
+	sent, err := svc.DispatchPending(ctx)
+	if err != nil || sent != 2 {
+		t.Fatalf("DispatchPending = %d, %v, want 2, nil", sent, err)
+	}
+	for _, id := range []int{first, last} {
+		msg := repo.get(t, id)
+		if msg.Status != database.OutboxStatusSent || msg.Attempts != 1 || msg.SentAt == nil {
+			t.Errorf("message %d: status = %s, attempts = %d, sent at = %v, want sent once", id, msg.Status, msg.Attempts, msg.SentAt)
+		}
+	}
+	if msg := repo.get(t, failing); msg.Status != database.OutboxStatusPending || msg.Attempts != 1 {
+		t.Fatalf("failed message: status = %s, attempts = %d, want pending after 1 attempt", msg.Status, msg.Attempts)
+	}
+
+	// The retry sends only the failed message and clears its error
+	fakes[notify.ChannelWhatsApp].FailWith(nil)
+	repo.makeDue()
+	sent, err = svc.DispatchPending(ctx)
+	if err != nil || sent != 1 {
+		t.Fatalf("retry: DispatchPending = %d, %v, want 1, nil", sent, err)
+	}
+
+	msg := repo.get(t, failing)
+	if msg.Status != database.OutboxStatusSent || msg.Attempts != 2 || msg.LastError != "" || msg.SentAt == nil {
+		t.Errorf("retried message: status = %s, attempts = %d, last error = %q, want sent after 2 attempts", msg.Status, msg.Attempts, msg.LastError)
+	}
+	if got := len(fakes[notify.ChannelEmail].Sent()); got != 2 {
+		t.Errorf("%d emails sent, want 2: sent messages must not be delivered again", got)
+	}
+	if got := fakes[notify.ChannelWhatsApp].Sent(); len(got) != 1 || got[0].To != "+628111111111" {
+		t.Errorf("WhatsApp messages sent = %+v, want one to +628111111111", got)
+	}
+}
+
+func TestResendRequeuesDeadMessage(t *testing.T) {
+	svc, repo, fakes := newTestOutbox(t)
+	ctx := context.Background()
+
+	id := queueTestMessage(t, repo, notify.ChannelEmail, "customer@example.com")
+	fakes[notify.ChannelEmail].FailWith(errors.New("smtp: connection refused"))
+	for i := 0; i < config.Get().OutboxMaxAttempts; i++ {
+		if _, err := svc.DispatchPending(ctx); err != nil {
+			t.Fatalf("DispatchPending: %v", err)
+		}
+		repo.makeDue()
+	}
+	if msg := repo.get(t, id); msg.Status != database.OutboxStatusDead {
+		t.Fatalf("status = %s, want dead", msg.Status)
+	}
+
+	resp, err := svc.Resend(ctx, id)
+	if err != nil {
+		t.Fatalf("Resend: %v", err)
+	}
+	if resp.Data.Status != database.OutboxStatusPending || resp.Data.Attempts != 0 {
+		t.Errorf("resent message: status = %s, attempts = %d, want pending with no attempts", resp.Data.Status, resp.Data.Attempts)
+	}
+
+	// Queued again, so it cannot be resent twice
+	var msgErr errs.MessageError
+	if _, err := svc.Resend(ctx, id); !errors.As(err, &msgErr) || msgErr.Status() != http.StatusBadRequest {
+		t.Errorf("Resend of a pending message = %v, want 400", err)
+	}
+
+	fakes[notify.ChannelEmail].FailWith(nil)
+	if sent, err := svc.DispatchPending(ctx); err != nil || sent != 1 {
+		t.Fatalf("DispatchPending after resend = %d, %v, want 1, nil", sent, err)
+	}
+	if _, err := svc.Resend(ctx, id); !errors.As(err, &msgErr) || msgErr.Status() != http.StatusBadRequest {
+		t.Errorf("Resend of a sent message = %v, want 400", err)
+	}
+	if _, err := svc.Resend(ctx, id+1); !errors.As(err, &msgErr) || msgErr.Status() != http.StatusNotFound {
+		t.Errorf("Resend of a missing message = %v, want 404", err)
+	}
+}
+
+func TestGetMessagesClampsLimit(t *testing.T) {
+	svc, repo, _ := newTestOutbox(t)
+
+	resp, err := svc.GetMessages(context.Background(), dto.OutboxFilterRequest{Page: 1, Limit: 1000})
+	if err != nil {
+		t.Fatalf("GetMessages: %v", err)
+	}
+	if resp.Meta.PerPage != 100 || repo.lastFilter.Limit != 100 {
+		t.Errorf("limit = %d (repository got %d), want 100", resp.Meta.PerPage, repo.lastFilter.Limit)
+	}
+}
+
+func TestDispatchPendingRoutesByNotifyChannel(t *testing.T) {
+	dbtest.LoadConfig()
+	ctx := context.Background()
+
+	// SMS is not configured on this server
+	fakes := map[notify.Channel]*notify.Fake{
+		notify.ChannelEmail:    notify.NewFake(notify.ChannelEmail),
+		notify.ChannelWhatsApp: notify.NewFake(notify.ChannelWhatsApp),
+	}
+	notifiers := map[notify.Channel]notify.Notifier{}
+	for channel, fake := range fakes {
+		notifiers[channel] = fake
+	}
+	notifications := ImplNotificationService(ImplEmailService(notifiers[notify.ChannelEmail]), notifiers)
+	repo := newMemOutbox()
+	svc := ImplOutboxService(repo, notifications)
+
+	tests := []struct {
+		name    string
+		user    database.User
+		channel notify.Channel
+		to      string
+	}{
+		{"email", database.User{Email: "email@example.com", NotifyChannel: "email", Phone: "+628111111111"}, notify.ChannelEmail, "email@example.com"},
+		{"whatsapp", database.User{Email: "wa@example.com", NotifyChannel: "whatsapp", Phone: "+628122222222"}, notify.ChannelWhatsApp, "+628122222222"},
+		{"whatsapp without phone", database.User{Email: "nophone@example.com", NotifyChannel: "whatsapp"}, notify.ChannelEmail, "nophone@example.com"},
+		{"channel not configured", database.User{Email: "sms@example.com", NotifyChannel: "sms", Phone: "+628133333333"}, notify.ChannelEmail, "sms@example.com"},
+	}
+
+	studio := &database.Studio{Name: "Studio A", Timezone: "Asia/Jakarta"}
+	for i, tt := range tests {
+		user := tt.user
+		user.Name = tt.name
+		booking := &database.Booking{
+			ID:          i + 1,
+			User:        &user,
+			Studio:      studio,
+			BookingDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+			StartTime:   time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+			EndTime:     time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC),
+			TotalPrice:  200000,
+		}
+
+		msg, err := notifications.ComposeBookingConfirmed(booking)
+		if err != nil {
+			t.Fatalf("%s: compose: %v", tt.name, err)
+		}
+		if err := repo.Create(ctx, msg); err != nil {
+			t.Fatalf("%s: queue: %v", tt.name, err)
+		}
+	}
+
+	sent, err := svc.DispatchPending(ctx)
+	if err != nil || sent != len(tests) {
+		t.Fatalf("DispatchPending = %d, %v, want %d, nil", sent, err, len(tests))
+	}
+
+	received := map[string]notify.Channel{}
+	for channel, fake := range fakes {
+		for _, msg := range fake.Sent() {
+			received[msg.To] = channel
+		}
+	}
+	for _, tt := range tests {
+		if got, ok := received[tt.to]; !ok || got != tt.channel {
+			t.Errorf("%s: message to %s went over %q, want %q", tt.name, tt.to, got, tt.channel)
+		}
+	}
+	if len(received) != len(tests) {
+		t.Errorf("%d recipients received messages, want %d", len(received), len(tests))
+	}
+}
@@ -1,14 +1,34 @@
 package service
 
-import "github.com/RaFYWStud/BackendBookingStudio/contract"
+import (
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/notify"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+)
 
 func New(repo *contract.Repository) *contract.Service {
-    emailService := ImplEmailService()
+    notifiers := newNotifiers(config.Get())
+    emailService := ImplEmailService(notifiers[notify.ChannelEmail])
+    notificationService := ImplNotificationService(emailService, notifiers)
+    authService := ImplAuthService(
+        repo.Auth,
+        repo.RefreshToken,
+        repo.RevokedToken,
+        repo.PasswordReset,
+        repo.LoginThrottle,
+        repo.TwoFactor,
+        repo.UnitOfWork,
+        emailService,
+    )
     
     return &contract.Service{
-        Auth:          ImplAuthService(repo.Auth),
-        Studio:        ImplStudioService(repo.Studio),
-        Booking:       ImplBookingService(repo.Booking, repo.Studio, emailService),
+        Auth:          authService,
+        Studio:        ImplStudioService(repo.Studio, repo.Closure),
+        Booking:       ImplBookingService(repo.Booking, repo.Studio, repo.Auth, repo.UnitOfWork, notificationService),
         Email:         emailService,
+        User:          ImplUserService(repo.User, repo.LoginThrottle, repo.UnitOfWork),
+        Outbox:        ImplOutboxService(repo.Outbox, notificationService),
+        Notification:  notificationService,
+        Closure:       ImplClosureService(repo.Closure, repo.Studio, repo.UnitOfWork),
     }
 }
\ No newline at end of file
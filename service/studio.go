@@ -1,10 +1,12 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
 
+	"github.com/RaFYWStud/BackendBookingStudio/config"
 	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
 	"github.com/RaFYWStud/BackendBookingStudio/contract"
 	"github.com/RaFYWStud/BackendBookingStudio/database"
@@ -12,16 +14,23 @@ import (
 	"gorm.io/gorm"
 )
 
+// calendarMaxDays - Longest range the availability calendar covers at once
+const calendarMaxDays = 62
+
+// lastTimezone - The timezone furthest behind UTC; a wall clock time that has passed there has passed everywhere
+var lastTimezone = time.FixedZone("UTC-12", -12*60*60)
+
 type studioService struct {
-    studioRepo contract.StudioRepository
+    studioRepo  contract.StudioRepository
+    closureRepo contract.ClosureRepository
 }
 
-func ImplStudioService(studioRepo contract.StudioRepository) contract.StudioService {
-    return &studioService{studioRepo: studioRepo}
+func ImplStudioService(studioRepo contract.StudioRepository, closureRepo contract.ClosureRepository) contract.StudioService {
+    return &studioService{studioRepo: studioRepo, closureRepo: closureRepo}
 }
 
 // GetAllStudios - Get list of studios with filters and pagination
-func (s *studioService) GetAllStudios(filter dto.StudioFilterRequest) (*dto.StudioListResponse, error) {
+func (s *studioService) GetAllStudios(ctx context.Context, filter dto.StudioFilterRequest) (*dto.StudioListResponse, error) {
     // Set default pagination values
     if filter.Page <= 0 {
         filter.Page = 1
@@ -33,7 +42,45 @@ func (s *studioService) GetAllStudios(filter dto.StudioFilterRequest) (*dto.Stud
         filter.Limit = 100 // Max limit
     }
 
-    studios, total, err := s.studioRepo.FindAll(filter)
+    // Only studios free for the whole session, when one is given
+    var freeDuring *database.SessionRange
+    if filter.Date != "" || filter.StartTime != "" || filter.EndTime != "" {
+        if filter.Date == "" || filter.StartTime == "" || filter.EndTime == "" {
+            return nil, errs.BadRequest("date, start_time and end_time must be sent together")
+        }
+
+        date, err := time.Parse("2006-01-02", filter.Date)
+        if err != nil {
+            return nil, errs.BadRequest("invalid date format, use YYYY-MM-DD")
+        }
+        startTime, err := time.Parse("15:04", filter.StartTime)
+        if err != nil {
+            return nil, errs.BadRequest("invalid start_time format, use HH:MM")
+        }
+        endTime, err := time.Parse("15:04", filter.EndTime)
+        if err != nil {
+            return nil, errs.BadRequest("invalid end_time format, use HH:MM")
+        }
+        if endTime.Equal(startTime) {
+            return nil, errs.BadRequest("end_time must differ from start_time")
+        }
+
+        // Same rules as CreateBooking, so no studio is listed as free for a session that can't be booked
+        startMinute, endMinute := database.SessionMinutes(startTime, endTime)
+        if minDuration := config.Get().BookingMinDuration; time.Duration(endMinute-startMinute)*time.Minute < minDuration {
+            return nil, errs.BadRequest("minimum booking duration is " + formatDuration(minDuration))
+        }
+        // Started even where the clock is furthest behind; studios where it only started in their
+        // own timezone are left out by the repository
+        if !sessionStartIn(date, startTime, lastTimezone).After(time.Now()) {
+            return nil, errs.BadRequest("cannot book studio in the past")
+        }
+
+        from, to := database.SessionBounds(date, startTime, endTime)
+        freeDuring = &database.SessionRange{From: from, To: to}
+    }
+
+    studios, total, err := s.studioRepo.FindAll(ctx, filter, freeDuring, time.Now())
     if err != nil {
         return nil, errs.InternalServerError("failed to fetch studios")
     }
@@ -49,7 +96,9 @@ func (s *studioService) GetAllStudios(filter dto.StudioFilterRequest) (*dto.Stud
             PricePerHour:   studio.PricePerHour,
             ImageURL:       studio.ImageURL,
             Facilities:     studio.Facilities,
-            OperatingHours: studio.OperatingHours,
+            OperatingHours: mapOperatingHoursToDTO(studio.OperatingHours),
+            Timezone:       studio.Timezone,
+            BufferMinutes:  studio.BufferMinutes,
             IsActive:       studio.IsActive,
             CreatedAt:      studio.CreatedAt.Format("2006-01-02 15:04:05"),
             UpdatedAt:      studio.UpdatedAt.Format("2006-01-02 15:04:05"),
@@ -72,8 +121,8 @@ func (s *studioService) GetAllStudios(filter dto.StudioFilterRequest) (*dto.Stud
 }
 
 // GetStudioByID - Get single studio detail
-func (s *studioService) GetStudioByID(studioID int) (*dto.StudioResponse, error) {
-    studio, err := s.studioRepo.FindByID(studioID)
+func (s *studioService) GetStudioByID(ctx context.Context, studioID int) (*dto.StudioResponse, error) {
+    studio, err := s.studioRepo.FindByID(ctx, studioID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("studio not found")
@@ -91,7 +140,9 @@ func (s *studioService) GetStudioByID(studioID int) (*dto.StudioResponse, error)
             PricePerHour:   studio.PricePerHour,
             ImageURL:       studio.ImageURL,
             Facilities:     studio.Facilities,
-            OperatingHours: studio.OperatingHours,
+            OperatingHours: mapOperatingHoursToDTO(studio.OperatingHours),
+            Timezone:       studio.Timezone,
+            BufferMinutes:  studio.BufferMinutes,
             IsActive:       studio.IsActive,
             CreatedAt:      studio.CreatedAt.Format("2006-01-02 15:04:05"),
             UpdatedAt:      studio.UpdatedAt.Format("2006-01-02 15:04:05"),
@@ -99,10 +150,10 @@ func (s *studioService) GetStudioByID(studioID int) (*dto.StudioResponse, error)
     }, nil
 }
 
-// CheckAvailability - Check studio availability for specific date and time
-func (s *studioService) CheckAvailability(studioID int, req dto.CheckAvailabilityRequest) (*dto.AvailabilityResponse, error) {
+// CheckAvailability - Free time of a studio on a date, and whether the requested time is free
+func (s *studioService) CheckAvailability(ctx context.Context, studioID int, req dto.CheckAvailabilityRequest) (*dto.AvailabilityResponse, error) {
     // Verify studio exists
-    _, err := s.studioRepo.FindByID(studioID)
+    studio, err := s.studioRepo.FindByID(ctx, studioID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("studio not found")
@@ -116,37 +167,82 @@ func (s *studioService) CheckAvailability(studioID int, req dto.CheckAvailabilit
         return nil, errs.BadRequest("invalid date format, use YYYY-MM-DD")
     }
 
-    // Parse times
-    startTime, err := time.Parse("15:04", req.StartTime)
-    if err != nil {
-        return nil, errs.BadRequest("invalid start_time format, use HH:MM")
+    // The requested time is optional, but start_time and end_time go together
+    if (req.StartTime == "") != (req.EndTime == "") {
+        return nil, errs.BadRequest("send both start_time and end_time, or neither")
     }
+    hasRange := req.StartTime != ""
 
-    endTime, err := time.Parse("15:04", req.EndTime)
-    if err != nil {
-        return nil, errs.BadRequest("invalid end_time format, use HH:MM")
-    }
+    var startTime, endTime time.Time
+    if hasRange {
+        startTime, err = time.Parse("15:04", req.StartTime)
+        if err != nil {
+            return nil, errs.BadRequest("invalid start_time format, use HH:MM")
+        }
 
-    // Validate time range
-    if endTime.Before(startTime) || endTime.Equal(startTime) {
-        return nil, errs.BadRequest("end_time must be after start_time")
-    }
+        endTime, err = time.Parse("15:04", req.EndTime)
+        if err != nil {
+            return nil, errs.BadRequest("invalid end_time format, use HH:MM")
+        }
 
-    // Get all bookings for this studio on this date
-    bookings, err := s.studioRepo.FindBookingsByDateRange(studioID, date)
-    if err != nil {
-        return nil, errs.InternalServerError("failed to check bookings")
+        // Validate time range; an end_time before start_time ends the next day
+        if endTime.Equal(startTime) {
+            return nil, errs.BadRequest("end_time must differ from start_time")
+        }
     }
 
-    // Check availability
-    isAvailable, err := s.studioRepo.IsStudioAvailable(studioID, date, startTime, endTime)
+    schedule, err := s.loadSchedule(ctx, studio, date, date)
     if err != nil {
-        return nil, errs.InternalServerError("failed to verify availability")
+        return nil, err
+    }
+    day := schedule.day(date)
+
+    minDuration := config.Get().BookingMinDuration
+    minLength := int(minDuration / time.Minute)
+    interval := int(config.Get().AvailabilitySlotInterval / time.Minute)
+    free := day.freePeriods(minLength)
+
+    // Start times are offered for the requested length, or the shortest session
+    length := minLength
+    var start, end int
+    if hasRange {
+        start, end = database.SessionMinutes(startTime, endTime)
+        length = max(end-start, minLength)
+    }
+    starts := startTimes(free, length, interval)
+
+    isAvailable := len(starts) > 0
+    message := "Studio has free time on the requested date"
+    if len(day.open) == 0 {
+        message = "Studio is closed on the requested date. Please check the operating hours."
+    } else if !isAvailable {
+        message = "Studio is fully booked on the requested date."
+    }
+
+    if hasRange {
+        isAvailable = false
+        for _, p := range free {
+            if p.Start <= start && end <= p.End {
+                isAvailable = true
+            }
+        }
+
+        message = "Studio is available for the requested time"
+        if !studio.OperatingHours.Covers(date, startTime, endTime) {
+            message = "Studio is closed at the requested time. Please check the operating hours."
+        } else if reason, closed := day.closedDuring(start, end); closed {
+            message = "Studio is closed at the requested time: " + reason
+        } else if end-start < minLength {
+            isAvailable = false
+            message = "Minimum booking duration is " + formatDuration(minDuration) + "."
+        } else if !isAvailable {
+            message = "Studio is not available for the requested time. Please check available slots."
+        }
     }
 
     // Build booked slots
-    bookedSlots := make([]dto.BookedSlot, len(bookings))
-    for i, booking := range bookings {
+    bookedSlots := make([]dto.BookedSlot, len(day.bookings))
+    for i, booking := range day.bookings {
         bookedSlots[i] = dto.BookedSlot{
             StartTime: booking.StartTime.Format("15:04"),
             EndTime:   booking.EndTime.Format("15:04"),
@@ -154,22 +250,16 @@ func (s *studioService) CheckAvailability(studioID int, req dto.CheckAvailabilit
         }
     }
 
-    // Build available slots (simplified - you can enhance this)
-    var availableSlots []dto.TimeSlot
-    if isAvailable {
-        availableSlots = []dto.TimeSlot{
-            {
-                StartTime: req.StartTime,
-                EndTime:   req.EndTime,
-            },
+    // Build closed slots
+    closedSlots := make([]dto.ClosedSlot, len(day.closures))
+    for i, closure := range day.closures {
+        closedSlots[i] = dto.ClosedSlot{
+            StartsAt: closure.StartsAt.Format(closureTimeLayout),
+            EndsAt:   closure.EndsAt.Format(closureTimeLayout),
+            Reason:   closure.Reason,
         }
     }
 
-    message := "Studio is available for the requested time"
-    if !isAvailable {
-        message = "Studio is not available for the requested time. Please check booked slots."
-    }
-
     return &dto.AvailabilityResponse{
         Success:   true,
         Available: isAvailable,
@@ -177,14 +267,120 @@ func (s *studioService) CheckAvailability(studioID int, req dto.CheckAvailabilit
         Data: &dto.AvailabilityData{
             StudioID:       studioID,
             Date:           req.Date,
-            AvailableSlots: availableSlots,
+            AvailableSlots: mapPeriodsToDTO(free),
+            StartTimes:     starts,
+            SlotInterval:   interval,
+            MinDuration:    minLength,
+            BufferMinutes:  studio.BufferMinutes,
             BookedSlots:    bookedSlots,
+            ClosedSlots:    closedSlots,
+        },
+    }, nil
+}
+
+// GetCalendar - Open hours, booked and free ranges of a studio day by day, with the occupancy.
+// Every day covers its own 24 hours, so sessions past midnight show on both days.
+func (s *studioService) GetCalendar(ctx context.Context, studioID int, req dto.CalendarRequest) (*dto.CalendarResponse, error) {
+    studio, err := s.studioRepo.FindByID(ctx, studioID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("studio not found")
+        }
+        return nil, errs.InternalServerError("failed to check studio")
+    }
+
+    // Default to the coming week in the studio's timezone
+    from := wallMidnight(time.Now().In(studio.TimeLocation()))
+    if req.From != "" {
+        from, err = time.Parse("2006-01-02", req.From)
+        if err != nil {
+            return nil, errs.BadRequest("invalid from format, use YYYY-MM-DD")
+        }
+    }
+
+    to := from.AddDate(0, 0, 6)
+    if req.To != "" {
+        to, err = time.Parse("2006-01-02", req.To)
+        if err != nil {
+            return nil, errs.BadRequest("invalid to format, use YYYY-MM-DD")
+        }
+    }
+
+    if to.Before(from) {
+        return nil, errs.BadRequest("to must not be before from")
+    }
+    if to.After(from.AddDate(0, 0, calendarMaxDays-1)) {
+        return nil, errs.BadRequest(fmt.Sprintf("the calendar covers at most %d days", calendarMaxDays))
+    }
+
+    schedule, err := s.loadSchedule(ctx, studio, from, to)
+    if err != nil {
+        return nil, err
+    }
+
+    minLength := int(config.Get().BookingMinDuration / time.Minute)
+
+    var days []dto.CalendarDay
+    var openTotal, bookedTotal int
+    for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+        day := schedule.day(date)
+
+        // Booked share of the time the studio could be booked that day
+        open, booked := day.openHours(), day.bookedHours()
+        openMinutes, bookedMinutes := day.usage()
+        openTotal += openMinutes
+        bookedTotal += bookedMinutes
+
+        closedSlots := make([]dto.ClosedSlot, len(day.closures))
+        for i, closure := range day.closures {
+            closedSlots[i] = dto.ClosedSlot{
+                StartsAt: closure.StartsAt.Format(closureTimeLayout),
+                EndsAt:   closure.EndsAt.Format(closureTimeLayout),
+                Reason:   closure.Reason,
+            }
+        }
+
+        days = append(days, dto.CalendarDay{
+            Date:        date.Format("2006-01-02"),
+            Weekday:     database.WeekdayKey(date.Weekday()),
+            OpenHours:   mapPeriodsToDTO(open),
+            BookedSlots: mapPeriodsToDTO(booked),
+            FreeSlots:   mapPeriodsToDTO(clipPeriods(day.freePeriods(minLength), 0, 24*60)),
+            ClosedSlots: closedSlots,
+            Occupancy:   occupancy(bookedMinutes, openMinutes),
+        })
+    }
+
+    return &dto.CalendarResponse{
+        Success: true,
+        Data: dto.CalendarData{
+            StudioID:      studio.ID,
+            Timezone:      studio.Timezone,
+            From:          from.Format("2006-01-02"),
+            To:            to.Format("2006-01-02"),
+            MinDuration:   minLength,
+            BufferMinutes: studio.BufferMinutes,
+            Occupancy:     occupancy(bookedTotal, openTotal),
+            Days:          days,
         },
     }, nil
 }
 
 // CreateStudio - Admin create new studio
-func (s *studioService) CreateStudio(req dto.CreateStudioRequest) (*dto.CreateStudioResponse, error) {
+func (s *studioService) CreateStudio(ctx context.Context, req dto.CreateStudioRequest) (*dto.CreateStudioResponse, error) {
+    timezone := req.Timezone
+    if timezone == "" {
+        timezone = database.DefaultStudioTimezone
+    }
+    if !isValidTimezone(timezone) {
+        return nil, errs.BadRequest("invalid timezone, use an IANA name such as Asia/Jakarta")
+    }
+
+    operatingHours, err := toWeeklyHours(req.OperatingHours)
+    if err != nil {
+        return nil, err
+    }
+
     studio := &database.Studio{
         Name:           req.Name,
         Description:    req.Description,
@@ -192,11 +388,13 @@ func (s *studioService) CreateStudio(req dto.CreateStudioRequest) (*dto.CreateSt
         PricePerHour:   req.PricePerHour,
         ImageURL:       req.ImageURL,
         Facilities:     database.StringArray(req.Facilities),
-        OperatingHours: req.OperatingHours,
+        OperatingHours: operatingHours,
+        Timezone:       timezone,
+        BufferMinutes:  req.BufferMinutes,
         IsActive:       true,
     }
 
-    if err := s.studioRepo.Create(studio); err != nil {
+    if err := s.studioRepo.Create(ctx, studio); err != nil {
         return nil, errs.InternalServerError("failed to create studio")
     }
 
@@ -211,7 +409,9 @@ func (s *studioService) CreateStudio(req dto.CreateStudioRequest) (*dto.CreateSt
             PricePerHour:   studio.PricePerHour,
             ImageURL:       studio.ImageURL,
             Facilities:     studio.Facilities,
-            OperatingHours: studio.OperatingHours,
+            OperatingHours: mapOperatingHoursToDTO(studio.OperatingHours),
+            Timezone:       studio.Timezone,
+            BufferMinutes:  studio.BufferMinutes,
             IsActive:       studio.IsActive,
             CreatedAt:      studio.CreatedAt.Format("2006-01-02 15:04:05"),
             UpdatedAt:      studio.UpdatedAt.Format("2006-01-02 15:04:05"),
@@ -220,9 +420,9 @@ func (s *studioService) CreateStudio(req dto.CreateStudioRequest) (*dto.CreateSt
 }
 
 // UpdateStudio - Admin update studio
-func (s *studioService) UpdateStudio(studioID int, req dto.UpdateStudioRequest) (*dto.UpdateStudioResponse, error) {
+func (s *studioService) UpdateStudio(ctx context.Context, studioID int, req dto.UpdateStudioRequest) (*dto.UpdateStudioResponse, error) {
     // Find existing studio
-    studio, err := s.studioRepo.FindByID(studioID)
+    studio, err := s.studioRepo.FindByID(ctx, studioID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("studio not found")
@@ -250,13 +450,26 @@ func (s *studioService) UpdateStudio(studioID int, req dto.UpdateStudioRequest)
     	studio.Facilities = database.StringArray(req.Facilities)
 	}
     if req.OperatingHours != nil {
-        studio.OperatingHours = *req.OperatingHours
+        operatingHours, err := toWeeklyHours(req.OperatingHours)
+        if err != nil {
+            return nil, err
+        }
+        studio.OperatingHours = operatingHours
+    }
+    if req.Timezone != nil {
+        if !isValidTimezone(*req.Timezone) {
+            return nil, errs.BadRequest("invalid timezone, use an IANA name such as Asia/Jakarta")
+        }
+        studio.Timezone = *req.Timezone
+    }
+    if req.BufferMinutes != nil {
+        studio.BufferMinutes = *req.BufferMinutes
     }
     if req.IsActive != nil {
         studio.IsActive = *req.IsActive
     }
 
-    if err := s.studioRepo.Update(studio); err != nil {
+    if err := s.studioRepo.Update(ctx, studio); err != nil {
         return nil, errs.InternalServerError("failed to update studio")
     }
 
@@ -271,7 +484,9 @@ func (s *studioService) UpdateStudio(studioID int, req dto.UpdateStudioRequest)
             PricePerHour:   studio.PricePerHour,
             ImageURL:       studio.ImageURL,
             Facilities:     studio.Facilities,
-            OperatingHours: studio.OperatingHours,
+            OperatingHours: mapOperatingHoursToDTO(studio.OperatingHours),
+            Timezone:       studio.Timezone,
+            BufferMinutes:  studio.BufferMinutes,
             IsActive:       studio.IsActive,
             CreatedAt:      studio.CreatedAt.Format("2006-01-02 15:04:05"),
             UpdatedAt:      studio.UpdatedAt.Format("2006-01-02 15:04:05"),
@@ -280,9 +495,9 @@ func (s *studioService) UpdateStudio(studioID int, req dto.UpdateStudioRequest)
 }
 
 // DeleteStudio - Admin delete/deactivate studio
-func (s *studioService) DeleteStudio(studioID int) (*dto.DeleteStudioResponse, error) {
+func (s *studioService) DeleteStudio(ctx context.Context, studioID int) (*dto.DeleteStudioResponse, error) {
     // Check if studio exists
-    _, err := s.studioRepo.FindByID(studioID)
+    _, err := s.studioRepo.FindByID(ctx, studioID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("studio not found")
@@ -291,7 +506,7 @@ func (s *studioService) DeleteStudio(studioID int) (*dto.DeleteStudioResponse, e
     }
 
     // Soft delete (you can also do hard delete or just set IsActive = false)
-    if err := s.studioRepo.Delete(studioID); err != nil {
+    if err := s.studioRepo.Delete(ctx, studioID); err != nil {
         return nil, errs.InternalServerError("failed to delete studio")
     }
 
@@ -301,9 +516,9 @@ func (s *studioService) DeleteStudio(studioID int) (*dto.DeleteStudioResponse, e
     }, nil
 }
 
-func (s *studioService) PatchStudio(studioID int, req dto.PatchStudioRequest) (*dto.PatchStudioResponse, error) {
+func (s *studioService) PatchStudio(ctx context.Context, studioID int, req dto.PatchStudioRequest) (*dto.PatchStudioResponse, error) {
     // Find existing studio
-    studio, err := s.studioRepo.FindByID(studioID)
+    studio, err := s.studioRepo.FindByID(ctx, studioID)
     if err != nil {
         if err == gorm.ErrRecordNotFound {
             return nil, errs.NotFound("studio not found")
@@ -331,13 +546,26 @@ func (s *studioService) PatchStudio(studioID int, req dto.PatchStudioRequest) (*
         studio.Facilities = database.StringArray(req.Facilities)
     }
     if req.OperatingHours != nil {
-        studio.OperatingHours = *req.OperatingHours
+        operatingHours, err := toWeeklyHours(req.OperatingHours)
+        if err != nil {
+            return nil, err
+        }
+        studio.OperatingHours = operatingHours
+    }
+    if req.Timezone != nil {
+        if !isValidTimezone(*req.Timezone) {
+            return nil, errs.BadRequest("invalid timezone, use an IANA name such as Asia/Jakarta")
+        }
+        studio.Timezone = *req.Timezone
+    }
+    if req.BufferMinutes != nil {
+        studio.BufferMinutes = *req.BufferMinutes
     }
     if req.IsActive != nil {
         studio.IsActive = *req.IsActive
     }
 
-    if err := s.studioRepo.Update(studio); err != nil {
+    if err := s.studioRepo.Update(ctx, studio); err != nil {
         return nil, errs.InternalServerError("failed to update studio")
     }
 
@@ -352,10 +580,249 @@ func (s *studioService) PatchStudio(studioID int, req dto.PatchStudioRequest) (*
             PricePerHour:   studio.PricePerHour,
             ImageURL:       studio.ImageURL,
             Facilities:     studio.Facilities,
-            OperatingHours: studio.OperatingHours,
+            OperatingHours: mapOperatingHoursToDTO(studio.OperatingHours),
+            Timezone:       studio.Timezone,
+            BufferMinutes:  studio.BufferMinutes,
             IsActive:       studio.IsActive,
             CreatedAt:      studio.CreatedAt.Format("2006-01-02 15:04:05"),
             UpdatedAt:      studio.UpdatedAt.Format("2006-01-02 15:04:05"),
         },
     }, nil
-}
\ No newline at end of file
+}
+
+// ============= HELPER FUNCTIONS =============
+
+// isValidTimezone - Timezone must be an IANA name (e.g. "Asia/Jakarta"); offsets like "+07:00" are rejected
+func isValidTimezone(name string) bool {
+    if name == "" || name == "Local" {
+        return false
+    }
+    _, err := time.LoadLocation(name)
+    return err == nil
+}
+
+// toWeeklyHours - Convert requested operating hours and check that they are valid
+func toWeeklyHours(hours *dto.OperatingHoursData) (database.WeeklyHours, error) {
+    weekly := database.WeeklyHours{}
+    for day, dayHours := range operatingHoursDays(hours) {
+        if *dayHours != nil {
+            weekly[database.WeekdayKey(day)] = database.DayHours{Open: (*dayHours).Open, Close: (*dayHours).Close}
+        }
+    }
+
+    if err := weekly.Validate(); err != nil {
+        return nil, errs.BadRequest("invalid operating_hours: " + err.Error())
+    }
+    return weekly, nil
+}
+
+// mapOperatingHoursToDTO - Every day of the week, null when the studio is closed
+func mapOperatingHoursToDTO(weekly database.WeeklyHours) dto.OperatingHoursData {
+    var data dto.OperatingHoursData
+    for day, dayHours := range operatingHoursDays(&data) {
+        if hours, ok := weekly.Hours(day); ok {
+            *dayHours = &dto.DayHoursData{Open: hours.Open, Close: hours.Close}
+        }
+    }
+    return data
+}
+
+// operatingHoursDays - The fields of hours by weekday
+func operatingHoursDays(hours *dto.OperatingHoursData) map[time.Weekday]**dto.DayHoursData {
+    return map[time.Weekday]**dto.DayHoursData{
+        time.Monday:    &hours.Monday,
+        time.Tuesday:   &hours.Tuesday,
+        time.Wednesday: &hours.Wednesday,
+        time.Thursday:  &hours.Thursday,
+        time.Friday:    &hours.Friday,
+        time.Saturday:  &hours.Saturday,
+        time.Sunday:    &hours.Sunday,
+    }
+}
+// studioDay - A studio's opening periods on one date and what keeps them busy, in minutes from midnight
+type studioDay struct {
+    date     time.Time
+    open     []database.OpenPeriod         // Sessions of the date start on it, but may run into the next day
+    busy     []database.OpenPeriod         // Bookings widened by the buffer time, closures and time already past
+    bookings []database.Booking            // Bookings of the date itself
+    sessions []database.OpenPeriod         // Bookings, those of the day before and after included
+    closures []database.ClosureOccurrence  // Closures overlapping the opening periods
+    closed   []database.OpenPeriod         // The closures in minutes
+}
+
+// studioSchedule - Bookings and closures of a studio over a range of dates, loaded at once
+type studioSchedule struct {
+    studio   *database.Studio
+    bookings map[string][]database.Booking // By booking date, including the day before and after the range
+    closures []database.ClosureOccurrence
+    now      time.Time                     // Wall clock time of the studio, stored as UTC
+}
+
+// loadSchedule - Bookings and closures of a studio from one date to another, inclusive. Bookings of the
+// day before and after count too, since sessions may run past midnight.
+func (s *studioService) loadSchedule(ctx context.Context, studio *database.Studio, from, to time.Time) (*studioSchedule, error) {
+    bookings, err := s.studioRepo.FindBookingsBetweenDates(ctx, studio.ID, from.AddDate(0, 0, -1), to.AddDate(0, 0, 1))
+    if err != nil {
+        return nil, errs.InternalServerError("failed to check bookings")
+    }
+
+    schedule := &studioSchedule{studio: studio, bookings: map[string][]database.Booking{}}
+    for _, booking := range bookings {
+        key := booking.BookingDate.Format("2006-01-02")
+        schedule.bookings[key] = append(schedule.bookings[key], booking)
+    }
+
+    // Opening periods of the last date may run until the end of the next day
+    schedule.closures, err = s.closureRepo.FindOccurrences(ctx, studio.ID, wallMidnight(from), wallMidnight(to).AddDate(0, 0, 2))
+    if err != nil {
+        return nil, errs.InternalServerError("failed to check studio closures")
+    }
+
+    now := time.Now().In(studio.TimeLocation())
+    schedule.now = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+
+    return schedule, nil
+}
+
+// day - Opening periods of the studio on date and what keeps them busy
+func (sch *studioSchedule) day(date time.Time) *studioDay {
+    day := &studioDay{date: date, bookings: sch.bookings[date.Format("2006-01-02")]}
+
+    for _, p := range sch.studio.OperatingHours.OpenPeriods(date) {
+        day.open = append(day.open, database.OpenPeriod{Start: max(p.Start, 0), End: p.End})
+    }
+    if len(day.open) == 0 {
+        return day
+    }
+    from, to := day.open[0].Start, day.open[len(day.open)-1].End
+
+    buffer := sch.studio.BufferMinutes
+    for offset := -1; offset <= 1; offset++ {
+        for _, booking := range sch.bookings[date.AddDate(0, 0, offset).Format("2006-01-02")] {
+            start, end := database.SessionMinutes(booking.StartTime, booking.EndTime)
+            session := database.OpenPeriod{Start: start + offset*24*60, End: end + offset*24*60}
+            day.sessions = append(day.sessions, session)
+            day.busy = append(day.busy, database.OpenPeriod{Start: session.Start - buffer, End: session.End + buffer})
+        }
+    }
+
+    midnight := wallMidnight(date)
+    for _, closure := range sch.closures {
+        closed := database.OpenPeriod{
+            Start: int(math.Floor(closure.StartsAt.Sub(midnight).Minutes())),
+            End:   int(math.Ceil(closure.EndsAt.Sub(midnight).Minutes())),
+        }
+        if closed.Start < to && from < closed.End {
+            day.closures = append(day.closures, closure)
+            day.closed = append(day.closed, closed)
+            day.busy = append(day.busy, closed)
+        }
+    }
+
+    // Time that already passed in the studio's timezone can't be booked
+    if past := sch.now.Sub(midnight); past > time.Duration(from)*time.Minute {
+        day.busy = append(day.busy, database.OpenPeriod{Start: from, End: int(math.Ceil(past.Minutes()))})
+    }
+
+    return day
+}
+
+// freePeriods - Free ranges at least minLength minutes long that start on the date
+func (d *studioDay) freePeriods(minLength int) []database.OpenPeriod {
+    var free []database.OpenPeriod
+    for _, p := range database.SubtractPeriods(d.open, d.busy) {
+        if p.Start < 24*60 && p.End-p.Start >= minLength {
+            free = append(free, p)
+        }
+    }
+    return free
+}
+
+// openHours - Opening periods within the date itself
+func (d *studioDay) openHours() []database.OpenPeriod {
+    return clipPeriods(d.open, 0, 24*60)
+}
+
+// bookedHours - Booked sessions within the date itself
+func (d *studioDay) bookedHours() []database.OpenPeriod {
+    return clipPeriods(d.sessions, 0, 24*60)
+}
+
+// usage - Minutes of the date the studio can be booked (open and not closed), and how many of them are booked
+func (d *studioDay) usage() (int, int) {
+    bookable := database.SubtractPeriods(d.openHours(), d.closed)
+    open := periodMinutes(bookable)
+    return open, open - periodMinutes(database.SubtractPeriods(bookable, d.bookedHours()))
+}
+
+// closedDuring - The reason of a closure overlapping start to end, if any
+func (d *studioDay) closedDuring(start, end int) (string, bool) {
+    for i, closed := range d.closed {
+        if closed.Start < end && start < closed.End {
+            return d.closures[i].Reason, true
+        }
+    }
+    return "", false
+}
+
+// startTimes - Start times on the date, multiples of interval minutes from midnight, at which a session
+// of length minutes fits in a free range
+func startTimes(free []database.OpenPeriod, length, interval int) []string {
+    times := []string{}
+    for _, p := range free {
+        first := (p.Start + interval - 1) / interval * interval
+        for t := first; t < 24*60 && t+length <= p.End; t += interval {
+            times = append(times, clockTime(t))
+        }
+    }
+    return times
+}
+
+// clipPeriods - The parts of periods between start and end
+func clipPeriods(periods []database.OpenPeriod, start, end int) []database.OpenPeriod {
+    var clipped []database.OpenPeriod
+    for _, p := range periods {
+        p.Start, p.End = max(p.Start, start), min(p.End, end)
+        if p.Start < p.End {
+            clipped = append(clipped, p)
+        }
+    }
+    return clipped
+}
+
+// periodMinutes - Total length of periods that don't overlap each other
+func periodMinutes(periods []database.OpenPeriod) int {
+    total := 0
+    for _, p := range periods {
+        total += p.End - p.Start
+    }
+    return total
+}
+
+// occupancy - Percentage of open minutes that are booked, to one decimal
+func occupancy(booked, open int) float64 {
+    if open == 0 {
+        return 0
+    }
+    return math.Round(float64(booked)*1000/float64(open)) / 10
+}
+
+// mapPeriodsToDTO - Periods as "HH:MM" ranges; 24:00 is written as 00:00
+func mapPeriodsToDTO(periods []database.OpenPeriod) []dto.TimeSlot {
+    slots := make([]dto.TimeSlot, len(periods))
+    for i, p := range periods {
+        slots[i] = dto.TimeSlot{StartTime: clockTime(p.Start), EndTime: clockTime(p.End)}
+    }
+    return slots
+}
+
+// wallMidnight - Start of date as a wall clock time stored as UTC, like closure times
+func wallMidnight(date time.Time) time.Time {
+    return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// clockTime - "HH:MM" of a minute from midnight; minutes past midnight wrap to the next day
+func clockTime(minute int) string {
+    minute = (minute%(24*60) + 24*60) % (24 * 60)
+    return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
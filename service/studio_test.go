@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/database/dbtest"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+)
+
+// testDay is a Monday far in the future, so no part of it has passed yet.
+var testDay = time.Date(2030, 1, 7, 0, 0, 0, 0, time.UTC)
+
+func formatPeriods(periods []database.OpenPeriod) string {
+	if len(periods) == 0 {
+		return "[]"
+	}
+	return fmt.Sprint(periods)
+}
+
+// periods builds opening periods from start and end pairs.
+func periods(bounds ...int) []database.OpenPeriod {
+	var result []database.OpenPeriod
+	for i := 0; i+1 < len(bounds); i += 2 {
+		result = append(result, database.OpenPeriod{Start: bounds[i], End: bounds[i+1]})
+	}
+	return result
+}
+
+func testBooking(t *testing.T, date time.Time, from, to string) database.Booking {
+	t.Helper()
+
+	start, err := time.Parse("15:04", from)
+	if err != nil {
+		t.Fatalf("parse %q: %v", from, err)
+	}
+	end, err := time.Parse("15:04", to)
+	if err != nil {
+		t.Fatalf("parse %q: %v", to, err)
+	}
+	return database.Booking{BookingDate: date, StartTime: start, EndTime: end}
+}
+
+// testSchedule is a studioSchedule with the given bookings, as loadSchedule would build it.
+func testSchedule(hours database.WeeklyHours, buffer int, now time.Time, bookings []database.Booking, closures []database.ClosureOccurrence) *studioSchedule {
+	schedule := &studioSchedule{
+		studio:   &database.Studio{OperatingHours: hours, BufferMinutes: buffer},
+		bookings: map[string][]database.Booking{},
+		closures: closures,
+		now:      now,
+	}
+	for _, booking := range bookings {
+		key := booking.BookingDate.Format("2006-01-02")
+		schedule.bookings[key] = append(schedule.bookings[key], booking)
+	}
+	return schedule
+}
+
+func TestFreePeriods(t *testing.T) {
+	past := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	daytime := database.DailyHours("09:00", "22:00")
+	always := database.DailyHours("00:00", "00:00")
+
+	tests := []struct {
+		name      string
+		hours     database.WeeklyHours
+		buffer    int
+		now       time.Time
+		bookings  []database.Booking
+		closures  []database.ClosureOccurrence
+		minLength int
+		want      []database.OpenPeriod
+	}{
+		{
+			name:  "nothing booked",
+			hours: daytime, now: past, minLength: 60,
+			want: periods(540, 1320),
+		},
+		{
+			name:  "booking widened by the buffer",
+			hours: daytime, buffer: 15, now: past, minLength: 60,
+			bookings: []database.Booking{testBooking(t, testDay, "12:00", "14:00")},
+			want:     periods(540, 705, 855, 1320),
+		},
+		{
+			name:  "gap shorter than the minimum",
+			hours: daytime, now: past, minLength: 90,
+			bookings: []database.Booking{testBooking(t, testDay, "10:00", "11:00")},
+			want:     periods(660, 1320),
+		},
+		{
+			name:  "overnight booking of the day before",
+			hours: always, buffer: 30, now: past, minLength: 60,
+			bookings: []database.Booking{testBooking(t, testDay.AddDate(0, 0, -1), "22:00", "02:00")},
+			want:     periods(150, 2880),
+		},
+		{
+			// The range after it starts the next day, so it belongs to that date
+			name:  "booking of the next day shortens the last range",
+			hours: always, now: past, minLength: 60,
+			bookings: []database.Booking{testBooking(t, testDay.AddDate(0, 0, 1), "01:00", "03:00")},
+			want:     periods(0, 1500),
+		},
+		{
+			name:  "overnight opening hours",
+			hours: database.DailyHours("20:00", "02:00"), now: past, minLength: 60,
+			want: periods(0, 120, 1200, 1560),
+		},
+		{
+			name:  "closure",
+			hours: daytime, now: past, minLength: 60,
+			closures: []database.ClosureOccurrence{{
+				Reason:   "Maintenance",
+				StartsAt: testDay.Add(15 * time.Hour),
+				EndsAt:   testDay.Add(16*time.Hour + 30*time.Minute),
+			}},
+			want: periods(540, 900, 990, 1320),
+		},
+		{
+			name:  "closure on another day",
+			hours: daytime, now: past, minLength: 60,
+			closures: []database.ClosureOccurrence{{
+				Reason:   "Holiday",
+				StartsAt: testDay.AddDate(0, 0, 1),
+				EndsAt:   testDay.AddDate(0, 0, 2),
+			}},
+			want: periods(540, 1320),
+		},
+		{
+			name:  "time already passed",
+			hours: daytime, now: testDay.Add(10*time.Hour + 20*time.Minute + 30*time.Second), minLength: 60,
+			want: periods(621, 1320),
+		},
+		{
+			name:  "closed day",
+			hours: database.WeeklyHours{"tuesday": {Open: "09:00", Close: "22:00"}}, now: past, minLength: 60,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := testSchedule(tt.hours, tt.buffer, tt.now, tt.bookings, tt.closures).day(testDay)
+			if got := day.freePeriods(tt.minLength); formatPeriods(got) != formatPeriods(tt.want) {
+				t.Errorf("freePeriods = %s, want %s", formatPeriods(got), formatPeriods(tt.want))
+			}
+		})
+	}
+}
+
+func TestClosedDuring(t *testing.T) {
+	closures := []database.ClosureOccurrence{{
+		Reason:   "Maintenance",
+		StartsAt: testDay.Add(15 * time.Hour),
+		EndsAt:   testDay.Add(16 * time.Hour),
+	}}
+	day := testSchedule(database.DailyHours("09:00", "22:00"), 0, testDay.AddDate(-30, 0, 0), nil, closures).day(testDay)
+
+	tests := []struct {
+		start, end int
+		closed     bool
+	}{
+		{840, 900, false}, // Ends as the closure starts
+		{840, 930, true},
+		{930, 945, true},
+		{960, 1020, false}, // Starts as the closure ends
+	}
+
+	for _, tt := range tests {
+		reason, closed := day.closedDuring(tt.start, tt.end)
+		if closed != tt.closed || (closed && reason != "Maintenance") {
+			t.Errorf("closedDuring(%d, %d) = %q, %v, want %v", tt.start, tt.end, reason, closed, tt.closed)
+		}
+	}
+}
+
+func TestStartTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		free     []database.OpenPeriod
+		length   int
+		interval int
+		want     string
+	}{
+		{"aligned range", periods(540, 720), 60, 30, "[09:00 09:30 10:00 10:30 11:00]"},
+		{"start rounded up to the interval", periods(545, 720), 60, 30, "[09:30 10:00 10:30 11:00]"},
+		{"range exactly one session long", periods(600, 660), 60, 30, "[10:00]"},
+		{"range too short", periods(600, 650), 60, 30, "[]"},
+		{"several ranges", periods(540, 600, 900, 1020), 60, 60, "[09:00 15:00 16:00]"},
+		{"sessions may run past midnight, but start on the date", periods(1320, 1560), 120, 60, "[22:00 23:00]"},
+		{"nothing free", nil, 60, 30, "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := startTimes(tt.free, tt.length, tt.interval)
+			if got == nil {
+				t.Fatalf("startTimes = nil, want an empty list for JSON")
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("startTimes = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClockTime(t *testing.T) {
+	tests := []struct {
+		minute int
+		want   string
+	}{
+		{0, "00:00"},
+		{545, "09:05"},
+		{1440, "00:00"}, // 24:00 is written as 00:00
+		{1500, "01:00"},
+		{-60, "23:00"},
+	}
+
+	for _, tt := range tests {
+		if got := clockTime(tt.minute); got != tt.want {
+			t.Errorf("clockTime(%d) = %s, want %s", tt.minute, got, tt.want)
+		}
+	}
+}
+
+func TestClipPeriods(t *testing.T) {
+	tests := []struct {
+		name    string
+		periods []database.OpenPeriod
+		want    []database.OpenPeriod
+	}{
+		{"inside the day", periods(540, 1320), periods(540, 1320)},
+		{"opened the day before", periods(-240, 120, 1200, 1560), periods(0, 120, 1200, 1440)},
+		{"around the clock", periods(-1440, 2880), periods(0, 1440)},
+		{"outside the day", periods(-240, 0, 1440, 1560), nil},
+		{"nothing", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clipPeriods(tt.periods, 0, 24*60); formatPeriods(got) != formatPeriods(tt.want) {
+				t.Errorf("clipPeriods = %s, want %s", formatPeriods(got), formatPeriods(tt.want))
+			}
+		})
+	}
+}
+
+func TestOccupancy(t *testing.T) {
+	tests := []struct {
+		booked, open int
+		want         float64
+	}{
+		{0, 780, 0},
+		{120, 780, 15.4},
+		{780, 780, 100},
+		{1, 3, 33.3},
+		{2, 3, 66.7},
+		{0, 0, 0}, // Closed all day
+	}
+
+	for _, tt := range tests {
+		if got := occupancy(tt.booked, tt.open); got != tt.want {
+			t.Errorf("occupancy(%d, %d) = %v, want %v", tt.booked, tt.open, got, tt.want)
+		}
+	}
+}
+
+func TestCalendarDay(t *testing.T) {
+	// An overnight studio with a booking from the night before, one running into the next day and a closure
+	schedule := testSchedule(database.DailyHours("20:00", "04:00"), 0, time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+		[]database.Booking{
+			testBooking(t, testDay.AddDate(0, 0, -1), "23:00", "02:00"),
+			testBooking(t, testDay, "23:00", "01:00"),
+		},
+		[]database.ClosureOccurrence{{Reason: "Cleaning", StartsAt: testDay.Add(20 * time.Hour), EndsAt: testDay.Add(21 * time.Hour)}},
+	)
+	day := schedule.day(testDay)
+
+	if got := fmt.Sprint(mapPeriodsToDTO(day.openHours())); got != "[{00:00 04:00} {20:00 00:00}]" {
+		t.Errorf("open hours = %s", got)
+	}
+	if got := fmt.Sprint(mapPeriodsToDTO(day.bookedHours())); got != "[{00:00 02:00} {23:00 00:00}]" {
+		t.Errorf("booked = %s", got)
+	}
+
+	openMinutes, bookedMinutes := day.usage()
+	// 4 hours after midnight and 3 of the 4 evening hours, the closure is not bookable
+	if openMinutes != 7*60 {
+		t.Errorf("open minutes = %d, want %d", openMinutes, 7*60)
+	}
+	if bookedMinutes != 3*60 {
+		t.Errorf("booked minutes = %d, want %d", bookedMinutes, 3*60)
+	}
+	if got := occupancy(bookedMinutes, openMinutes); got != 42.9 {
+		t.Errorf("occupancy = %v, want 42.9", got)
+	}
+}
+
+func TestGetAllStudiosFreeDuringValidation(t *testing.T) {
+	dbtest.LoadConfig()
+	svc := &studioService{}
+
+	// Rejected before the repository is reached, so no database is needed
+	short := time.Date(2000, 1, 1, 10, 0, 0, 0, time.UTC).Add(config.Get().BookingMinDuration - time.Minute)
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	tests := []struct {
+		name             string
+		date, start, end string
+	}{
+		{"date only", "2030-01-07", "", ""},
+		{"no end_time", "2030-01-07", "10:00", ""},
+		{"invalid date", "07-01-2030", "10:00", "12:00"},
+		{"invalid start_time", "2030-01-07", "10", "12:00"},
+		{"same start and end", "2030-01-07", "10:00", "10:00"},
+		{"shorter than the minimum", "2030-01-07", "10:00", short.Format("15:04")},
+		{"in the past", yesterday, "10:00", "12:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.GetAllStudios(context.Background(), dto.StudioFilterRequest{Date: tt.date, StartTime: tt.start, EndTime: tt.end})
+			var msgErr errs.MessageError
+			if !errors.As(err, &msgErr) || msgErr.Status() != http.StatusBadRequest {
+				t.Errorf("GetAllStudios = %v, want 400", err)
+			}
+		})
+	}
+}
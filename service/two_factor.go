@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/permission"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/token"
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/totp"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+    recoveryCodeCount    = 10
+    recoveryCodeLength   = 10                                 // Characters, shown as two groups of five
+    recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789" // No 0/o, 1/l/i to avoid typos
+    totpSkew             = 1                                  // Accept the previous and next 30s step for clock drift
+)
+
+// LoginTwoFactor - Second login step: exchange a challenge token and a TOTP or recovery code for tokens
+func (s *authService) LoginTwoFactor(ctx context.Context, req dto.LoginTwoFactorRequest, clientIP string) (*dto.LoginResponse, error) {
+    challenge, err := token.ValidateTwoFactorChallengeToken(req.ChallengeToken)
+    if err != nil {
+        return nil, errs.Unauthorized("invalid or expired challenge token")
+    }
+
+    used, err := s.revokedTokenRepo.IsRevoked(ctx, challenge.TokenID)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to verify challenge token")
+    }
+    if used {
+        return nil, errs.Unauthorized("invalid or expired challenge token")
+    }
+
+    user, err := s.authRepo.FindByID(ctx, challenge.UserID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.Unauthorized("invalid or expired challenge token")
+        }
+        return nil, errs.InternalServerError("failed to authenticate user")
+    }
+
+    if !user.IsActive {
+        return nil, errs.Forbidden("your account has been suspended")
+    }
+    if user.TOTPEnabledAt == nil {
+        return nil, errs.Unauthorized("invalid or expired challenge token")
+    }
+
+    // Wrong codes count towards the same lockout as wrong passwords
+    email := normalizeEmail(user.Email)
+    if err := s.checkLoginAllowed(ctx, email, clientIP); err != nil {
+        return nil, err
+    }
+
+    ok, err := s.verifySecondFactor(ctx, s.twoFactorRepo, user, req.Code, req.RecoveryCode)
+    if err != nil {
+        return nil, err
+    }
+    if !ok {
+        if lockErr := s.recordLoginFailure(ctx, email, clientIP, user); lockErr != nil {
+            return nil, lockErr
+        }
+        return nil, errs.Unauthorized("invalid two-factor code")
+    }
+    s.clearLoginFailures(ctx, email)
+
+    // The challenge is single use
+    if err := s.revokedTokenRepo.Create(ctx, &database.RevokedToken{
+        JTI:       challenge.TokenID,
+        UserID:    user.ID,
+        ExpiresAt: challenge.ExpiresAt,
+    }); err != nil {
+        return nil, errs.InternalServerError("failed to complete login")
+    }
+
+    familyID, err := generateRandomToken(16)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to generate authentication token")
+    }
+
+    loginData, err := s.issueTokens(ctx, user, familyID)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to generate authentication token")
+    }
+
+    return &dto.LoginResponse{
+        Success: true,
+        Message: "Login successful",
+        Data:    loginData,
+    }, nil
+}
+
+// GetTwoFactorStatus - Whether 2FA is on and how many recovery codes are left
+func (s *authService) GetTwoFactorStatus(ctx context.Context, userID int) (*dto.TwoFactorStatusResponse, error) {
+    user, err := s.findUserByID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    var remaining int64
+    if user.TOTPEnabledAt != nil {
+        remaining, err = s.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID)
+        if err != nil {
+            return nil, errs.InternalServerError("failed to fetch recovery codes")
+        }
+    }
+
+    return &dto.TwoFactorStatusResponse{
+        Success: true,
+        Data: dto.TwoFactorStatusData{
+            Enabled:                user.TOTPEnabledAt != nil,
+            Required:               isTwoFactorMandatory(user.Role),
+            RecoveryCodesRemaining: remaining,
+        },
+    }, nil
+}
+
+// EnrollTwoFactor - Generate a new secret; 2FA stays off until ConfirmTwoFactor
+func (s *authService) EnrollTwoFactor(ctx context.Context, userID int) (*dto.TwoFactorEnrollResponse, error) {
+    user, err := s.findUserByID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if user.TOTPEnabledAt != nil {
+        return nil, errs.BadRequest("two-factor authentication is already enabled")
+    }
+
+    secret, err := totp.GenerateSecret()
+    if err != nil {
+        return nil, errs.InternalServerError("failed to generate two-factor secret")
+    }
+
+    stored, err := totp.SealSecret(secret, config.Get().TOTPEncryptionKey)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to save two-factor secret")
+    }
+
+    if err := s.twoFactorRepo.SetPendingSecret(ctx, userID, stored); err != nil {
+        return nil, errs.InternalServerError("failed to save two-factor secret")
+    }
+
+    issuer := getEnv("APP_NAME", "Studio Booking System")
+
+    return &dto.TwoFactorEnrollResponse{
+        Success: true,
+        Message: "Scan the QR code with your authenticator app, then confirm with a code",
+        Data: dto.TwoFactorEnrollData{
+            Secret:     secret,
+            OTPAuthURI: totp.ProvisioningURI(issuer, user.Email, secret),
+        },
+    }, nil
+}
+
+// ConfirmTwoFactor - Turn 2FA on once the user proves the app is set up, and hand out recovery codes
+func (s *authService) ConfirmTwoFactor(ctx context.Context, userID int, req dto.TwoFactorCodeRequest) (*dto.TwoFactorRecoveryCodesResponse, error) {
+    user, err := s.findUserByID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if user.TOTPEnabledAt != nil {
+        return nil, errs.BadRequest("two-factor authentication is already enabled")
+    }
+    if user.TOTPSecret == "" {
+        return nil, errs.BadRequest("start two-factor enrollment first")
+    }
+
+    secret, err := openTOTPSecret(user)
+    if err != nil {
+        return nil, err
+    }
+
+    counter, ok := totp.Validate(secret, req.Code, time.Now(), totpSkew)
+    if !ok {
+        return nil, errs.BadRequest("invalid two-factor code")
+    }
+
+    codes, hashes, err := generateRecoveryCodes()
+    if err != nil {
+        return nil, err
+    }
+
+    // 2FA only turns on together with its recovery codes
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        if err := repos.TwoFactor.Enable(ctx, userID, counter); err != nil {
+            return errs.InternalServerError("failed to enable two-factor authentication")
+        }
+        if err := repos.TwoFactor.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+            return errs.InternalServerError("failed to save recovery codes")
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.TwoFactorRecoveryCodesResponse{
+        Success: true,
+        Message: "Two-factor authentication enabled. Store these recovery codes somewhere safe, they will not be shown again.",
+        Data:    dto.TwoFactorRecoveryCodesData{RecoveryCodes: codes},
+    }, nil
+}
+
+// RegenerateRecoveryCodes - Replace every recovery code with a fresh set
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID int, req dto.TwoFactorVerifyRequest) (*dto.TwoFactorRecoveryCodesResponse, error) {
+    user, err := s.findUserByID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if user.TOTPEnabledAt == nil {
+        return nil, errs.BadRequest("two-factor authentication is not enabled")
+    }
+
+    codes, hashes, err := generateRecoveryCodes()
+    if err != nil {
+        return nil, err
+    }
+
+    // A recovery code used to authorize this is only spent if the new set is saved
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        ok, err := s.verifySecondFactor(ctx, repos.TwoFactor, user, req.Code, req.RecoveryCode)
+        if err != nil {
+            return err
+        }
+        if !ok {
+            return errs.BadRequest("invalid two-factor code")
+        }
+
+        if err := repos.TwoFactor.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+            return errs.InternalServerError("failed to save recovery codes")
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.TwoFactorRecoveryCodesResponse{
+        Success: true,
+        Message: "New recovery codes generated. Previous codes no longer work.",
+        Data:    dto.TwoFactorRecoveryCodesData{RecoveryCodes: codes},
+    }, nil
+}
+
+// DisableTwoFactor - Turn 2FA off after re-checking password and a second factor
+func (s *authService) DisableTwoFactor(ctx context.Context, userID int, req dto.DisableTwoFactorRequest) (*dto.DisableTwoFactorResponse, error) {
+    user, err := s.findUserByID(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if user.TOTPEnabledAt == nil {
+        return nil, errs.BadRequest("two-factor authentication is not enabled")
+    }
+    if isTwoFactorMandatory(user.Role) {
+        return nil, errs.Forbidden("two-factor authentication is mandatory for your role")
+    }
+
+    if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+        return nil, errs.BadRequest("password is incorrect")
+    }
+
+    ok, err := s.verifySecondFactor(ctx, s.twoFactorRepo, user, req.Code, req.RecoveryCode)
+    if err != nil {
+        return nil, err
+    }
+    if !ok {
+        return nil, errs.BadRequest("invalid two-factor code")
+    }
+
+    if err := s.twoFactorRepo.Disable(ctx, userID); err != nil {
+        return nil, errs.InternalServerError("failed to disable two-factor authentication")
+    }
+
+    return &dto.DisableTwoFactorResponse{
+        Success: true,
+        Message: "Two-factor authentication disabled",
+    }, nil
+}
+
+// ============= HELPER FUNCTIONS =============
+
+// verifySecondFactor - Check a TOTP code (not reused) or consume a recovery code through repo
+func (s *authService) verifySecondFactor(ctx context.Context, repo contract.TwoFactorRepository, user *database.User, code, recoveryCode string) (bool, error) {
+    switch {
+    case code != "":
+        secret, err := openTOTPSecret(user)
+        if err != nil {
+            return false, err
+        }
+        counter, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+        if !ok {
+            return false, nil
+        }
+        accepted, err := repo.AcceptCounter(ctx, user.ID, counter)
+        if err != nil {
+            return false, errs.InternalServerError("failed to verify two-factor code")
+        }
+        return accepted, nil
+
+    case recoveryCode != "":
+        used, err := repo.UseRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(recoveryCode)))
+        if err != nil {
+            return false, errs.InternalServerError("failed to verify recovery code")
+        }
+        if used {
+            log.Printf("🔑 User #%d signed in with a recovery code", user.ID)
+        }
+        return used, nil
+
+    default:
+        return false, errs.BadRequest("code or recovery_code is required")
+    }
+}
+
+// generateRecoveryCodes - A new set of recovery codes in display form, with the hashes to store
+func generateRecoveryCodes() ([]string, []string, error) {
+    codes := make([]string, recoveryCodeCount)
+    hashes := make([]string, recoveryCodeCount)
+    for i := range codes {
+        code, err := generateRecoveryCode()
+        if err != nil {
+            return nil, nil, errs.InternalServerError("failed to generate recovery codes")
+        }
+        codes[i] = code
+        hashes[i] = hashToken(normalizeRecoveryCode(code))
+    }
+    return codes, hashes, nil
+}
+
+// openTOTPSecret - The user's TOTP secret, decrypted with TOTP_ENCRYPTION_KEY if it was stored encrypted
+func openTOTPSecret(user *database.User) (string, error) {
+    secret, err := totp.OpenSecret(user.TOTPSecret, config.Get().TOTPEncryptionKey)
+    if err != nil {
+        log.Printf("❌ Failed to read the two-factor secret of User #%d: %v", user.ID, err)
+        return "", errs.InternalServerError("failed to read two-factor secret")
+    }
+    return secret, nil
+}
+
+// findUserByID - Find user by ID and map errors
+func (s *authService) findUserByID(ctx context.Context, userID int) (*database.User, error) {
+    user, err := s.authRepo.FindByID(ctx, userID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("user not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch user")
+    }
+    return user, nil
+}
+
+// isTwoFactorMandatory - Whether config forces 2FA on accounts with this role
+func isTwoFactorMandatory(role string) bool {
+    return config.Get().RequireAdminTwoFactor && role == string(permission.RoleAdmin)
+}
+
+// generateRecoveryCode - Random code formatted as xxxxx-xxxxx
+func generateRecoveryCode() (string, error) {
+    alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+    var sb strings.Builder
+    for i := 0; i < recoveryCodeLength; i++ {
+        if i == recoveryCodeLength/2 {
+            sb.WriteByte('-')
+        }
+        n, err := rand.Int(rand.Reader, alphabetSize)
+        if err != nil {
+            return "", err
+        }
+        sb.WriteByte(recoveryCodeAlphabet[n.Int64()])
+    }
+    return sb.String(), nil
+}
+
+// normalizeRecoveryCode - Ignore case, spaces and dashes in a typed recovery code
+func normalizeRecoveryCode(code string) string {
+    code = strings.ToLower(code)
+    return strings.Map(func(r rune) rune {
+        if r == '-' || r == ' ' {
+            return -1
+        }
+        return r
+    }, code)
+}
\ No newline at end of file
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/RaFYWStud/BackendBookingStudio/config/pkg/errs"
+	"github.com/RaFYWStud/BackendBookingStudio/contract"
+	"github.com/RaFYWStud/BackendBookingStudio/database"
+	"github.com/RaFYWStud/BackendBookingStudio/dto"
+	"gorm.io/gorm"
+)
+
+type userService struct {
+    userRepo          contract.UserRepository
+    loginThrottleRepo contract.LoginThrottleRepository
+    unitOfWork        contract.UnitOfWork
+}
+
+func ImplUserService(
+    userRepo contract.UserRepository,
+    loginThrottleRepo contract.LoginThrottleRepository,
+    unitOfWork contract.UnitOfWork,
+) contract.UserService {
+    return &userService{
+        userRepo:          userRepo,
+        loginThrottleRepo: loginThrottleRepo,
+        unitOfWork:        unitOfWork,
+    }
+}
+
+// GetAllUsers - Admin list users with filters and pagination
+func (s *userService) GetAllUsers(ctx context.Context, filter dto.UserFilterRequest) (*dto.UserListResponse, error) {
+    // Set default pagination
+    if filter.Page < 1 {
+        filter.Page = 1
+    }
+    if filter.Limit < 1 {
+        filter.Limit = 10
+    }
+    if filter.Limit > 100 {
+        filter.Limit = 100 // Max limit
+    }
+
+    var createdFrom, createdTo *time.Time
+    if filter.CreatedFrom != "" {
+        from, err := time.Parse("2006-01-02", filter.CreatedFrom)
+        if err != nil {
+            return nil, errs.BadRequest("invalid created_from format, use YYYY-MM-DD")
+        }
+        createdFrom = &from
+    }
+    if filter.CreatedTo != "" {
+        to, err := time.Parse("2006-01-02", filter.CreatedTo)
+        if err != nil {
+            return nil, errs.BadRequest("invalid created_to format, use YYYY-MM-DD")
+        }
+        // Inclusive: everything before the start of the next day
+        to = to.AddDate(0, 0, 1)
+        createdTo = &to
+    }
+
+    users, total, err := s.userRepo.FindAll(ctx, filter, createdFrom, createdTo)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to fetch users")
+    }
+
+    userDTOs := make([]dto.AdminUserData, len(users))
+    for i, user := range users {
+        userDTOs[i] = mapAdminUserToDTO(&user)
+    }
+
+    totalPages := int(math.Ceil(float64(total) / float64(filter.Limit)))
+
+    return &dto.UserListResponse{
+        Success: true,
+        Data:    userDTOs,
+        Meta: dto.PaginationMeta{
+            CurrentPage: filter.Page,
+            PerPage:     filter.Limit,
+            Total:       total,
+            TotalPages:  totalPages,
+        },
+    }, nil
+}
+
+// GetUserDetail - Admin get user detail with booking summary
+func (s *userService) GetUserDetail(ctx context.Context, userID int) (*dto.UserDetailResponse, error) {
+    user, err := s.findUser(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    counts, err := s.userRepo.CountBookingsByStatus(ctx, userID)
+    if err != nil {
+        return nil, errs.InternalServerError("failed to fetch booking summary")
+    }
+
+    var summary dto.UserBookingSummary
+    for _, c := range counts {
+        summary.TotalBookings += c.Count
+        switch database.BookingStatus(c.Status) {
+        case database.BookingStatusPending:
+            summary.Pending = c.Count
+        case database.BookingStatusConfirmed:
+            summary.Confirmed = c.Count
+            summary.TotalSpent += c.TotalPrice
+        case database.BookingStatusCompleted:
+            summary.Completed = c.Count
+            summary.TotalSpent += c.TotalPrice
+        case database.BookingStatusCancelled:
+            summary.Cancelled = c.Count
+        case database.BookingStatusExpired:
+            summary.Expired = c.Count
+        }
+    }
+
+    data := dto.UserDetailData{
+        AdminUserData:  mapAdminUserToDTO(user),
+        BookingSummary: summary,
+    }
+
+    throttle, err := s.loginThrottleRepo.Find(ctx, database.LoginThrottleScopeEmail, normalizeEmail(user.Email))
+    if err != nil && err != gorm.ErrRecordNotFound {
+        return nil, errs.InternalServerError("failed to fetch login lock status")
+    }
+    if throttle != nil && throttle.LockedUntil != nil && time.Now().Before(*throttle.LockedUntil) {
+        data.LockedUntil = throttle.LockedUntil.Format("2006-01-02 15:04:05")
+    }
+
+    return &dto.UserDetailResponse{
+        Success: true,
+        Data:    data,
+    }, nil
+}
+
+// UpdateUserRole - Admin promote/demote user. Takes effect on the user's next request.
+func (s *userService) UpdateUserRole(ctx context.Context, adminID int, userID int, req dto.UpdateUserRoleRequest) (*dto.UpdateUserRoleResponse, error) {
+    if adminID == userID {
+        return nil, errs.BadRequest("you cannot change your own role")
+    }
+
+    user, err := s.findUser(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if user.Role == req.Role {
+        return nil, errs.BadRequest(fmt.Sprintf("user is already %s", req.Role))
+    }
+
+    if err := s.userRepo.UpdateRole(ctx, userID, req.Role); err != nil {
+        return nil, errs.InternalServerError("failed to update user role")
+    }
+    user.Role = req.Role
+
+    return &dto.UpdateUserRoleResponse{
+        Success: true,
+        Message: fmt.Sprintf("User role updated to %s", req.Role),
+        Data:    mapAdminUserToDTO(user),
+    }, nil
+}
+
+// SuspendUser - Admin suspend user account and revoke every session
+func (s *userService) SuspendUser(ctx context.Context, adminID int, userID int, req dto.SuspendUserRequest) (*dto.UpdateUserStatusResponse, error) {
+    if adminID == userID {
+        return nil, errs.BadRequest("you cannot suspend your own account")
+    }
+
+    user, err := s.findUser(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if !user.IsActive {
+        return nil, errs.BadRequest("user is already suspended")
+    }
+
+    // Auth middleware already rejects suspended users; also drop refresh tokens
+    // so the account stays logged out after being reactivated
+    err = s.unitOfWork.WithTx(ctx, func(repos *contract.Repository) error {
+        if err := repos.User.Suspend(ctx, userID, req.Reason); err != nil {
+            return err
+        }
+        if err := repos.Auth.IncrementTokenVersion(ctx, userID); err != nil {
+            return err
+        }
+        return repos.RefreshToken.RevokeAllByUserID(ctx, userID)
+    })
+    if err != nil {
+        return nil, errs.InternalServerError("failed to suspend user")
+    }
+
+    user, err = s.findUser(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.UpdateUserStatusResponse{
+        Success: true,
+        Message: "User has been suspended",
+        Data:    mapAdminUserToDTO(user),
+    }, nil
+}
+
+// UnsuspendUser - Admin reactivate suspended user account
+func (s *userService) UnsuspendUser(ctx context.Context, userID int) (*dto.UpdateUserStatusResponse, error) {
+    user, err := s.findUser(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if user.IsActive {
+        return nil, errs.BadRequest("user is not suspended")
+    }
+
+    if err := s.userRepo.Unsuspend(ctx, userID); err != nil {
+        return nil, errs.InternalServerError("failed to reactivate user")
+    }
+
+    user, err = s.findUser(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    return &dto.UpdateUserStatusResponse{
+        Success: true,
+        Message: "User has been reactivated",
+        Data:    mapAdminUserToDTO(user),
+    }, nil
+}
+
+// UnlockUser - Admin lift a login lockout caused by failed attempts
+func (s *userService) UnlockUser(ctx context.Context, userID int) (*dto.UpdateUserStatusResponse, error) {
+    user, err := s.findUser(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := s.loginThrottleRepo.Clear(ctx, database.LoginThrottleScopeEmail, normalizeEmail(user.Email)); err != nil {
+        return nil, errs.InternalServerError("failed to unlock user")
+    }
+
+    return &dto.UpdateUserStatusResponse{
+        Success: true,
+        Message: "User login has been unlocked",
+        Data:    mapAdminUserToDTO(user),
+    }, nil
+}
+
+// ============= HELPER FUNCTIONS =============
+
+// findUser - Find user by ID and map errors
+func (s *userService) findUser(ctx context.Context, userID int) (*database.User, error) {
+    user, err := s.userRepo.FindByID(ctx, userID)
+    if err != nil {
+        if err == gorm.ErrRecordNotFound {
+            return nil, errs.NotFound("user not found")
+        }
+        return nil, errs.InternalServerError("failed to fetch user")
+    }
+    return user, nil
+}
+
+// mapAdminUserToDTO - Map user model to admin user data
+func mapAdminUserToDTO(user *database.User) dto.AdminUserData {
+    data := dto.AdminUserData{
+        ID:               user.ID,
+        Name:             user.Name,
+        Email:            user.Email,
+        Role:             user.Role,
+        EmailVerified:    user.EmailVerifiedAt != nil,
+        TwoFactorEnabled: user.TOTPEnabledAt != nil,
+        IsActive:         user.IsActive,
+        SuspendReason:    user.SuspendReason,
+        CreatedAt:        user.CreatedAt.Format("2006-01-02 15:04:05"),
+        UpdatedAt:        user.UpdatedAt.Format("2006-01-02 15:04:05"),
+    }
+
+    if user.SuspendedAt != nil {
+        data.SuspendedAt = user.SuspendedAt.Format("2006-01-02 15:04:05")
+    }
+
+    return data
+}
\ No newline at end of file